@@ -136,6 +136,10 @@ var printAddonsJSON = func(cc *config.ClusterConfig) {
 			"Status":  stringFromStatus(enabled),
 			"Profile": cc.Name,
 		}
+		if len(addonBundle.Values) > 0 {
+			addonsMap[addonName]["Values"] = addonBundle.ResolveValues(cc)
+			addonsMap[addonName]["Schema"] = addonBundle.Values
+		}
 	}
 	jsonString, _ := json.Marshal(addonsMap)
 
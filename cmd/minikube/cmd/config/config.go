@@ -175,6 +175,11 @@ var settings = []Setting{
 		name: "native-ssh",
 		set:  SetBool,
 	},
+	{
+		name:        config.ProblemsURL,
+		set:         SetString,
+		validations: []setFn{IsValidURL},
+	},
 }
 
 // ConfigCmd represents the config command
@@ -19,10 +19,17 @@ package config
 import (
 	"github.com/spf13/cobra"
 	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
+var (
+	addonValues string
+	addonWait   bool
+)
+
 var addonsEnableCmd = &cobra.Command{
 	Use:   "enable ADDON_NAME",
 	Short: "Enables the addon w/ADDON_NAME within minikube (example: minikube addons enable dashboard). For a list of available addons use: minikube addons list ",
@@ -32,14 +39,33 @@ var addonsEnableCmd = &cobra.Command{
 			exit.UsageT("usage: minikube addons enable ADDON_NAME")
 		}
 		addon := args[0]
-		err := addons.SetAndSave(ClusterFlagValue(), addon, "true")
+		var values map[string]string
+		if addonValues != "" {
+			var err error
+			values, err = assets.ParseValues(addonValues)
+			if err != nil {
+				exit.UsageT("invalid --set: {{.error}}", out.V{"error": err})
+			}
+		}
+		err := addons.EnableAndSave(ClusterFlagValue(), addon, values)
 		if err != nil {
 			exit.WithError("enable failed", err)
 		}
 		out.T(out.AddonEnable, "The '{{.addonName}}' addon is enabled", out.V{"addonName": addon})
+
+		if addonWait {
+			_, cc := mustload.Partial(ClusterFlagValue())
+			out.T(out.Waiting, "Verifying the '{{.addonName}}' addon is healthy ...", out.V{"addonName": addon})
+			if err := addons.Verify(cc, addon, addons.VerifyTimeout); err != nil {
+				exit.WithCodeT(exit.Unavailable, "The '{{.addonName}}' addon did not become healthy: {{.error}}", out.V{"addonName": addon, "error": err})
+			}
+			out.T(out.Check, "The '{{.addonName}}' addon is healthy", out.V{"addonName": addon})
+		}
 	},
 }
 
 func init() {
+	addonsEnableCmd.Flags().StringVar(&addonValues, "set", "", "Comma separated list of key=value settings for the addon, as listed by 'minikube addons list -o json'")
+	addonsEnableCmd.Flags().BoolVar(&addonWait, "wait", false, "Wait for the workloads of the addon, and of the addons it requires, to become healthy")
 	AddonsCmd.AddCommand(addonsEnableCmd)
 }
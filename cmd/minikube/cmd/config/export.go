@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var exportFormat string
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the configuration of a cluster as a cluster spec",
+	Long:  "Export the configuration of a cluster as a YAML or JSON cluster spec, which can be passed to 'minikube start --config-file' to reproduce the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.UsageT("Usage: minikube config export")
+		}
+
+		format := strings.ToLower(exportFormat)
+		if format != "yaml" && format != "json" {
+			exit.WithCodeT(exit.BadUsage, fmt.Sprintf("invalid output format: %s. Valid values: 'yaml', 'json'", exportFormat))
+		}
+
+		_, cc := mustload.Partial(ClusterFlagValue())
+		data, err := config.NewSpec(*cc).Marshal(format)
+		if err != nil {
+			exit.WithError("Failed to export cluster spec", err)
+		}
+		out.Ln("%s", strings.TrimSpace(string(data)))
+	},
+}
+
+func init() {
+	configExportCmd.Flags().StringVarP(&exportFormat, "output", "o", "yaml", "The output format. One of 'yaml', 'json'")
+	ConfigCmd.AddCommand(configExportCmd)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var addonsInstallCmd = &cobra.Command{
+	Use:   "install SOURCE",
+	Short: "Installs a user-defined addon from a directory, archive, URL or git repository containing an addon.yaml",
+	Long: `Installs a user-defined addon from a directory, archive, URL or git repository containing an addon.yaml, such as:
+
+	minikube addons install ./my-addon
+	minikube addons install git::https://github.com/example/my-addon.git
+
+Once installed, the addon can be enabled with: minikube addons enable ADDON_NAME`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube addons install SOURCE")
+		}
+		m, err := addons.Install(args[0])
+		if err != nil {
+			exit.WithError("install failed", err)
+		}
+		out.T(out.AddonEnable, "The '{{.addonName}}' addon is installed, and can now be enabled", out.V{"addonName": m.Name})
+	},
+}
+
+func init() {
+	AddonsCmd.AddCommand(addonsInstallCmd)
+}
@@ -40,6 +40,21 @@ func run(name string, value string, fns []setFn) error {
 	return nil
 }
 
+// Validate runs the validations of a setting against a value
+func Validate(name string, value string) error {
+	s, err := findSetting(name)
+	if err != nil {
+		return err
+	}
+	return run(name, value, s.validations)
+}
+
+// IsSetting returns whether a name is a setting of 'minikube config'
+func IsSetting(name string) bool {
+	_, err := findSetting(name)
+	return err == nil
+}
+
 func findSetting(name string) (Setting, error) {
 	for _, s := range settings {
 		if name == s.name {
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/forward"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/shell"
+)
+
+var containerEnvTmpl = fmt.Sprintf("{{ if .ContainerdAddress }}{{ .Prefix }}%s{{ .Delimiter }}{{ .ContainerdAddress }}{{ .Suffix }}{{ .Prefix }}%s{{ .Delimiter }}{{ .ContainerdNamespace }}{{ .Suffix }}{{ end }}{{ .Prefix }}%s{{ .Delimiter }}{{ .RuntimeEndpoint }}{{ .Suffix }}{{ .Prefix }}%s{{ .Delimiter }}{{ .ImageEndpoint }}{{ .Suffix }}{{ .Prefix }}%s{{ .Delimiter }}{{ .MinikubeContainerRuntimeProfile }}{{ .Suffix }}{{ .UsageHint }}", constants.ContainerdAddressEnv, constants.ContainerdNamespaceEnv, constants.ContainerRuntimeEndpointEnv, constants.ImageServiceEndpointEnv, constants.MinikubeActiveContainerRuntimeEnv)
+
+// containerdNamespace is the containerd namespace of the containers and images of Kubernetes
+const containerdNamespace = "k8s.io"
+
+// ContainerShellConfig represents the shell config for the socket of a container runtime
+type ContainerShellConfig struct {
+	shell.Config
+	ContainerdAddress               string
+	ContainerdNamespace             string
+	RuntimeEndpoint                 string
+	ImageEndpoint                   string
+	MinikubeContainerRuntimeProfile string
+}
+
+var (
+	containerUnset bool
+)
+
+// containerShellCfgSet generates context variables for "container-env"
+func containerShellCfgSet(ec ContainerEnvConfig, envMap map[string]string) *ContainerShellConfig {
+	const usgPlz = "To point your shell to minikube's container runtime, run:"
+	var usgCmd = fmt.Sprintf("minikube -p %s container-env", ec.profile)
+	s := &ContainerShellConfig{
+		Config: *shell.CfgSet(ec.EnvConfig, usgPlz, usgCmd),
+	}
+	s.ContainerdAddress = envMap[constants.ContainerdAddressEnv]
+	s.ContainerdNamespace = envMap[constants.ContainerdNamespaceEnv]
+	s.RuntimeEndpoint = envMap[constants.ContainerRuntimeEndpointEnv]
+	s.ImageEndpoint = envMap[constants.ImageServiceEndpointEnv]
+	s.MinikubeContainerRuntimeProfile = envMap[constants.MinikubeActiveContainerRuntimeEnv]
+
+	return s
+}
+
+// containerEnvCmd represents the container-env command
+var containerEnvCmd = &cobra.Command{
+	Use:   "container-env",
+	Short: "Configure environment to use minikube's container runtime",
+	Long: `Sets up env variables to use the container runtime of minikube from the host, whichever it is.
+
+For the docker runtime, these are the variables of 'minikube docker-env', which point the docker CLI to the Docker daemon over TCP and TLS.
+For the containerd and cri-o runtimes, the socket of the runtime is forwarded to a Unix socket on the host over SSH, by a process running
+in the background, and the variables point nerdctl (containerd only) and crictl to it. --unset stops the process.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+		driverName := co.CP.Host.DriverName
+
+		if driverName == driver.None {
+			exit.UsageT(`'none' driver does not support 'minikube container-env' command`)
+		}
+
+		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: co.CP.Runner, Socket: co.Config.KubernetesConfig.CRISocket})
+		if err != nil {
+			exit.WithError("Failed runtime", err)
+		}
+
+		if cr.Name() == "Docker" {
+			ec := mustDockerEnvConfig(cname, co, "container-env")
+			if containerUnset {
+				if err := dockerUnsetScript(ec, os.Stdout); err != nil {
+					exit.WithError("Error generating unset output", err)
+				}
+				return
+			}
+			if err := dockerSetScript(ec, os.Stdout); err != nil {
+				exit.WithError("Error generating set output", err)
+			}
+			return
+		}
+
+		if !cr.Active() {
+			exit.WithCodeT(exit.Unavailable, `The {{.runtime}} service within '{{.cluster}}' is not active`, out.V{"runtime": cr.Name(), "cluster": cname})
+		}
+
+		sh := shell.EnvConfig{
+			Shell: shell.ForceShell,
+		}
+		ec := ContainerEnvConfig{
+			EnvConfig:  sh,
+			profile:    cname,
+			socket:     forward.SocketPath(cname),
+			containerd: cr.Name() == "containerd",
+		}
+
+		if ec.Shell == "" {
+			ec.Shell, err = shell.Detect()
+			if err != nil {
+				exit.WithError("Error detecting shell", err)
+			}
+		}
+
+		if containerUnset {
+			if err := forward.Stop(cname); err != nil {
+				exit.WithError("Error stopping the socket forwarder", err)
+			}
+			if err := containerUnsetScript(ec, os.Stdout); err != nil {
+				exit.WithError("Error generating unset output", err)
+			}
+			return
+		}
+
+		if err := forward.Start(cname, cr.SocketPath()); err != nil {
+			exit.WithError("Error forwarding the socket of the container runtime", err)
+		}
+		if err := containerSetScript(ec, os.Stdout); err != nil {
+			exit.WithError("Error generating set output", err)
+		}
+	},
+}
+
+// ContainerEnvConfig encapsulates all external inputs into shell generation for the socket of a container runtime
+type ContainerEnvConfig struct {
+	shell.EnvConfig
+	profile string
+	// socket is the path of the socket on the host that is forwarded to the runtime
+	socket     string
+	containerd bool
+}
+
+// containerSetScript writes out a shell-compatible 'container-env' script
+func containerSetScript(ec ContainerEnvConfig, w io.Writer) error {
+	envVars := containerEnvVars(ec)
+	return shell.SetScript(ec.EnvConfig, w, containerEnvTmpl, containerShellCfgSet(ec, envVars))
+}
+
+// containerUnsetScript writes out a shell-compatible 'container-env --unset' script
+func containerUnsetScript(ec ContainerEnvConfig, w io.Writer) error {
+	vars := []string{}
+	if ec.containerd {
+		vars = append(vars, constants.ContainerdAddressEnv, constants.ContainerdNamespaceEnv)
+	}
+	vars = append(vars,
+		constants.ContainerRuntimeEndpointEnv,
+		constants.ImageServiceEndpointEnv,
+		constants.MinikubeActiveContainerRuntimeEnv,
+	)
+	return shell.UnsetScript(ec.EnvConfig, w, vars)
+}
+
+// containerEnvVars gets the necessary env variables to allow the use of the socket of minikube's container runtime
+func containerEnvVars(ec ContainerEnvConfig) map[string]string {
+	endpoint := fmt.Sprintf("unix://%s", ec.socket)
+	env := map[string]string{
+		constants.ContainerRuntimeEndpointEnv:       endpoint,
+		constants.ImageServiceEndpointEnv:           endpoint,
+		constants.MinikubeActiveContainerRuntimeEnv: ec.profile,
+	}
+	if ec.containerd {
+		env[constants.ContainerdAddressEnv] = ec.socket
+		env[constants.ContainerdNamespaceEnv] = containerdNamespace
+	}
+	return env
+}
+
+func init() {
+	containerEnvCmd.Flags().BoolVar(&noProxy, "no-proxy", false, "Add machine IP to NO_PROXY environment variable, for the docker runtime")
+	containerEnvCmd.Flags().StringVar(&shell.ForceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	containerEnvCmd.Flags().BoolVarP(&containerUnset, "unset", "u", false, "Unset variables instead of setting them")
+}
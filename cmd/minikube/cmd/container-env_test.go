@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateContainerScripts(t *testing.T) {
+	var tests = []struct {
+		shell     string
+		config    ContainerEnvConfig
+		wantSet   string
+		wantUnset string
+	}{
+		{
+			"bash",
+			ContainerEnvConfig{profile: "containerd", socket: "/home/user/.minikube/profiles/containerd/runtime.sock", containerd: true},
+			`export CONTAINERD_ADDRESS="/home/user/.minikube/profiles/containerd/runtime.sock"
+export CONTAINERD_NAMESPACE="k8s.io"
+export CONTAINER_RUNTIME_ENDPOINT="unix:///home/user/.minikube/profiles/containerd/runtime.sock"
+export IMAGE_SERVICE_ENDPOINT="unix:///home/user/.minikube/profiles/containerd/runtime.sock"
+export MINIKUBE_ACTIVE_CONTAINER_RUNTIME="containerd"
+
+# To point your shell to minikube's container runtime, run:
+# eval $(minikube -p containerd container-env)
+`,
+			`unset CONTAINERD_ADDRESS CONTAINERD_NAMESPACE CONTAINER_RUNTIME_ENDPOINT IMAGE_SERVICE_ENDPOINT MINIKUBE_ACTIVE_CONTAINER_RUNTIME
+`,
+		},
+		{
+			"fish",
+			ContainerEnvConfig{profile: "crio", socket: "/home/user/.minikube/profiles/crio/runtime.sock"},
+			`set -gx CONTAINER_RUNTIME_ENDPOINT "unix:///home/user/.minikube/profiles/crio/runtime.sock";
+set -gx IMAGE_SERVICE_ENDPOINT "unix:///home/user/.minikube/profiles/crio/runtime.sock";
+set -gx MINIKUBE_ACTIVE_CONTAINER_RUNTIME "crio";
+
+# To point your shell to minikube's container runtime, run:
+# minikube -p crio container-env | source
+`,
+			`set -e CONTAINER_RUNTIME_ENDPOINT;
+set -e IMAGE_SERVICE_ENDPOINT;
+set -e MINIKUBE_ACTIVE_CONTAINER_RUNTIME;
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.config.profile, func(t *testing.T) {
+			tc.config.EnvConfig.Shell = tc.shell
+			var b []byte
+			buf := bytes.NewBuffer(b)
+			if err := containerSetScript(tc.config, buf); err != nil {
+				t.Errorf("setScript(%+v) error: %v", tc.config, err)
+			}
+			got := buf.String()
+			if diff := cmp.Diff(tc.wantSet, got); diff != "" {
+				t.Errorf("setScript(%+v) mismatch (-want +got):\n%s\n\nraw output:\n%s\nquoted: %q", tc.config, diff, got, got)
+			}
+
+			buf = bytes.NewBuffer(b)
+			if err := containerUnsetScript(tc.config, buf); err != nil {
+				t.Errorf("unsetScript(%+v) error: %v", tc.config, err)
+			}
+			got = buf.String()
+			if diff := cmp.Diff(tc.wantUnset, got); diff != "" {
+				t.Errorf("unsetScript(%+v) mismatch (-want +got):\n%s\n\nraw output:\n%s\nquoted: %q", tc.config, diff, got, got)
+			}
+		})
+	}
+}
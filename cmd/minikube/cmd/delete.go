@@ -40,10 +40,12 @@ import (
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/forward"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/schedule"
 )
 
 var deleteAll bool
@@ -305,10 +307,18 @@ func deleteProfile(profile *config.Profile) error {
 		}
 	}
 
-	if err := killMountProcess(); err != nil {
+	if err := killMountProcess(profile.Name); err != nil {
 		out.FailureT("Failed to kill mount process: {{.error}}", out.V{"error": err})
 	}
 
+	if err := schedule.Cancel(profile.Name); err != nil {
+		out.FailureT("Failed to cancel scheduled stop: {{.error}}", out.V{"error": err})
+	}
+
+	if err := forward.Stop(profile.Name); err != nil {
+		out.FailureT("Failed to stop socket forwarder: {{.error}}", out.V{"error": err})
+	}
+
 	deleteHosts(api, cc)
 
 	// In case DeleteHost didn't complete the job.
@@ -478,9 +488,9 @@ func deleteProfileDirectory(profile string) {
 	}
 }
 
-// killMountProcess kills the mount process, if it is running
-func killMountProcess() error {
-	pidPath := filepath.Join(localpath.MiniPath(), constants.MountProcessFileName)
+// killMountProcess kills the mount process of a profile, if it is running
+func killMountProcess(profile string) error {
+	pidPath := filepath.Join(localpath.Profile(profile), constants.MountProcessFileName)
 	if _, err := os.Stat(pidPath); os.IsNotExist(err) {
 		return nil
 	}
@@ -73,7 +73,11 @@ type EnvNoProxyGetter struct{}
 func dockerShellCfgSet(ec DockerEnvConfig, envMap map[string]string) *DockerShellConfig {
 	profile := ec.profile
 	const usgPlz = "To point your shell to minikube's docker-daemon, run:"
-	var usgCmd = fmt.Sprintf("minikube -p %s docker-env", profile)
+	command := ec.command
+	if command == "" {
+		command = "docker-env"
+	}
+	var usgCmd = fmt.Sprintf("minikube -p %s %s", profile, command)
 	s := &DockerShellConfig{
 		Config: *shell.CfgSet(ec.EnvConfig, usgPlz, usgCmd),
 	}
@@ -146,48 +150,7 @@ var dockerEnvCmd = &cobra.Command{
 				out.V{"runtime": co.Config.KubernetesConfig.ContainerRuntime})
 		}
 
-		sh := shell.EnvConfig{
-			Shell: shell.ForceShell,
-		}
-
-		if ok := isDockerActive(co.CP.Runner); !ok {
-			glog.Warningf("dockerd is not active will try to restart it...")
-			mustRestartDocker(cname, co.CP.Runner)
-		}
-
-		var err error
-		port := constants.DockerDaemonPort
-		if driver.NeedsPortForward(driverName) {
-			port, err = oci.ForwardedPort(driverName, cname, port)
-			if err != nil {
-				exit.WithCodeT(exit.Failure, "Error getting port binding for '{{.driver_name}} driver: {{.error}}", out.V{"driver_name": driverName, "error": err})
-			}
-		}
-
-		ec := DockerEnvConfig{
-			EnvConfig: sh,
-			profile:   cname,
-			driver:    driverName,
-			hostIP:    co.CP.IP.String(),
-			port:      port,
-			certsDir:  localpath.MakeMiniPath("certs"),
-			noProxy:   noProxy,
-		}
-
-		if ec.Shell == "" {
-			ec.Shell, err = shell.Detect()
-			if err != nil {
-				exit.WithError("Error detecting shell", err)
-			}
-		}
-
-		out, err := tryDockerConnectivity("docker", ec)
-		if err != nil { // docker might be up but been loaded with wrong certs/config
-			// to fix issues like this #8185
-			glog.Warningf("couldn't connect to docker inside minikube. will try to restart dockerd service... output: %s error: %v", string(out), err)
-			mustRestartDocker(cname, co.CP.Runner)
-		}
-
+		ec := mustDockerEnvConfig(cname, co, "docker-env")
 		if dockerUnset {
 			if err := dockerUnsetScript(ec, os.Stdout); err != nil {
 				exit.WithError("Error generating unset output", err)
@@ -201,9 +164,61 @@ var dockerEnvCmd = &cobra.Command{
 	},
 }
 
+// mustDockerEnvConfig returns the inputs of the script of a minikube command pointing a shell to the Docker daemon of a running cluster,
+// restarting the daemon if it is unreachable
+func mustDockerEnvConfig(cname string, co mustload.ClusterController, command string) DockerEnvConfig {
+	driverName := co.CP.Host.DriverName
+	sh := shell.EnvConfig{
+		Shell: shell.ForceShell,
+	}
+
+	if ok := isDockerActive(co.CP.Runner); !ok {
+		glog.Warningf("dockerd is not active will try to restart it...")
+		mustRestartDocker(cname, co.CP.Runner)
+	}
+
+	var err error
+	port := constants.DockerDaemonPort
+	if driver.NeedsPortForward(driverName) {
+		port, err = oci.ForwardedPort(driverName, cname, port)
+		if err != nil {
+			exit.WithCodeT(exit.Failure, "Error getting port binding for '{{.driver_name}} driver: {{.error}}", out.V{"driver_name": driverName, "error": err})
+		}
+	}
+
+	ec := DockerEnvConfig{
+		EnvConfig: sh,
+		command:   command,
+		profile:   cname,
+		driver:    driverName,
+		hostIP:    co.CP.IP.String(),
+		port:      port,
+		certsDir:  localpath.MakeMiniPath("certs"),
+		noProxy:   noProxy,
+	}
+
+	if ec.Shell == "" {
+		ec.Shell, err = shell.Detect()
+		if err != nil {
+			exit.WithError("Error detecting shell", err)
+		}
+	}
+
+	out, err := tryDockerConnectivity("docker", ec)
+	if err != nil { // docker might be up but been loaded with wrong certs/config
+		// to fix issues like this #8185
+		glog.Warningf("couldn't connect to docker inside minikube. will try to restart dockerd service... output: %s error: %v", string(out), err)
+		mustRestartDocker(cname, co.CP.Runner)
+	}
+
+	return ec
+}
+
 // DockerEnvConfig encapsulates all external inputs into shell generation for Docker
 type DockerEnvConfig struct {
 	shell.EnvConfig
+	// command is the minikube command generating the script, docker-env if empty
+	command  string
 	profile  string
 	driver   string
 	hostIP   string
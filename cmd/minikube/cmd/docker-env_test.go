@@ -51,6 +51,21 @@ export MINIKUBE_ACTIVE_DOCKERD="dockerdrver"
 
 # To point your shell to minikube's docker-daemon, run:
 # eval $(minikube -p dockerdrver docker-env)
+`,
+			`unset DOCKER_TLS_VERIFY DOCKER_HOST DOCKER_CERT_PATH MINIKUBE_ACTIVE_DOCKERD
+`,
+		},
+		{
+			"bash",
+			DockerEnvConfig{command: "container-env", profile: "containerenv", driver: "kvm2", hostIP: "127.0.0.1", port: 2376, certsDir: "/certs"},
+			nil,
+			`export DOCKER_TLS_VERIFY="1"
+export DOCKER_HOST="tcp://127.0.0.1:2376"
+export DOCKER_CERT_PATH="/certs"
+export MINIKUBE_ACTIVE_DOCKERD="containerenv"
+
+# To point your shell to minikube's docker-daemon, run:
+# eval $(minikube -p containerenv container-env)
 `,
 			`unset DOCKER_TLS_VERIFY DOCKER_HOST DOCKER_CERT_PATH MINIKUBE_ACTIVE_DOCKERD
 `,
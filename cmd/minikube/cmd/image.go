@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var imageTag string
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage images inside a node of the cluster",
+	Long:  "List, load, remove, pull or build images inside a node of the cluster, using its container runtime",
+}
+
+// imageListCmd represents the image ls command
+var imageListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List images in the node.",
+	Long:  "Lists the images known to the container runtime of the node.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.UsageT("Usage: minikube image ls")
+		}
+
+		_, cr, _ := imageRuntime()
+		images, err := cr.ListImages()
+		if err != nil {
+			exit.WithError("Failed to list images", err)
+		}
+		for _, img := range images {
+			out.Ln("%s", img)
+		}
+	},
+}
+
+// imageLoadCmd represents the image load command
+var imageLoadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Load an image into the node.",
+	Long:  "Loads an image tarball, or an image from the local docker daemon, into the container runtime of the node. Unlike 'minikube cache add', the image is not added to the cache of every cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("Usage: minikube image load [image or tarball]...")
+		}
+
+		r, _, cc := imageRuntime()
+		for _, img := range args {
+			out.T(out.Copying, "Loading image {{.image}} ...", out.V{"image": img})
+			var err error
+			if _, serr := os.Stat(img); serr == nil {
+				err = machine.LoadImageFile(r, cc.KubernetesConfig, img)
+			} else {
+				err = machine.LoadDaemonImage(r, cc.KubernetesConfig, img)
+			}
+			if err != nil {
+				exit.WithError("Failed to load image", err)
+			}
+		}
+	},
+}
+
+// imageRemoveCmd represents the image rm command
+var imageRemoveCmd = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove images from the node.",
+	Long:  "Removes images from the container runtime of the node.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("Usage: minikube image rm [image]...")
+		}
+
+		_, cr, _ := imageRuntime()
+		for _, img := range args {
+			if err := cr.RemoveImage(img); err != nil {
+				exit.WithError("Failed to remove image", err)
+			}
+			out.T(out.Deleted, "Removed image {{.image}}", out.V{"image": img})
+		}
+	},
+}
+
+// imagePullCmd represents the image pull command
+var imagePullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull images into the node.",
+	Long:  "Pulls images from their registry, using the container runtime of the node.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("Usage: minikube image pull [image]...")
+		}
+
+		_, cr, _ := imageRuntime()
+		for _, img := range args {
+			out.T(out.Pulling, "Pulling image {{.image}} ...", out.V{"image": img})
+			if err := cr.PullImage(img); err != nil {
+				exit.WithError("Failed to pull image", err)
+			}
+		}
+	},
+}
+
+// imageBuildCmd represents the image build command
+var imageBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build an image in the node.",
+	Long:  "Copies a local build context directory to the node, and builds an image from it using the container runtime of the node: docker build, podman build for cri-o, or buildkit for containerd.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube image build [directory] --tag [image]")
+		}
+		if imageTag == "" {
+			exit.UsageT("Please specify the name of the image to build with --tag")
+		}
+		if fi, err := os.Stat(args[0]); err != nil || !fi.IsDir() {
+			exit.UsageT("Build context {{.path}} is not a directory", out.V{"path": args[0]})
+		}
+
+		r, _, cc := imageRuntime()
+		out.T(out.Provisioning, "Building image {{.image}} from {{.path}} ...", out.V{"image": imageTag, "path": args[0]})
+		if err := machine.BuildImage(r, cc.KubernetesConfig, args[0], imageTag); err != nil {
+			if errors.Is(err, cruntime.ErrBuildNotSupported) {
+				exit.WithCodeT(exit.Unavailable, "Building images with the {{.runtime}} container runtime requires buildkit, which this node lacks. Build the image on the host and use 'minikube image load', or update the minikube ISO.", out.V{"runtime": cc.KubernetesConfig.ContainerRuntime})
+			}
+			exit.WithError("Failed to build image", err)
+		}
+		out.T(out.Check, "Built image {{.image}}", out.V{"image": imageTag})
+	},
+}
+
+// imageRuntime returns the command runner and container runtime of the node selected with --node,
+// which defaults to the primary control plane.
+func imageRuntime() (command.Runner, cruntime.Manager, *config.ClusterConfig) {
+	co := mustload.Running(ClusterFlagValue())
+
+	r := co.CP.Runner
+	if nodeName != "" {
+		n, _, err := node.Retrieve(*co.Config, nodeName)
+		if err != nil {
+			exit.WithCodeT(exit.Unavailable, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+		}
+		h, err := machine.LoadHost(co.API, driver.MachineName(*co.Config, *n))
+		if err != nil {
+			exit.WithError("Error getting host", err)
+		}
+		r, err = machine.CommandRunner(h)
+		if err != nil {
+			exit.WithError("Failed to get command runner", err)
+		}
+	}
+
+	cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: r})
+	if err != nil {
+		exit.WithError("Failed runtime", err)
+	}
+	return r, cr, co.Config
+}
+
+func init() {
+	imageBuildCmd.Flags().StringVarP(&imageTag, "tag", "t", "", "The name of the image to build, for example 'my-app:latest'")
+	for _, c := range []*cobra.Command{imageListCmd, imageLoadCmd, imageRemoveCmd, imagePullCmd, imageBuildCmd} {
+		c.Flags().StringVarP(&nodeName, "node", "n", "", "The node to use. Defaults to the primary control plane.")
+		imageCmd.AddCommand(c)
+	}
+}
@@ -18,15 +18,22 @@ package cmd
 
 import (
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/logs"
+	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
@@ -43,6 +50,18 @@ var (
 	numberOfLines int
 	// showProblems only shows lines that match known issues
 	showProblems bool
+	// bundleLogs writes a support bundle instead of showing logs
+	bundleLogs bool
+	// logsSince only shows the entries of this recent period, set via --since
+	logsSince time.Duration
+	// logsComponents only shows the logs of these components
+	logsComponents []string
+	// logsPods shows the logs of the containers of these pods
+	logsPods []string
+	// logsLevel only shows the entries of this severity or higher
+	logsLevel string
+	// logsOutput is the output format, text or json
+	logsOutput string
 )
 
 // logsCmd represents the logs command
@@ -51,30 +70,54 @@ var logsCmd = &cobra.Command{
 	Short: "Returns logs to debug a local Kubernetes cluster",
 	Long:  `Gets the logs of the running instance, used for debugging minikube, not user code.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if bundleLogs {
+			writeBundle(ClusterFlagValue())
+			return
+		}
+
+		q := logsQuery(cmd)
 		co := mustload.Running(ClusterFlagValue())
 
-		bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *co.Config, co.CP.Runner)
+		n, runner := co.CP.Node, co.CP.Runner
+		if nodeName != "" {
+			var err error
+			n, _, err = node.Retrieve(*co.Config, nodeName)
+			if err != nil {
+				exit.WithCodeT(exit.Unavailable, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+			}
+			h, err := machine.LoadHost(co.API, driver.MachineName(*co.Config, *n))
+			if err != nil {
+				exit.WithError("Error getting host", err)
+			}
+			runner, err = machine.CommandRunner(h)
+			if err != nil {
+				exit.WithError("Failed to get command runner", err)
+			}
+		}
+		q.Node = driver.MachineName(*co.Config, *n)
+
+		bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *co.Config, runner)
 		if err != nil {
 			exit.WithError("Error getting cluster bootstrapper", err)
 		}
 
-		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: co.CP.Runner})
+		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: runner})
 		if err != nil {
 			exit.WithError("Unable to get runtime", err)
 		}
 		if followLogs {
-			err := logs.Follow(cr, bs, *co.Config, co.CP.Runner)
+			err := logs.Follow(cr, bs, *co.Config, runner, q)
 			if err != nil {
 				exit.WithError("Follow", err)
 			}
 			return
 		}
 		if showProblems {
-			problems := logs.FindProblems(cr, bs, *co.Config, co.CP.Runner)
+			problems := logs.FindProblems(cr, bs, *co.Config, runner)
 			logs.OutputProblems(problems, numberOfProblems)
 			return
 		}
-		err = logs.Output(cr, bs, *co.Config, co.CP.Runner, numberOfLines)
+		err = logs.Output(cr, bs, *co.Config, runner, q)
 		if err != nil {
 			out.Ln("")
 			// Avoid exit.WithError, since it outputs the issue URL
@@ -84,9 +127,67 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// logsQuery returns the log entries selected by the flags
+func logsQuery(cmd *cobra.Command) logs.Query {
+	q := logs.Query{
+		Lines:      numberOfLines,
+		Since:      logsSince,
+		Components: logsComponents,
+		Pods:       logsPods,
+	}
+
+	// A time range replaces the default number of lines
+	if logsSince > 0 && !cmd.Flags().Changed("length") {
+		q.Lines = 0
+	}
+
+	if logsLevel != "" {
+		l, err := logs.ParseLevel(logsLevel)
+		if err != nil {
+			exit.UsageT("{{.error}}", out.V{"error": err})
+		}
+		q.Level = l
+	}
+
+	switch strings.ToLower(logsOutput) {
+	case "text":
+	case "json":
+		q.JSON = true
+	default:
+		exit.UsageT("Sorry, the output format {{.format}} is not supported. Valid values: 'text', 'json'", out.V{"format": logsOutput})
+	}
+	return q
+}
+
+// writeBundle writes a support bundle of a cluster to the current directory, even if the cluster is not running
+func writeBundle(cname string) {
+	api, cc := mustload.Partial(cname)
+	defer api.Close()
+
+	out.T(out.Documentation, "Collecting a support bundle of {{.name}} ...", out.V{"name": cname})
+	p, err := logs.WriteBundle(logs.BundleConfig{
+		API:     api,
+		Cluster: *cc,
+		Bootstrapper: func(r command.Runner) (bootstrapper.Bootstrapper, error) {
+			return cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, r)
+		},
+		Dir: ".",
+	})
+	if err != nil {
+		exit.WithError("Unable to write support bundle", err)
+	}
+	out.T(out.SuccessType, "Wrote the support bundle to {{.path}}. Secrets in the cluster configuration are redacted, but check the logs before sharing it.", out.V{"path": p})
+}
+
 func init() {
 	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
 	logsCmd.Flags().BoolVar(&showProblems, "problems", false, "Show only log entries which point to known problems")
 	logsCmd.Flags().IntVarP(&numberOfLines, "length", "n", 60, "Number of lines back to go within the log")
+	logsCmd.Flags().BoolVar(&bundleLogs, "bundle", false, "Write the logs of every node, the cluster configuration and the state of the host to a timestamped tar.gz, to share when reporting a problem")
 	logsCmd.Flags().StringVar(&nodeName, "node", "", "The node to get logs from. Defaults to the primary control plane.")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "Only show the log entries of this recent period, such as 10m. Shows every entry of the period, unless --length is also set.")
+	logsCmd.Flags().StringSliceVar(&logsComponents, "component", []string{}, "Only show the logs of these components, such as kubelet, apiserver, etcd, dmesg or runtime")
+	logsCmd.Flags().StringSliceVar(&logsPods, "pod", []string{}, "Show the logs of the containers of these pods, in the kube-system or any other namespace")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "", "Only show the log entries of this severity or higher: debug, info, warning, error or fatal")
+	logsCmd.Flags().StringVarP(&logsOutput, "output", "o", "text", "The output format. One of 'text', 'json'. With json, every log entry is an object with its source, node, timestamp, level and message.")
 }
@@ -26,6 +26,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -34,13 +35,15 @@ import (
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/nfs"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/third_party/go9p/ufs"
 )
 
 const (
 	// nineP is the value of --type used for the 9p filesystem.
-	nineP               = "9p"
+	nineP               = cluster.NineP
 	defaultMountVersion = "9p2000.L"
 	defaultMsize        = 262144
 )
@@ -55,9 +58,14 @@ var gid string
 var mSize int
 var options []string
 var mode uint
+var mountWatch bool
+var watchIgnore []string
+var watchDebounce time.Duration
+var statCacheTTL time.Duration
+var readAhead int
 
 // supportedFilesystems is a map of filesystem types to not warn against.
-var supportedFilesystems = map[string]bool{nineP: true}
+var supportedFilesystems = map[string]bool{nineP: true, cluster.SSHFS: true, cluster.NFS: true}
 
 // mountCmd represents the mount command
 var mountCmd = &cobra.Command{
@@ -66,7 +74,7 @@ var mountCmd = &cobra.Command{
 	Long:  `Mounts the specified directory into minikube.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if isKill {
-			if err := killMountProcess(); err != nil {
+			if err := killMountProcess(ClusterFlagValue()); err != nil {
 				exit.WithError("Error killing mount process", err)
 			}
 			os.Exit(0)
@@ -103,23 +111,27 @@ var mountCmd = &cobra.Command{
 			exit.UsageT(`'none' driver does not support 'minikube mount' command`)
 		}
 
+		// sshfs is served over the SSH connection to the VM, so it needs neither the host IP nor a port
 		var ip net.IP
+		var port int
 		var err error
-		if mountIP == "" {
-			ip, err = cluster.HostIP(co.CP.Host)
-			if err != nil {
-				exit.WithError("Error getting the host IP address to use from within the VM", err)
+		if mountType != cluster.SSHFS {
+			if mountIP == "" {
+				ip, err = cluster.HostIP(co.CP.Host)
+				if err != nil {
+					exit.WithError("Error getting the host IP address to use from within the VM", err)
+				}
+			} else {
+				ip = net.ParseIP(mountIP)
+				if ip == nil {
+					exit.WithCodeT(exit.Data, "error parsing the input ip address for mount")
+				}
 			}
-		} else {
-			ip = net.ParseIP(mountIP)
-			if ip == nil {
-				exit.WithCodeT(exit.Data, "error parsing the input ip address for mount")
+			port, err = getPort()
+			if err != nil {
+				exit.WithError("Error finding port for mount", err)
 			}
 		}
-		port, err := getPort()
-		if err != nil {
-			exit.WithError("Error finding port for mount", err)
-		}
 
 		cfg := &cluster.MountConfig{
 			Type:    mountType,
@@ -154,21 +166,50 @@ var mountCmd = &cobra.Command{
 		out.T(out.Option, "Mount type:   {{.name}}", out.V{"type": cfg.Type})
 		out.T(out.Option, "User ID:      {{.userID}}", out.V{"userID": cfg.UID})
 		out.T(out.Option, "Group ID:     {{.groupID}}", out.V{"groupID": cfg.GID})
-		out.T(out.Option, "Version:      {{.version}}", out.V{"version": cfg.Version})
-		out.T(out.Option, "Message Size: {{.size}}", out.V{"size": cfg.MSize})
+		if cfg.Type == nineP {
+			out.T(out.Option, "Version:      {{.version}}", out.V{"version": cfg.Version})
+			out.T(out.Option, "Message Size: {{.size}}", out.V{"size": cfg.MSize})
+		}
 		out.T(out.Option, "Permissions:  {{.octalMode}} ({{.writtenMode}})", out.V{"octalMode": fmt.Sprintf("%o", cfg.Mode), "writtenMode": cfg.Mode})
 		out.T(out.Option, "Options:      {{.options}}", out.V{"options": cfg.Options})
-		out.T(out.Option, "Bind Address: {{.Address}}", out.V{"Address": net.JoinHostPort(bindIP, fmt.Sprint(port))})
+		if cfg.Type != cluster.SSHFS {
+			out.T(out.Option, "Bind Address: {{.Address}}", out.V{"Address": net.JoinHostPort(bindIP, fmt.Sprint(port))})
+		}
 
 		var wg sync.WaitGroup
-		if cfg.Type == nineP {
+		switch cfg.Type {
+		case nineP:
 			wg.Add(1)
 			go func() {
 				out.T(out.Fileserver, "Userspace file server: ")
-				ufs.StartServer(net.JoinHostPort(bindIP, strconv.Itoa(port)), debugVal, hostPath)
+				ufs.StartServer(net.JoinHostPort(bindIP, strconv.Itoa(port)), debugVal, hostPath, statCacheTTL, readAhead)
 				out.T(out.Stopped, "Userspace file server is shutdown")
 				wg.Done()
 			}()
+		case cluster.NFS:
+			// the NFS server reports file ownership, so the user and group must be known as numbers
+			uid, gid, err := cluster.GuestIDs(co.CP.Runner, cfg)
+			if err != nil {
+				exit.WithError("Error resolving the user and group of the mount", err)
+			}
+			srv, err := nfs.NewServer(hostPath, uid, gid)
+			if err != nil {
+				exit.WithError("Error creating NFS server", err)
+			}
+			l, err := net.Listen("tcp", net.JoinHostPort(bindIP, strconv.Itoa(port)))
+			if err != nil {
+				exit.WithError("Error listening for NFS", err)
+			}
+			out.WarningT("The NFS server has no authentication: anything that can reach {{.address}} can read and write {{.path}}", out.V{"address": l.Addr().String(), "path": hostPath})
+			wg.Add(1)
+			go func() {
+				out.T(out.Fileserver, "NFS server: ")
+				if err := srv.Serve(l); err != nil {
+					glog.Warningf("NFS server: %v", err)
+				}
+				out.T(out.Stopped, "NFS server is shutdown")
+				wg.Done()
+			}()
 		}
 
 		// Unmount if Ctrl-C or kill request is received.
@@ -185,11 +226,15 @@ var mountCmd = &cobra.Command{
 			}
 		}()
 
-		err = cluster.Mount(co.CP.Runner, ip.String(), vmPath, cfg)
-		if err != nil {
+		if cfg.Type == cluster.SSHFS {
+			mountSSHFS(co, hostPath, vmPath, cfg, &wg)
+		} else if err := cluster.Mount(co.CP.Runner, ip.String(), vmPath, cfg); err != nil {
 			exit.WithError("mount failed", err)
 		}
 		out.T(out.SuccessType, "Successfully mounted {{.sourcePath}} to {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+		if mountWatch {
+			watchMount(co, hostPath, vmPath)
+		}
 		out.Ln("")
 		out.T(out.Notice, "NOTE: This process must stay alive for the mount to be accessible ...")
 		wg.Wait()
@@ -198,7 +243,7 @@ var mountCmd = &cobra.Command{
 
 func init() {
 	mountCmd.Flags().StringVar(&mountIP, "ip", "", "Specify the ip that the mount should be setup on")
-	mountCmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p)")
+	mountCmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p, sshfs, nfs). The nfs server has no authentication, so anything that can reach the bind address can read and write the directory.")
 	mountCmd.Flags().StringVar(&mountVersion, "9p-version", defaultMountVersion, "Specify the 9p version that the mount should use")
 	mountCmd.Flags().BoolVar(&isKill, "kill", false, "Kill the mount process spawned by minikube start")
 	mountCmd.Flags().StringVar(&uid, "uid", "docker", "Default user id used for the mount")
@@ -206,6 +251,46 @@ func init() {
 	mountCmd.Flags().UintVar(&mode, "mode", 0755, "File permissions used for the mount")
 	mountCmd.Flags().StringSliceVar(&options, "options", []string{}, "Additional mount options, such as cache=fscache")
 	mountCmd.Flags().IntVar(&mSize, "msize", defaultMsize, "The number of bytes to use for 9p packet payload")
+	mountCmd.Flags().DurationVar(&statCacheTTL, "9p-stat-cache", 0, "How long the 9p file server caches file attributes (0 to disable)")
+	mountCmd.Flags().IntVar(&readAhead, "9p-read-ahead", 0, "The number of bytes the 9p file server reads ahead of each read (0 to disable)")
+	mountCmd.Flags().BoolVar(&mountWatch, "watch", false, "Propagate changes made on the host into the mount, so that file watchers within the cluster see them")
+	mountCmd.Flags().StringSliceVar(&watchIgnore, "watch-ignore", []string{".git"}, "Patterns of paths whose changes are not propagated by --watch")
+	mountCmd.Flags().DurationVar(&watchDebounce, "watch-debounce", 200*time.Millisecond, "How long changes are collected before --watch propagates them")
+}
+
+// mountSSHFS mounts the host with sshfs, which lasts as long as the SSH session serving it
+func mountSSHFS(co mustload.ClusterController, hostPath string, vmPath string, cfg *cluster.MountConfig, wg *sync.WaitGroup) {
+	client, err := sshutil.NewSSHClient(co.CP.Host.Driver)
+	if err != nil {
+		exit.WithError("Error connecting to the VM over SSH", err)
+	}
+	m, err := cluster.MountSSHFS(co.CP.Runner, client, hostPath, vmPath, cfg)
+	if err != nil {
+		exit.WithError("mount failed", err)
+	}
+	wg.Add(1)
+	go func() {
+		if err := m.Wait(); err != nil {
+			out.FailureT("sshfs failed: {{.error}}", out.V{"error": err})
+		}
+		m.Close()
+		out.T(out.Stopped, "sshfs is shutdown")
+		wg.Done()
+	}()
+}
+
+// watchMount propagates the changes made to the source of a mount for as long as the mount lives
+func watchMount(co mustload.ClusterController, hostPath string, vmPath string) {
+	w, err := cluster.NewWatcher(co.CP.Runner, hostPath, vmPath, cluster.WatchConfig{Ignore: watchIgnore, Debounce: watchDebounce})
+	if err != nil {
+		exit.WithError("Error watching for changes", err)
+	}
+	out.T(out.Option, "Propagating changes of {{.sourcePath}} to {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+	go func() {
+		if err := w.Run(nil); err != nil {
+			out.FailureT("Failed to watch for changes: {{.error}}", out.V{"error": err})
+		}
+	}()
 }
 
 // getPort asks the kernel for a free open port that is ready to use
@@ -17,19 +17,31 @@ limitations under the License.
 package cmd
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
 )
 
 var (
-	cp     bool
-	worker bool
+	cp           bool
+	worker       bool
+	nodeCPUs     int
+	nodeMemory   string
+	nodeDiskSize string
+	nodeLabels   []string
+	nodeTaints   []string
+	nodeZone     string
+	nodeRegion   string
 )
 var nodeAddCmd = &cobra.Command{
 	Use:   "add",
@@ -43,6 +55,22 @@ var nodeAddCmd = &cobra.Command{
 			out.FailureT("none driver does not support multi-node clusters")
 		}
 
+		// control planes join behind the virtual IP, which only highly-available clusters have
+		if cp && !config.IsHA(*cc) {
+			exit.UsageT("Control planes can only be added to clusters started with --control-planes greater than 1")
+		}
+
+		// Add CNI config if it's not already there, so that kindnet is chosen once the node is added
+		current, err := cni.New(*cc)
+		if err != nil {
+			exit.WithError("failed to choose CNI", err)
+		}
+		if cni.IsDisabled(current) && !strings.EqualFold(cc.KubernetesConfig.CNI, "false") {
+			if err := config.MultiNodeCNIConfig(cc); err != nil {
+				exit.WithError("failed to save config", err)
+			}
+		}
+
 		name := node.Name(len(cc.Nodes) + 1)
 
 		out.T(out.Happy, "Adding node {{.name}} to cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
@@ -54,6 +82,10 @@ var nodeAddCmd = &cobra.Command{
 			ControlPlane:      cp,
 			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		}
+		if cp {
+			n.Port = cc.KubernetesConfig.NodePort
+		}
+		setNodeTopology(&n)
 
 		// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
 		if len(cc.Nodes) == 1 {
@@ -70,20 +102,70 @@ var nodeAddCmd = &cobra.Command{
 			}
 		}
 
-		// Add CNI config if it's not already there
-		// We need to run kubeadm.init here as well
-		if err := config.MultiNodeCNIConfig(cc); err != nil {
-			exit.WithError("failed to save config", err)
+		// A CNI is required for pods to reach each other across nodes, so make sure it is applied
+		cnm, err := cni.New(*cc)
+		if err != nil {
+			exit.WithError("failed to choose CNI", err)
+		}
+		if cni.IsDisabled(cnm) {
+			out.WarningT("{{.cluster}} has no CNI, so pods on {{.name}} may be unreachable. Recreate the cluster with --cni to choose one.", out.V{"name": name, "cluster": cc.Name})
+		} else if err := cnm.Apply(co.CP.Runner); err != nil {
+			exit.WithError("failed to apply CNI", err)
 		}
 
 		out.T(out.Ready, "Successfully added {{.name}} to {{.cluster}}!", out.V{"name": name, "cluster": cc.Name})
 	},
 }
 
+// setNodeTopology sets the resources, labels, taints and topology of a new node from the flags
+func setNodeTopology(n *config.Node) {
+	if nodeCPUs < 0 {
+		exit.UsageT("The number of CPUs must not be negative, got {{.cpus}}", out.V{"cpus": nodeCPUs})
+	}
+	n.CPUs = nodeCPUs
+
+	size := func(flag string, value string) int {
+		if value == "" {
+			return 0
+		}
+		mb, err := pkgutil.CalculateSizeInMB(value)
+		if err != nil {
+			exit.WithCodeT(exit.Config, "Unable to parse --{{.flag}} '{{.value}}': {{.error}}", out.V{"flag": flag, "value": value, "error": err})
+		}
+		return mb
+	}
+	n.Memory = size("memory", nodeMemory)
+	n.DiskSize = size("disk-size", nodeDiskSize)
+
+	for _, l := range nodeLabels {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			exit.UsageT("Invalid label '{{.label}}', expected key=value", out.V{"label": l})
+		}
+		if n.Labels == nil {
+			n.Labels = map[string]string{}
+		}
+		n.Labels[kv[0]] = kv[1]
+	}
+	n.Taints = nodeTaints
+	n.Zone = nodeZone
+	n.Region = nodeRegion
+	if err := bsutil.ValidateNode(*n); err != nil {
+		exit.UsageT("Invalid node topology: {{.error}}", out.V{"error": err})
+	}
+}
+
 func init() {
 	// TODO(https://github.com/kubernetes/minikube/issues/7366): We should figure out which minikube start flags to actually import
 	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker.")
 	nodeAddCmd.Flags().BoolVar(&worker, "worker", true, "If true, the added node will be marked for work. Defaults to true.")
+	nodeAddCmd.Flags().IntVar(&nodeCPUs, "cpus", 0, "Number of CPUs allocated to the node. Defaults to the CPUs of the cluster.")
+	nodeAddCmd.Flags().StringVar(&nodeMemory, "memory", "", "Amount of RAM to allocate to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to the memory of the cluster.")
+	nodeAddCmd.Flags().StringVar(&nodeDiskSize, "disk-size", "", "Disk size allocated to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to the disk size of the cluster.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeLabels, "labels", nil, "Kubernetes labels of the node, as key=value pairs.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeTaints, "taints", nil, "Kubernetes taints of the node, as key[=value]:effect where effect is NoSchedule, PreferNoSchedule or NoExecute.")
+	nodeAddCmd.Flags().StringVar(&nodeZone, "zone", "", "The topology.kubernetes.io/zone label of the node.")
+	nodeAddCmd.Flags().StringVar(&nodeRegion, "region", "", "The topology.kubernetes.io/region label of the node.")
 	nodeAddCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 
 	nodeCmd.AddCommand(nodeAddCmd)
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
@@ -37,6 +38,10 @@ var nodeDeleteCmd = &cobra.Command{
 		name := args[0]
 
 		co := mustload.Healthy(ClusterFlagValue())
+		if n, _, err := node.Retrieve(*co.Config, name); err == nil && config.IsPrimaryControlPlane(*co.Config, *n) {
+			exit.WithCodeT(exit.Config, "The primary control plane {{.name}} of cluster {{.cluster}} cannot be deleted. Use \"minikube delete\" to delete the cluster.", out.V{"name": name, "cluster": co.Config.Name})
+		}
+
 		out.T(out.DeletingHost, "Deleting node {{.name}} from cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
 
 		n, err := node.Delete(*co.Config, name)
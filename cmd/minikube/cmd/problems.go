@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/problem"
+)
+
+var (
+	problemsOutput string
+	problemsOS     string
+)
+
+// problemsCmd represents the problems command
+var problemsCmd = &cobra.Command{
+	Use:   "problems",
+	Short: "List or test the known problems that minikube gives advice for",
+	Long: `Lists or tests the known problems that minikube gives advice for when it fails.
+
+Problems are added to the built-in ones with YAML files in the problems.d directory of the minikube home,
+and with a YAML file at the URL of the problems-url setting, which is downloaded at most once a day.
+Each file is a list of problems with an id, a regexp matching the error, advice, and optionally a url, the goos they apply to,
+and the diagnostic probes to run when minikube fails with them.
+A problem replaces the built-in one with the same id.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.UsageT("Usage: minikube problems [list|test]")
+	},
+}
+
+// problemsListCmd represents the problems list command
+var problemsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the known problems",
+	Long:  "Lists the known problems, those added by the user first, and the files they are defined in.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.UsageT("Usage: minikube problems list")
+		}
+
+		defs := loadProblems()
+		switch strings.ToLower(problemsOutput) {
+		case "json":
+			type problemJSON struct {
+				ID     string   `json:"id"`
+				Source string   `json:"source"`
+				Regexp string   `json:"regexp"`
+				Advice string   `json:"advice"`
+				URL    string   `json:"url,omitempty"`
+				GOOS   []string `json:"goos,omitempty"`
+				Probes []string `json:"probes,omitempty"`
+			}
+			ps := []problemJSON{}
+			for _, d := range defs {
+				ps = append(ps, problemJSON{ID: d.ID, Source: d.Source, Regexp: d.Regexp.String(), Advice: d.Advice, URL: d.URL, GOOS: d.GOOS, Probes: d.Probes})
+			}
+			b, err := json.Marshal(ps)
+			if err != nil {
+				exit.WithError("Failed to marshal problems", err)
+			}
+			out.Ln(string(b))
+		case "table":
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"ID", "Source", "OS", "Regexp"})
+			table.SetAutoFormatHeaders(false)
+			table.SetAutoWrapText(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, d := range defs {
+				table.Append([]string{d.ID, d.Source, strings.Join(d.GOOS, ","), d.Regexp.String()})
+			}
+			table.Render()
+		default:
+			exit.WithCodeT(exit.BadUsage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", problemsOutput))
+		}
+	},
+}
+
+// problemsTestCmd represents the problems test command
+var problemsTestCmd = &cobra.Command{
+	Use:   "test <error text>",
+	Short: "Show which known problem matches an error",
+	Long:  "Shows the known problem that minikube would give advice for, if it failed with an error, and the other problems that match it.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("Usage: minikube problems test <error text>")
+		}
+
+		loadProblems()
+		msg := strings.Join(args, " ")
+		ms := problem.Matches(msg, problemsOS)
+		if len(ms) == 0 {
+			out.T(out.Meh, "No known problem matches this error on {{.os}}", out.V{"os": problemsOS})
+			os.Exit(exit.Failure)
+		}
+
+		out.T(out.Check, "{{.id}} from {{.source}} matches", out.V{"id": ms[0].ID, "source": ms[0].Source})
+		p := problem.FromError(fmt.Errorf("%s", msg), problemsOS)
+		p.Display()
+		if len(p.Probes) > 0 {
+			out.T(out.HealthCheck, "Diagnostic probes: {{.probes}}", out.V{"probes": strings.Join(p.Probes, ", ")})
+		}
+		for _, m := range ms[1:] {
+			out.T(out.Option, "{{.id}} from {{.source}} also matches, but does not apply", out.V{"id": m.ID, "source": m.Source})
+		}
+	},
+}
+
+// loadProblems returns the known problems, warning about the problem files that could not be loaded
+func loadProblems() []problem.Definition {
+	defs, err := problem.Definitions()
+	if err != nil {
+		out.WarningT("Some problems could not be loaded: {{.error}}", out.V{"error": err})
+	}
+	return defs
+}
+
+func init() {
+	problemsListCmd.Flags().StringVarP(&problemsOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	problemsTestCmd.Flags().StringVar(&problemsOS, "os", runtime.GOOS, "The operating system to test the problems for, as problems may be specific to one")
+	problemsCmd.AddCommand(problemsListCmd)
+	problemsCmd.AddCommand(problemsTestCmd)
+}
@@ -30,11 +30,13 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/kubectl/pkg/util/templates"
 	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/addons"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/translate"
 )
 
@@ -77,6 +79,13 @@ var RootCmd = &cobra.Command{
 	},
 }
 
+// loadUserAddons registers the user-defined addons, for the commands which manage or enable addons
+func loadUserAddons() {
+	if err := addons.LoadUserAddons(); err != nil {
+		out.WarningT("Unable to load user-defined addons: {{.error}}", out.V{"error": err})
+	}
+}
+
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -171,6 +180,10 @@ func init() {
 	translate.DetermineLocale()
 	RootCmd.PersistentFlags().StringP(config.ProfileName, "p", constants.DefaultClusterName, `The name of the minikube VM being used. This can be set to allow having multiple instances of minikube independently.`)
 	RootCmd.PersistentFlags().StringP(configCmd.Bootstrapper, "b", "kubeadm", "The name of the cluster bootstrapper that will set up the Kubernetes cluster.")
+	configCmd.AddonsCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		RootCmd.PersistentPreRun(cmd, args)
+		loadUserAddons()
+	}
 
 	groups := templates.CommandGroups{
 		{
@@ -190,7 +203,9 @@ func init() {
 			Commands: []*cobra.Command{
 				dockerEnvCmd,
 				podmanEnvCmd,
+				containerEnvCmd,
 				cacheCmd,
+				imageCmd,
 			},
 		},
 		{
@@ -199,7 +214,9 @@ func init() {
 				configCmd.AddonsCmd,
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
+				snapshotCmd,
 				updateContextCmd,
+				upgradeCmd,
 			},
 		},
 		{
@@ -224,6 +241,7 @@ func init() {
 				sshKeyCmd,
 				ipCmd,
 				logsCmd,
+				problemsCmd,
 				updateCheckCmd,
 				versionCmd,
 				optionsCmd,
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/schedule"
+)
+
+// scheduledStopCmd runs in the background, and stops a cluster when it is due to stop
+var scheduledStopCmd = &cobra.Command{
+	Use:    schedule.Command,
+	Short:  "Waits for a cluster to be due to stop, and stops it",
+	Long:   "Waits for a cluster to be due to stop, and stops it. Started in the background by 'minikube stop --schedule' and 'minikube start --auto-stop'.",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		// outlive the terminal that started the watcher
+		signal.Ignore(syscall.SIGHUP)
+
+		cname := ClusterFlagValue()
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		due, err := schedule.Wait(api, cname)
+		api.Close()
+		if err := schedule.Done(cname); err != nil {
+			glog.Warningf("scheduled stop: %v", err)
+		}
+		if err != nil {
+			exit.WithError("Failed to wait for the scheduled stop", err)
+		}
+		if !due {
+			return
+		}
+
+		glog.Infof("%q is due to stop", cname)
+		stopProfile(cname)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(scheduledStopCmd)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+)
+
+var snapshotOutput string
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save, restore, or list snapshots of a cluster",
+	Long:  "Save, restore, or list snapshots of a cluster's etcd data, certificates, configuration and image list",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.UsageT("Usage: minikube snapshot [save|restore|list|delete]")
+	},
+}
+
+// snapshotSaveCmd represents the snapshot save command
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save a snapshot of the cluster.",
+	Long:  "Captures the etcd data, certificates, cluster config and image list of the control plane.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube snapshot save [name]")
+		}
+
+		co := mustload.Healthy(ClusterFlagValue())
+		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: co.CP.Runner})
+		if err != nil {
+			exit.WithError("Failed runtime", err)
+		}
+
+		out.T(out.Copying, "Saving snapshot {{.name}} of {{.profile}} ...", out.V{"name": args[0], "profile": co.Config.Name})
+		m, err := snapshot.Save(co.Config, cr, co.CP.Runner, args[0])
+		if err != nil {
+			exit.WithError("Failed to save snapshot", err)
+		}
+		out.T(out.Check, "Saved snapshot {{.name}} ({{.images}} images)", out.V{"name": m.Name, "images": len(m.Images)})
+	},
+}
+
+// snapshotRestoreCmd represents the snapshot restore command
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the cluster from a snapshot.",
+	Long:  "Rolls the etcd data, certificates and cluster config of the control plane back to a saved snapshot.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube snapshot restore [name]")
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: co.CP.Runner})
+		if err != nil {
+			exit.WithError("Failed runtime", err)
+		}
+
+		out.T(out.Resetting, "Restoring snapshot {{.name}} of {{.profile}} ...", out.V{"name": args[0], "profile": co.Config.Name})
+		m, err := snapshot.Restore(co.Config, cr, co.CP.Runner, args[0])
+		if err != nil {
+			exit.WithError("Failed to restore snapshot", err)
+		}
+
+		missing, err := snapshot.MissingImages(m, cr)
+		if err != nil {
+			out.WarningT("Unable to compare images: {{.error}}", out.V{"error": err})
+		} else if len(missing) > 0 {
+			out.WarningT("The following images from the snapshot are no longer present: {{.images}}", out.V{"images": strings.Join(missing, ", ")})
+		}
+		out.T(out.Check, "Restored snapshot {{.name}}", out.V{"name": m.Name})
+	},
+}
+
+// snapshotListCmd represents the snapshot list command
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots of the cluster.",
+	Long:  "Lists all snapshots saved for the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.UsageT("Usage: minikube snapshot list")
+		}
+
+		_, cc := mustload.Partial(ClusterFlagValue())
+		ms, err := snapshot.List(cc.Name)
+		if err != nil {
+			exit.WithError("Failed to list snapshots", err)
+		}
+
+		switch strings.ToLower(snapshotOutput) {
+		case "json":
+			if ms == nil {
+				ms = []*snapshot.Manifest{}
+			}
+			b, err := json.Marshal(ms)
+			if err != nil {
+				exit.WithError("Failed to marshal snapshots", err)
+			}
+			out.Ln(string(b))
+		case "table":
+			if len(ms) == 0 {
+				out.T(out.Empty, "No snapshots found for {{.profile}}", out.V{"profile": cc.Name})
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Name", "Created", "Version", "Runtime", "Images"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, m := range ms {
+				table.Append([]string{m.Name, m.CreationTime.Format(time.RFC3339), m.KubernetesVersion, m.ContainerRuntime, fmt.Sprintf("%d", len(m.Images))})
+			}
+			table.Render()
+		default:
+			exit.WithCodeT(exit.BadUsage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", snapshotOutput))
+		}
+	},
+}
+
+// snapshotDeleteCmd represents the snapshot delete command
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a snapshot of the cluster.",
+	Long:  "Deletes a saved snapshot from the profile directory.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube snapshot delete [name]")
+		}
+
+		_, cc := mustload.Partial(ClusterFlagValue())
+		if err := snapshot.Delete(cc.Name, args[0]); err != nil {
+			exit.WithError("Failed to delete snapshot", err)
+		}
+		out.T(out.Deleted, "Deleted snapshot {{.name}}", out.V{"name": args[0]})
+	},
+}
+
+func init() {
+	snapshotListCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+}
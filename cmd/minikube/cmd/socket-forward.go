@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/forward"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+)
+
+var forwardSocket string
+
+// socketForwardCmd runs in the background, and forwards a socket on the host to the container runtime of a cluster
+var socketForwardCmd = &cobra.Command{
+	Use:    forward.Command,
+	Short:  "Forwards a socket on the host to the container runtime of a cluster",
+	Long:   "Forwards the connections to a Unix socket on the host to the socket of the container runtime within a cluster, over SSH, until the cluster stops. Started in the background by 'minikube container-env'.",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		// outlive the terminal that started the forwarder
+		signal.Ignore(syscall.SIGHUP)
+
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+		client, err := sshutil.NewSSHClient(co.CP.Host.Driver)
+		if err != nil {
+			exit.WithError("Error getting ssh client", err)
+		}
+
+		path := forward.SocketPath(cname)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			exit.WithError("Error removing stale socket", err)
+		}
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			exit.WithError("Error listening on socket", err)
+		}
+		if err := os.Chmod(path, 0600); err != nil {
+			glog.Warningf("chmod %s: %v", path, err)
+		}
+
+		// stop once the cluster does
+		go func() {
+			err := client.Wait()
+			glog.Infof("ssh connection to %q closed: %v", cname, err)
+			l.Close()
+		}()
+
+		glog.Infof("forwarding %s to %s within %q", path, forwardSocket, cname)
+		err = forward.Serve(l, forward.SSHRelay(client, forwardSocket))
+		glog.Infof("socket forwarder for %q stopped: %v", cname, err)
+		if err := forward.Done(cname); err != nil {
+			glog.Warningf("socket forwarder: %v", err)
+		}
+	},
+}
+
+func init() {
+	socketForwardCmd.Flags().StringVar(&forwardSocket, "socket", "", "The socket of the container runtime within the cluster")
+	RootCmd.AddCommand(socketForwardCmd)
+}
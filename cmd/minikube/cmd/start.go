@@ -26,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"os/user"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -45,6 +46,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/download"
@@ -57,7 +59,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/notify"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/minikube/translate"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
@@ -123,6 +127,14 @@ func platform() string {
 // runStart handles the executes the flow of "minikube start"
 func runStart(cmd *cobra.Command, args []string) {
 	mabing.Log("runStart",cmd.Use)
+	switch viper.GetString(startOutput) {
+	case "text":
+	case "json":
+		out.SetJSON(true)
+	default:
+		exit.UsageT("Sorry, the output format {{.format}} is not supported. Valid values: 'text', 'json'", out.V{"format": viper.GetString(startOutput)})
+	}
+	register.Reg.SetStep(register.InitialSetup)
 	displayVersion(version.GetVersion()) //mabing: * minikube v1.11.0 on Ubuntu 18.04
 
 	// No need to do the update check if no one is going to see it
@@ -132,6 +144,13 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 
 	displayEnviron(os.Environ())
+	loadUserAddons()
+
+	if path := viper.GetString(configFile); path != "" {
+		if err := applyClusterSpec(cmd, path); err != nil {
+			exit.WithCodeT(exit.Config, "Unable to use cluster spec {{.path}}: {{.error}}", out.V{"path": path, "error": err})
+		}
+	}
 
 	// if --registry-mirror specified when run minikube start,
 	// take arg precedence over MINIKUBE_REGISTRY_MIRROR
@@ -148,12 +167,17 @@ func runStart(cmd *cobra.Command, args []string) {
 		out.WarningT("Profile name '{{.name}}' is not valid", out.V{"name": ClusterFlagValue()})
 		exit.UsageT("Only alphanumeric, dots, underscores and dashes '-' are permitted. Minimum 2 characters, starting by alphanumeric.")
 	}
+	if viper.GetBool(fix) {
+		applyFixes(ClusterFlagValue())
+	}
+
 	existing, err := config.Load(ClusterFlagValue())
 	if err != nil && !config.IsNotExist(err) {
 		exit.WithCodeT(exit.Data, "Unable to load config: {{.error}}", out.V{"error": err})
 	}
 
 	validateSpecifiedDriver(existing)
+	register.Reg.SetStep(register.SelectingDriver)
 	ds, alts, specified := selectDriver(existing)
 	starter, err := provisionWithDriver(cmd, ds, existing)
 	if err != nil {
@@ -200,11 +224,25 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// starting a cluster cancels the stop scheduled for it, and replaces its watcher
+	if err := schedule.Cancel(starter.Cfg.Name); err != nil {
+		glog.Warningf("cancel scheduled stop: %v", err)
+	}
+
 	kubeconfig, err := startWithDriver(starter, existing)
 	if err != nil {
 		exit.WithError("failed to start node", err)
 	}
 
+	if starter.Cfg.AutoStop > 0 {
+		if err := schedule.Start(starter.Cfg.Name); err != nil {
+			out.WarningT("Unable to schedule the stop of {{.name}}: {{.error}}", out.V{"name": starter.Cfg.Name, "error": err})
+		} else {
+			out.T(out.Waiting, "{{.name}} will stop after {{.duration}} without API server requests", out.V{"name": starter.Cfg.Name, "duration": starter.Cfg.AutoStop})
+		}
+	}
+
+	register.Reg.SetStep(register.Done)
 	if err := showKubectlInfo(kubeconfig, starter.Node.KubernetesVersion, starter.Cfg.Name); err != nil {
 		glog.Errorf("kubectl info: %v", err)
 	}
@@ -225,11 +263,15 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	validateUser(driverName)
 
 	// Download & update the driver, even in --download-only mode
+	register.Reg.SetStep(register.DownloadingArtifacts)
 	if !viper.GetBool(dryRun) {
 		updateDriver(driverName)
 	}
 
 	k8sVersion := getKubernetesVersion(existing)
+	if existing == nil {
+		validateControlPlanes(driverName, k8sVersion)
+	}
 	cc, n, err := generateClusterConfig(cmd, existing, k8sVersion, driverName)
 	if err != nil {
 		return node.Starter{}, errors.Wrap(err, "Failed to generate config")
@@ -242,7 +284,7 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	}
 	mabing.Log("driver.IsVM(driverName): ", driver.IsVM(driverName))
 	if driver.IsVM(driverName) {
-		url, err := download.ISO(viper.GetStringSlice(isoURL), cmd.Flags().Changed(isoURL))
+		url, err := download.ISO(viper.GetStringSlice(isoURL), flagSpecified(cmd, isoURL))
 		if err != nil {
 			return node.Starter{}, errors.Wrap(err, "Failed to cache ISO")
 		}
@@ -262,6 +304,26 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		return node.Starter{}, err
 	}
 
+	// The virtual IP is picked from the network of the primary control plane, so it is only known once it is provisioned
+	if existing == nil && viper.GetInt(controlPlanes) > 1 {
+		vip := viper.GetString(haVIP)
+		if vip == "" {
+			vip, err = bsutil.HAVirtualIP(mRunner, n.IP)
+			if err != nil {
+				exit.WithCodeT(exit.Unavailable, "Unable to find a free virtual IP for the control planes: {{.error}}. Pick one with --ha-vip.", out.V{"error": err})
+			}
+		} else if bsutil.AddressInUse(mRunner, vip) {
+			exit.WithCodeT(exit.Config, "The virtual IP {{.ip}} is already in use. Pick another one with --ha-vip.", out.V{"ip": vip})
+		}
+		cc.KubernetesConfig.APIServerHAVIP = vip
+		if err := config.SaveProfile(cc.Name, &cc); err != nil {
+			return node.Starter{}, errors.Wrap(err, "saving virtual IP")
+		}
+		if driver.NeedsPortForward(driverName) {
+			out.WarningT("The '{{.driver}}' driver forwards the API server port of the primary control plane, so the virtual IP {{.ip}} is only used within the cluster.", out.V{"driver": driverName, "ip": cc.KubernetesConfig.APIServerHAVIP})
+		}
+	}
+
 	if viper.GetBool(nativeSSH) {
 		ssh.SetDefaultClient(ssh.Native)
 	} else {
@@ -289,6 +351,7 @@ func startWithDriver(starter node.Starter, existing *config.ClusterConfig) (*kub
 	}
 
 	numNodes := viper.GetInt(nodes)
+	numControlPlanes := viper.GetInt(controlPlanes)
 	if existing != nil {
 		if numNodes > 1 {
 			// We ignore the --nodes parameter if we're restarting an existing cluster
@@ -310,9 +373,17 @@ func startWithDriver(starter node.Starter, existing *config.ClusterConfig) (*kub
 					n := config.Node{
 						Name:              nodeName,
 						Worker:            true,
-						ControlPlane:      false,
+						ControlPlane:      i < numControlPlanes,
 						KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
 					}
+					if i < len(specNodes) {
+						n = specNodes[i]
+						n.IP = ""
+						n.KubernetesVersion = starter.Cfg.KubernetesConfig.KubernetesVersion
+					}
+					if n.ControlPlane {
+						n.Port = starter.Cfg.KubernetesConfig.NodePort
+					}
 					out.Ln("") // extra newline for clarity on the command line
 					err := node.Add(starter.Cfg, n)
 					if err != nil {
@@ -320,8 +391,9 @@ func startWithDriver(starter node.Starter, existing *config.ClusterConfig) (*kub
 					}
 				}
 			} else {
+				// secondary control planes left the cluster when it was stopped, and rejoin it like workers
 				for _, n := range existing.Nodes {
-					if !n.ControlPlane {
+					if !config.IsPrimaryControlPlane(*existing, n) {
 						err := node.Add(starter.Cfg, n)
 						if err != nil {
 							return nil, errors.Wrap(err, "adding node")
@@ -822,9 +894,52 @@ func validateCPUCount(local bool) {
 	}
 }
 
+// validateControlPlanes validates the number of control planes of a new cluster, which all count as nodes
+func validateControlPlanes(drvName string, k8sVersion string) {
+	n := viper.GetInt(controlPlanes)
+	if n < 1 {
+		exit.WithCodeT(exit.Config, "The number of control planes must be at least 1, got {{.count}}", out.V{"count": n})
+	}
+	vip := viper.GetString(haVIP)
+	if n == 1 {
+		if vip != "" {
+			out.WarningT("--ha-vip is ignored, as the cluster has a single control plane")
+		}
+		return
+	}
+	if vip != "" && net.ParseIP(vip).To4() == nil {
+		exit.UsageT("The virtual IP {{.ip}} is not a valid IPv4 address", out.V{"ip": vip})
+	}
+	if driver.BareMetal(drvName) {
+		exit.WithCodeT(exit.Config, "The none driver is not compatible with multi-node clusters.")
+	}
+	if version, err := util.ParseKubernetesVersion(k8sVersion); err == nil && version.LT(semver.MustParse("1.15.0")) {
+		exit.WithCodeT(exit.Config, "Highly-available clusters require Kubernetes v1.15.0 or newer.")
+	}
+	if viper.GetInt(nodes) < n {
+		viper.Set(nodes, n)
+	}
+}
+
+// validateCNI checks that a CNI manifest exists, and makes its path absolute so that it can be found later on
+func validateCNI() {
+	name := viper.GetString(cniFlag)
+	if !cni.IsCustom(name) {
+		return
+	}
+	abs, err := filepath.Abs(name)
+	if err == nil {
+		_, err = os.Stat(abs)
+	}
+	if err != nil {
+		exit.WithCodeT(exit.Config, "The CNI manifest {{.path}} cannot be used: {{.error}}", out.V{"path": name, "error": err})
+	}
+	viper.Set(cniFlag, abs)
+}
+
 // validateFlags validates the supplied flags against known bad combinations
 func validateFlags(cmd *cobra.Command, drvName string) {
-	if cmd.Flags().Changed(humanReadableDiskSize) {
+	if flagSpecified(cmd, humanReadableDiskSize) {
 		diskSizeMB, err := util.CalculateSizeInMB(viper.GetString(humanReadableDiskSize))
 		if err != nil {
 			exit.WithCodeT(exit.Config, "Validation unable to parse disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": viper.GetString(humanReadableDiskSize), "error": err})
@@ -835,20 +950,28 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		}
 	}
 
-	if cmd.Flags().Changed(cpus) {
+	if flagSpecified(cmd, cpus) {
 		validateCPUCount(driver.BareMetal(drvName))
 		if !driver.HasResourceLimits(drvName) {
 			out.WarningT("The '{{.name}}' driver does not respect the --cpus flag", out.V{"name": drvName})
 		}
 	}
 
-	if cmd.Flags().Changed(memory) {
+	if flagSpecified(cmd, memory) {
 		validateMemorySize()
 		if !driver.HasResourceLimits(drvName) {
 			out.WarningT("The '{{.name}}' driver does not respect the --memory flag", out.V{"name": drvName})
 		}
 	}
 
+	if flagSpecified(cmd, cniFlag) {
+		validateCNI()
+	}
+
+	if cmd.Flags().Changed(mountTypeFlag) && !supportedFilesystems[viper.GetString(mountTypeFlag)] {
+		exit.UsageT("Invalid mount type {{.type}}, valid types are 9p, sshfs and nfs", out.V{"type": viper.GetString(mountTypeFlag)})
+	}
+
 	if driver.BareMetal(drvName) {
 		if ClusterFlagValue() != constants.DefaultClusterName {
 			exit.WithCodeT(exit.Config, "The '{{.name}} driver does not support multiple profiles: https://minikube.sigs.k8s.io/docs/reference/drivers/none/", out.V{"name": drvName})
@@ -925,6 +1048,18 @@ func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.C
 		ControlPlane:      true,
 		Worker:            true,
 	}
+	if len(specNodes) > 0 {
+		// the name of the primary control plane is picked by the driver
+		s := specNodes[0]
+		cp.Worker = s.Worker
+		cp.CPUs = s.CPUs
+		cp.Memory = s.Memory
+		cp.DiskSize = s.DiskSize
+		cp.Labels = s.Labels
+		cp.Taints = s.Taints
+		cp.Zone = s.Zone
+		cp.Region = s.Region
+	}
 	cc.Nodes = []config.Node{cp}
 	return cc, cp, nil
 }
@@ -943,11 +1078,11 @@ func autoSetDriverOptions(cmd *cobra.Command, drvName string) (err error) {
 		}
 	}
 
-	if !cmd.Flags().Changed(cacheImages) {
+	if !flagSpecified(cmd, cacheImages) {
 		viper.Set(cacheImages, hints.CacheImages)
 	}
 
-	if !cmd.Flags().Changed(containerRuntime) && hints.ContainerRuntime != "" {
+	if !flagSpecified(cmd, containerRuntime) && hints.ContainerRuntime != "" {
 		viper.Set(containerRuntime, hints.ContainerRuntime)
 		glog.Infof("auto set %s to %q.", containerRuntime, hints.ContainerRuntime)
 	}
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,7 +27,9 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
@@ -54,6 +58,7 @@ const (
 	criSocket               = "cri-socket"
 	networkPlugin           = "network-plugin"
 	enableDefaultCNI        = "enable-default-cni"
+	cniFlag                 = "cni"
 	hypervVirtualSwitch     = "hyperv-virtual-switch"
 	hypervUseExternalSwitch = "hyperv-use-external-switch"
 	hypervExternalAdapter   = "hyperv-external-adapter"
@@ -74,6 +79,7 @@ const (
 	imageRepository         = "image-repository"
 	imageMirrorCountry      = "image-mirror-country"
 	mountString             = "mount-string"
+	mountTypeFlag           = "mount-type"
 	disableDriverMounts     = "disable-driver-mounts"
 	cacheImages             = "cache-images"
 	uuid                    = "uuid"
@@ -98,10 +104,16 @@ const (
 	hostOnlyNicType         = "host-only-nic-type"
 	natNicType              = "nat-nic-type"
 	nodes                   = "nodes"
+	controlPlanes           = "control-planes"
+	haVIP                   = "ha-vip"
 	preload                 = "preload"
 	deleteOnFailure         = "delete-on-failure"
 	forceSystemd            = "force-systemd"
 	kicBaseImage            = "base-image"
+	configFile              = "config-file"
+	startOutput             = "output"
+	autoStop                = "auto-stop"
+	fix                     = "fix"
 )
 
 // initMinikubeFlags includes commandline flags for minikube.
@@ -115,6 +127,8 @@ func initMinikubeFlags() {
 	startCmd.Flags().Bool(force, false, "Force minikube to perform possibly dangerous operations")
 	startCmd.Flags().Bool(interactive, true, "Allow user prompts for more information")
 	startCmd.Flags().Bool(dryRun, false, "dry-run mode. Validates configuration, but does not mutate system state")
+	startCmd.Flags().StringP(startOutput, "o", "text", "Format to print stdout in. Options include: [text,json]")
+	startCmd.Flags().String(configFile, "", "Path to a YAML or JSON cluster spec, as generated by 'minikube config export'. Flags passed on the command line take precedence over the spec.")
 
 	startCmd.Flags().Int(cpus, 2, "Number of CPUs allocated to Kubernetes.")
 	startCmd.Flags().String(memory, "", "Amount of RAM to allocate to Kubernetes (format: <number>[<unit>], where unit = b, k, m or g).")
@@ -128,18 +142,24 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(containerRuntime, "docker", "The container runtime to be used (docker, crio, containerd).")
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube.")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":/minikube-host", "The argument to pass the minikube mount command on start.")
+	startCmd.Flags().String(mountTypeFlag, "", "The filesystem type of the mount: 9p, sshfs or nfs. The nfs server has no authentication. (default: the best type for the driver)")
 	startCmd.Flags().StringArrayVar(&config.AddonList, "addons", nil, "Enable addons. see `minikube addons list` for a list of valid addon names.")
 	startCmd.Flags().String(criSocket, "", "The cri socket path to be used.")
 	startCmd.Flags().String(networkPlugin, "", "The name of the network plugin.")
 	startCmd.Flags().Bool(enableDefaultCNI, false, "Enable the default CNI plugin (/etc/cni/net.d/k8s.conf). Used in conjunction with \"--network-plugin=cni\".")
+	startCmd.Flags().String(cniFlag, "", "CNI plug-in to use. Valid options: auto, bridge, calico, flannel, kindnet, false, or the path to a CNI manifest (default: auto)")
 	startCmd.Flags().StringSlice(waitComponents, kverify.DefaultWaitList, fmt.Sprintf("comma separated list of Kubernetes components to verify and wait for after starting a cluster. defaults to %q, available options: %q . other acceptable values are 'all' or 'none', 'true' and 'false'", strings.Join(kverify.DefaultWaitList, ","), strings.Join(kverify.AllComponentsList, ",")))
 	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "max time to wait per Kubernetes core services to be healthy.")
 	startCmd.Flags().Bool(nativeSSH, true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().IntP(nodes, "n", 1, "The number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().Int(controlPlanes, 1, "The number of control planes to spin up, behind a virtual IP. More than one makes the cluster highly available. Defaults to 1.")
+	startCmd.Flags().String(haVIP, "", "The virtual IP of a cluster with more than one control plane, in the network of the primary control plane. Defaults to a free address at the end of its /24.")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
 	startCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
+	startCmd.Flags().Bool(fix, false, "If set, diagnose the known problems that minikube can safely fix, such as containers left behind by a deleted cluster, and fix them before starting.")
+	startCmd.Flags().Duration(autoStop, 0, "Stop the cluster once its API servers have seen no new connections for this long, such as 1h (0 to disable)")
 	startCmd.Flags().Bool(forceSystemd, false, "If set, force the container runtime to use sytemd as cgroup manager. Currently available for docker and crio. Defaults to false.")
 }
 
@@ -209,6 +229,189 @@ func ClusterFlagValue() string {
 	return viper.GetString(config.ProfileName)
 }
 
+// specFlag is a start flag value derived from a cluster spec
+type specFlag struct {
+	name  string
+	value string
+}
+
+// clusterSpecFlags translates a cluster spec into the equivalent start flags, omitting unset fields
+func clusterSpecFlags(s *config.ClusterSpec) []specFlag {
+	fs := []specFlag{}
+	str := func(name string, v string) {
+		if v != "" {
+			fs = append(fs, specFlag{name, v})
+		}
+	}
+	num := func(name string, v int) {
+		if v != 0 {
+			fs = append(fs, specFlag{name, strconv.Itoa(v)})
+		}
+	}
+	boolean := func(name string, v bool) {
+		if v {
+			fs = append(fs, specFlag{name, "true"})
+		}
+	}
+	list := func(name string, vs []string) {
+		for _, v := range vs {
+			str(name, v)
+		}
+	}
+
+	str("driver", s.Driver)
+	num(cpus, s.CPUs)
+	num(memory, s.Memory)
+	if s.DiskSize != 0 {
+		str(humanReadableDiskSize, fmt.Sprintf("%dmb", s.DiskSize))
+	}
+	str(isoURL, s.MinikubeISO)
+	str(kicBaseImage, s.KicBaseImage)
+	boolean(keepContext, s.KeepContext)
+	boolean(embedCerts, s.EmbedCerts)
+	str(vpnkitSock, s.HyperkitVpnKitSock)
+	list(vsockPorts, s.HyperkitVSockPorts)
+	list("docker-env", s.DockerEnv)
+	list("docker-opt", s.DockerOpt)
+	list("insecure-registry", s.InsecureRegistry)
+	list("registry-mirror", s.RegistryMirror)
+	str(hostOnlyCIDR, s.HostOnlyCIDR)
+	str(hypervVirtualSwitch, s.HypervVirtualSwitch)
+	boolean(hypervUseExternalSwitch, s.HypervUseExternalSwitch)
+	str(hypervExternalAdapter, s.HypervExternalAdapter)
+	str(kvmNetwork, s.KVMNetwork)
+	str(kvmQemuURI, s.KVMQemuURI)
+	boolean(kvmGPU, s.KVMGPU)
+	boolean(kvmHidden, s.KVMHidden)
+	boolean(disableDriverMounts, s.DisableDriverMounts)
+	list(nfsShare, s.NFSShare)
+	str(nfsSharesRoot, s.NFSSharesRoot)
+	boolean(noVTXCheck, s.NoVTXCheck)
+	boolean(dnsProxy, s.DNSProxy)
+	boolean(hostDNSResolver, s.HostDNSResolver)
+	str(hostOnlyNicType, s.HostOnlyNicType)
+	str(natNicType, s.NatNicType)
+
+	k := s.KubernetesConfig
+	str(kubernetesVersion, k.KubernetesVersion)
+	str(apiServerName, k.APIServerName)
+	list("apiserver-names", k.APIServerNames)
+	for _, ip := range k.APIServerIPs {
+		str("apiserver-ips", ip.String())
+	}
+	str(dnsDomain, k.DNSDomain)
+	str(containerRuntime, k.ContainerRuntime)
+	str(criSocket, k.CRISocket)
+	str(networkPlugin, k.NetworkPlugin)
+	str(featureGates, k.FeatureGates)
+	str(serviceCIDR, k.ServiceCIDR)
+	str(imageRepository, k.ImageRepository)
+	for _, e := range k.ExtraOptions {
+		str("extra-config", e.String())
+	}
+	boolean(cacheImages, k.ShouldLoadCachedImages)
+	boolean(enableDefaultCNI, k.EnableDefaultCNI)
+	str(cniFlag, k.CNI)
+	num(apiServerPort, k.NodePort)
+	if s.AutoStop != 0 {
+		str(autoStop, s.AutoStop.String())
+	}
+
+	addons := []string{}
+	for name, enabled := range s.Addons {
+		if enabled {
+			addons = append(addons, name)
+		}
+	}
+	sort.Strings(addons)
+	list("addons", addons)
+
+	if s.VerifyComponents != nil {
+		wait := []string{}
+		for c, enabled := range s.VerifyComponents {
+			if enabled {
+				wait = append(wait, c)
+			}
+		}
+		sort.Strings(wait)
+		if len(wait) == 0 {
+			wait = []string{"none"}
+		}
+		str(waitComponents, strings.Join(wait, ","))
+	}
+	return fs
+}
+
+// specFlags holds the names of the flags whose values were set from a cluster spec
+var specFlags = map[string]bool{}
+
+// specNodes are the nodes described by a cluster spec, the first of which is the primary control plane
+var specNodes []config.Node
+
+// applyClusterSpec sets the start flags from a cluster spec file, validating them like 'minikube config set' does.
+// Flags passed on the command line take precedence over the spec.
+func applyClusterSpec(cmd *cobra.Command, path string) error {
+	s, err := config.LoadSpec(path)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range clusterSpecFlags(s) {
+		if cmd.Flags().Changed(f.name) {
+			glog.Infof("--%s was passed on the command line, ignoring spec value %q", f.name, f.value)
+			continue
+		}
+		if cmdcfg.IsSetting(f.name) {
+			if err := cmdcfg.Validate(f.name, f.value); err != nil {
+				return errors.Wrapf(err, "invalid value for %s", f.name)
+			}
+		}
+		fl := cmd.Flags().Lookup(f.name)
+		if fl == nil {
+			return fmt.Errorf("unknown flag %s", f.name)
+		}
+		// Unlike FlagSet.Set, this does not mark the flag as changed, so the spec is not mistaken for
+		// flags passed on the command line, which override the config of an existing cluster
+		if err := fl.Value.Set(f.value); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", f.name)
+		}
+		specFlags[f.name] = true
+	}
+	// Unchanged flags rank below the minikube config in viper, so the spec values are set in viper directly
+	for name := range specFlags {
+		fl := cmd.Flags().Lookup(name)
+		if sv, ok := fl.Value.(pflag.SliceValue); ok {
+			viper.Set(name, sv.GetSlice())
+		} else {
+			viper.Set(name, fl.Value.String())
+		}
+	}
+
+	if len(s.Nodes) == 0 {
+		return nil
+	}
+	if cmd.Flags().Changed(nodes) || cmd.Flags().Changed(controlPlanes) {
+		glog.Infof("--%s or --%s was passed on the command line, ignoring the nodes of the spec", nodes, controlPlanes)
+		return nil
+	}
+	for _, n := range s.Nodes {
+		if err := bsutil.ValidateNode(n); err != nil {
+			return errors.Wrapf(err, "node %q", n.Name)
+		}
+	}
+	specNodes = s.Nodes
+	viper.Set(nodes, len(s.Nodes))
+	viper.Set(controlPlanes, len(config.ControlPlanes(s.ClusterConfig)))
+	specFlags[nodes] = true
+	specFlags[controlPlanes] = true
+	return nil
+}
+
+// flagSpecified returns whether a flag was passed on the command line or set from a cluster spec
+func flagSpecified(cmd *cobra.Command, name string) bool {
+	return cmd.Flags().Changed(name) || specFlags[name]
+}
+
 // generateClusterConfig generate a config.ClusterConfig based on flags or existing cluster config
 func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k8sVersion string, drvName string) (config.ClusterConfig, config.Node, error) {
 	var cc config.ClusterConfig
@@ -222,7 +425,7 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 		}
 
 		mem := suggestMemoryAllocation(sysLimit, containerLimit, viper.GetInt(nodes))
-		if cmd.Flags().Changed(memory) {
+		if flagSpecified(cmd, memory) {
 			mem, err = pkgutil.CalculateSizeInMB(viper.GetString(memory))
 			if err != nil {
 				exit.WithCodeT(exit.Config, "Generate unable to parse memory '{{.memory}}': {{.error}}", out.V{"memory": viper.GetString(memory), "error": err})
@@ -245,9 +448,9 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 		// Pick good default values for --network-plugin and --enable-default-cni based on runtime.
 		selectedEnableDefaultCNI := viper.GetBool(enableDefaultCNI)
 		selectedNetworkPlugin := viper.GetString(networkPlugin)
-		if r.DefaultCNI() && !cmd.Flags().Changed(networkPlugin) {
+		if r.DefaultCNI() && !flagSpecified(cmd, networkPlugin) {
 			selectedNetworkPlugin = "cni"
-			if !cmd.Flags().Changed(enableDefaultCNI) {
+			if !flagSpecified(cmd, enableDefaultCNI) {
 				selectedEnableDefaultCNI = true
 			}
 		}
@@ -271,7 +474,7 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 			repository = autoSelectedRepository
 		}
 
-		if cmd.Flags().Changed(imageRepository) || cmd.Flags().Changed(imageMirrorCountry) {
+		if flagSpecified(cmd, imageRepository) || flagSpecified(cmd, imageMirrorCountry) {
 			out.T(out.SuccessType, "Using image repository {{.name}}", out.V{"name": repository})
 		}
 
@@ -324,8 +527,10 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 				ExtraOptions:           config.ExtraOptions,
 				ShouldLoadCachedImages: viper.GetBool(cacheImages),
 				EnableDefaultCNI:       selectedEnableDefaultCNI,
+				CNI:                    viper.GetString(cniFlag),
 				NodePort:               viper.GetInt(apiServerPort),
 			},
+			AutoStop: viper.GetDuration(autoStop),
 		}
 		cc.VerifyComponents = interpretWaitFlag(*cmd)
 	}
@@ -538,6 +743,10 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.KubernetesConfig.EnableDefaultCNI = viper.GetBool(enableDefaultCNI)
 	}
 
+	if cmd.Flags().Changed(cniFlag) {
+		cc.KubernetesConfig.CNI = viper.GetString(cniFlag)
+	}
+
 	if cmd.Flags().Changed(waitComponents) {
 		cc.VerifyComponents = interpretWaitFlag(*cmd)
 	}
@@ -546,6 +755,13 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.KicBaseImage = viper.GetString(kicBaseImage)
 	}
 
+	if cmd.Flags().Changed(autoStop) {
+		cc.AutoStop = viper.GetDuration(autoStop)
+	}
+
+	// starting a cluster cancels the stop scheduled for it
+	cc.ScheduledStop = nil
+
 	return cc
 }
 
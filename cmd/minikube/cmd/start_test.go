@@ -17,9 +17,11 @@ limitations under the License.
 package cmd
 
 import (
+	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cfg "k8s.io/minikube/pkg/minikube/config"
@@ -234,3 +236,95 @@ func TestSuggestMemoryAllocation(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterSpecFlags(t *testing.T) {
+	spec := &cfg.ClusterSpec{
+		ClusterConfig: cfg.ClusterConfig{
+			CPUs:     4,
+			Memory:   4096,
+			DiskSize: 30000,
+			Driver:   "docker",
+			KubernetesConfig: cfg.KubernetesConfig{
+				ContainerRuntime: "containerd",
+				ExtraOptions:     cfg.ExtraOptionSlice{{Component: "kubelet", Key: "max-pods", Value: "50"}},
+			},
+			Nodes:            []cfg.Node{{Name: "m01"}, {Name: "m02"}},
+			Addons:           map[string]bool{"ingress": true, "dashboard": false, "registry": true},
+			VerifyComponents: map[string]bool{"apiserver": true, "system_pods": true, "default_sa": false},
+		},
+	}
+
+	want := []specFlag{
+		{"driver", "docker"},
+		{cpus, "4"},
+		{memory, "4096"},
+		{humanReadableDiskSize, "30000mb"},
+		{containerRuntime, "containerd"},
+		{"extra-config", "kubelet.max-pods=50"},
+		{"addons", "ingress"},
+		{"addons", "registry"},
+		{waitComponents, "apiserver,system_pods"},
+	}
+	if diff := cmp.Diff(want, clusterSpecFlags(spec), cmp.AllowUnexported(specFlag{})); diff != "" {
+		t.Errorf("clusterSpecFlags mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyClusterSpec(t *testing.T) {
+	f, err := ioutil.TempFile("", "spec.*.yaml")
+	if err != nil {
+		t.Fatalf("tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	spec := `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: ClusterSpec
+CPUs: 4
+Memory: 4096
+Nodes:
+- ControlPlane: true
+  Worker: true
+- Name: db
+  Worker: true
+  CPUs: 8
+`
+	if _, err := f.WriteString(spec); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	f.Close()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Int(cpus, 2, "")
+	cmd.Flags().String(memory, "", "")
+	cmd.Flags().Int(nodes, 1, "")
+	cmd.Flags().Int(controlPlanes, 1, "")
+	if err := cmd.Flags().Set(memory, "2048"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	defer func() {
+		specFlags = map[string]bool{}
+		specNodes = nil
+		viper.Set(cpus, nil)
+		viper.Set(nodes, nil)
+		viper.Set(controlPlanes, nil)
+	}()
+
+	if err := applyClusterSpec(cmd, f.Name()); err != nil {
+		t.Fatalf("applyClusterSpec: %v", err)
+	}
+	if cmd.Flags().Changed(cpus) {
+		t.Errorf("--%s is marked as changed by the spec", cpus)
+	}
+	if !flagSpecified(cmd, cpus) || viper.GetInt(cpus) != 4 {
+		t.Errorf("--%s = %d, specified: %v, want 4 from the spec", cpus, viper.GetInt(cpus), flagSpecified(cmd, cpus))
+	}
+	if got := cmd.Flag(memory).Value.String(); got != "2048" {
+		t.Errorf("--%s = %s, want the command line value 2048", memory, got)
+	}
+	if viper.GetInt(nodes) != 2 || viper.GetInt(controlPlanes) != 1 {
+		t.Errorf("nodes = %d, control planes = %d, want 2 and 1", viper.GetInt(nodes), viper.GetInt(controlPlanes))
+	}
+	want := []cfg.Node{{ControlPlane: true, Worker: true}, {Name: "db", Worker: true, CPUs: 8}}
+	if diff := cmp.Diff(want, specNodes); diff != "" {
+		t.Errorf("spec nodes mismatch (-want +got):\n%s", diff)
+	}
+}
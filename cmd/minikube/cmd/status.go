@@ -20,24 +20,42 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
+	"github.com/mitchellh/go-ps"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/schedule"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+	"k8s.io/minikube/pkg/version"
 )
 
 var statusFormat string
@@ -57,6 +75,17 @@ const (
 	Nonexistent = "Nonexistent" // ~state.None
 	// Irrelevant is used for statuses that aren't meaningful for worker nodes
 	Irrelevant = "Irrelevant"
+
+	// # Additional states used for node conditions and addons:
+
+	// OK means the node has no unwanted conditions
+	OK = "OK"
+	// Healthy means the workloads of an addon are healthy
+	Healthy = "Healthy"
+	// Unhealthy means the workloads of an addon are not healthy
+	Unhealthy = "Unhealthy"
+	// Enabled means the addon is enabled, but has no workloads to check
+	Enabled = "Enabled"
 )
 
 // Status holds string representations of component states
@@ -67,26 +96,74 @@ type Status struct {
 	APIServer  string
 	Kubeconfig string
 	Worker     bool
+
+	// The following are only populated for running nodes
+	Runtime    string    `json:",omitempty"` // state of the container runtime
+	Paused     bool      `json:",omitempty"`
+	Pressure   string    `json:",omitempty"` // unwanted node conditions reported by Kubernetes, or OK
+	DiskUsed   int       `json:",omitempty"` // percentage of /var in use
+	MemoryUsed int       `json:",omitempty"` // percentage of memory in use
+	Versions   *Versions `json:",omitempty"`
+
+	// The following are only populated for the control plane
+	Addons        map[string]string `json:",omitempty"` // health of each enabled addon
+	Tunnels       []string          `json:",omitempty"` // routes of the tunnels running for the cluster
+	Mount         string            `json:",omitempty"` // state of the mount process
+	ScheduledStop string            `json:",omitempty"` // when the cluster is due to stop, if its watcher is running
+}
+
+// Versions holds the versions of the software in use on a node
+type Versions struct {
+	Minikube   string
+	Kubernetes string
+	Runtime    string `json:",omitempty"`
+	ISO        string `json:",omitempty"` // only used by VM drivers
+	BaseImage  string `json:",omitempty"` // only used by container drivers
 }
 
 const (
 	minikubeNotRunningStatusFlag = 1 << 0
 	clusterNotRunningStatusFlag  = 1 << 1
 	k8sNotRunningStatusFlag      = 1 << 2
+	nodeDegradedStatusFlag       = 1 << 3
+	addonsDegradedStatusFlag     = 1 << 4
 	defaultStatusFormat          = `{{.Name}}
 type: Control Plane
 host: {{.Host}}
 kubelet: {{.Kubelet}}
 apiserver: {{.APIServer}}
 kubeconfig: {{.Kubeconfig}}
+` + nodeDetailsFormat + `
+{{- if .Addons}}
+addons:{{range $name, $health := .Addons}}
+  {{$name}}: {{$health}}{{end}}{{end}}
+{{- if .Tunnels}}
+tunnels:{{range .Tunnels}}
+  {{.}}{{end}}{{end}}
+{{- if .Mount}}
+mount: {{.Mount}}{{end}}
+{{- if .ScheduledStop}}
+scheduled stop: {{.ScheduledStop}}{{end}}
 
 `
 	workerStatusFormat = `{{.Name}}
 type: Worker
 host: {{.Host}}
 kubelet: {{.Kubelet}}
+` + nodeDetailsFormat + `
 
 `
+	nodeDetailsFormat = `
+{{- if .Runtime}}
+runtime: {{.Runtime}}{{if .Paused}} (paused){{end}}{{end}}
+{{- if .Pressure}}
+conditions: {{.Pressure}}{{end}}
+{{- if .DiskUsed}}
+disk: {{.DiskUsed}}% used{{end}}
+{{- if .MemoryUsed}}
+memory: {{.MemoryUsed}}% used{{end}}
+{{- with .Versions}}
+versions: minikube {{.Minikube}}, kubernetes {{.Kubernetes}}{{if .Runtime}}, {{.Runtime}}{{end}}{{if .ISO}}, iso {{.ISO}}{{end}}{{if .BaseImage}}, base image {{.BaseImage}}{{end}}{{end}}`
 )
 
 // statusCmd represents the status command
@@ -95,47 +172,32 @@ var statusCmd = &cobra.Command{
 	Short: "Gets the status of a local Kubernetes cluster",
 	Long: `Gets the status of a local Kubernetes cluster.
 	Exit status contains the status of minikube's VM, cluster and Kubernetes encoded on it's bits in this order from right to left.
-	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for Kubernetes NOK)`,
+	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for Kubernetes NOK)
+	Degraded states are encoded on the following bits: 8 (for a node under pressure or with a stopped container runtime, or a minority of stopped control planes in a highly-available cluster) and 16 (for unhealthy addons)`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		if output != "text" && statusFormat != defaultStatusFormat {
 			exit.UsageT("Cannot use both --output and --format options")
 		}
+		output = strings.ToLower(output)
+		if output != "text" && output != "json" {
+			exit.WithCodeT(exit.BadUsage, fmt.Sprintf("invalid output format: %s. Valid values: 'text', 'json'", output))
+		}
 
 		cname := ClusterFlagValue()
 		api, cc := mustload.Partial(cname)
 
-		var statuses []*Status
-
-		if nodeName != "" || statusFormat != defaultStatusFormat && len(cc.Nodes) > 1 {
-			n, _, err := node.Retrieve(*cc, nodeName)
-			if err != nil {
-				exit.WithError("retrieving node", err)
-			}
+		if statusWatch {
+			watchStatus(api, cname, statusWatchInterval, output, os.Stdout)
+			return
+		}
 
-			st, err := status(api, *cc, *n)
-			if err != nil {
-				glog.Errorf("status error: %v", err)
-			}
-			statuses = append(statuses, st)
-		} else {
-			for _, n := range cc.Nodes {
-				glog.Infof("checking status of %s ...", n.Name)
-				machineName := driver.MachineName(*cc, n)
-				st, err := status(api, *cc, n)
-				glog.Infof("%s status: %+v", machineName, st)
-
-				if err != nil {
-					glog.Errorf("status error: %v", err)
-				}
-				if st.Host == Nonexistent {
-					glog.Errorf("The %q host does not exist!", machineName)
-				}
-				statuses = append(statuses, st)
-			}
+		statuses, err := clusterStatus(api, cc, nil)
+		if err != nil {
+			exit.WithError("retrieving node", err)
 		}
 
-		switch strings.ToLower(output) {
+		switch output {
 		case "text":
 			for _, st := range statuses {
 				if err := statusText(st, os.Stdout); err != nil {
@@ -146,31 +208,169 @@ var statusCmd = &cobra.Command{
 			if err := statusJSON(statuses, os.Stdout); err != nil {
 				exit.WithError("status json failure", err)
 			}
-		default:
-			exit.WithCodeT(exit.BadUsage, fmt.Sprintf("invalid output format: %s. Valid values: 'text', 'json'", output))
 		}
 
 		os.Exit(exitCode(statuses))
 	},
 }
 
+// clusterStatus returns the status of the node selected with --node, or of every node.
+// If runners is not nil, it is used to keep the command runners of the nodes open between calls.
+func clusterStatus(api libmachine.API, cc *config.ClusterConfig, runners runnerCache) ([]*Status, error) {
+	var statuses []*Status
+
+	if nodeName != "" || statusFormat != defaultStatusFormat && len(cc.Nodes) > 1 {
+		n, _, err := node.Retrieve(*cc, nodeName)
+		if err != nil {
+			return nil, err
+		}
+
+		st, err := status(api, *cc, *n, runners)
+		if err != nil {
+			glog.Errorf("status error: %v", err)
+		}
+		statuses = append(statuses, st)
+		addClusterDetailsOnce(*cc, statuses)
+		return statuses, nil
+	}
+
+	for _, n := range cc.Nodes {
+		glog.Infof("checking status of %s ...", n.Name)
+		machineName := driver.MachineName(*cc, n)
+		st, err := status(api, *cc, n, runners)
+		glog.Infof("%s status: %+v", machineName, st)
+
+		if err != nil {
+			glog.Errorf("status error: %v", err)
+		}
+		if st.Host == Nonexistent {
+			glog.Errorf("The %q host does not exist!", machineName)
+		}
+		statuses = append(statuses, st)
+	}
+	addClusterDetailsOnce(*cc, statuses)
+	return statuses, nil
+}
+
+// addClusterDetailsOnce adds the cluster details to the first control plane with a running API server,
+// or to the first control plane if none is running, as they are the same for every control plane.
+func addClusterDetailsOnce(cc config.ClusterConfig, statuses []*Status) {
+	var cp *Status
+	for _, st := range statuses {
+		if st.Worker {
+			continue
+		}
+		if st.APIServer == state.Running.String() {
+			cp = st
+			break
+		}
+		if cp == nil {
+			cp = st
+		}
+	}
+	if cp != nil {
+		addClusterDetails(cc, cp)
+	}
+}
+
+// runnerCache keeps the command runners of machines open between status checks
+type runnerCache map[string]command.Runner
+
+// runner returns the command runner for a host, reusing a cached one if possible
+func (rc runnerCache) runner(h *host.Host) (command.Runner, error) {
+	if r, ok := rc[h.Name]; ok {
+		return r, nil
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		return nil, err
+	}
+	if rc == nil {
+		return r, nil
+	}
+	cached := evictingRunner{Runner: r, cache: rc, name: h.Name}
+	rc[h.Name] = cached
+	return cached, nil
+}
+
+// evictingRunner removes itself from a runnerCache whenever it fails, as its connection may be broken,
+// so that the next status check connects to the machine again
+type evictingRunner struct {
+	command.Runner
+	cache runnerCache
+	name  string
+}
+
+// RunCmd runs a command, evicting the runner if it fails
+func (r evictingRunner) RunCmd(cmd *exec.Cmd) (*command.RunResult, error) {
+	rr, err := r.Runner.RunCmd(cmd)
+	r.evictOn(err)
+	return rr, err
+}
+
+// Copy copies a file, evicting the runner if it fails
+func (r evictingRunner) Copy(f assets.CopyableFile) error {
+	err := r.Runner.Copy(f)
+	r.evictOn(err)
+	return err
+}
+
+// Remove removes a file, evicting the runner if it fails
+func (r evictingRunner) Remove(f assets.CopyableFile) error {
+	err := r.Runner.Remove(f)
+	r.evictOn(err)
+	return err
+}
+
+func (r evictingRunner) evictOn(err error) {
+	if err != nil {
+		glog.Infof("evicting the runner of %s after: %v", r.name, err)
+		delete(r.cache, r.name)
+	}
+}
+
+// exitCode encodes the statuses of the nodes into the exit code of minikube status.
+// A highly-available cluster survives losing a minority of its control planes, which only degrades it.
 func exitCode(statuses []*Status) int {
+	controlPlanes, running := 0, 0
+	for _, st := range statuses {
+		if !st.Worker {
+			controlPlanes++
+			if st.APIServer == state.Running.String() {
+				running++
+			}
+		}
+	}
+	quorum := controlPlanes > 1 && running > controlPlanes/2
+
 	c := 0
 	for _, st := range statuses {
 		if st.Host != state.Running.String() {
 			c |= minikubeNotRunningStatusFlag
 		}
+		if quorum && !st.Worker && st.APIServer != state.Running.String() {
+			c |= nodeDegradedStatusFlag
+			continue
+		}
 		if (st.APIServer != state.Running.String() && st.APIServer != Irrelevant) || st.Kubelet != state.Running.String() {
 			c |= clusterNotRunningStatusFlag
 		}
 		if st.Kubeconfig != Configured && st.Kubeconfig != Irrelevant {
 			c |= k8sNotRunningStatusFlag
 		}
+		if (st.Pressure != "" && st.Pressure != OK) || (st.Runtime != "" && st.Runtime != state.Running.String()) {
+			c |= nodeDegradedStatusFlag
+		}
+		for _, health := range st.Addons {
+			if strings.HasPrefix(health, Unhealthy) {
+				c |= addonsDegradedStatusFlag
+			}
+		}
 	}
 	return c
 }
 
-func status(api libmachine.API, cc config.ClusterConfig, n config.Node) (*Status, error) {
+func status(api libmachine.API, cc config.ClusterConfig, n config.Node, runners runnerCache) (*Status, error) {
 
 	controlPlane := n.ControlPlane
 	name := driver.MachineName(cc, n)
@@ -192,12 +392,14 @@ func status(api libmachine.API, cc config.ClusterConfig, n config.Node) (*Status
 
 	// We have no record of this host. Return nonexistent struct
 	if hs == state.None.String() {
+		delete(runners, name)
 		return st, nil
 	}
 	st.Host = hs
 
 	// If it's not running, quickly bail out rather than delivering conflicting messages
 	if st.Host != state.Running.String() {
+		delete(runners, name)
 		glog.Infof("host is not running, skipping remaining checks")
 		st.APIServer = st.Host
 		st.Kubelet = st.Host
@@ -223,7 +425,7 @@ func status(api libmachine.API, cc config.ClusterConfig, n config.Node) (*Status
 		return st, err
 	}
 
-	cr, err := machine.CommandRunner(host)
+	cr, err := runners.runner(host)
 	if err != nil {
 		return st, err
 	}
@@ -232,21 +434,30 @@ func status(api libmachine.API, cc config.ClusterConfig, n config.Node) (*Status
 	glog.Infof("%s kubelet status = %s", name, stk)
 	st.Kubelet = stk.String()
 
+	addNodeDetails(cc, cr, st)
+	if st.Kubelet == state.Running.String() {
+		addNodeConditions(cc, n, st)
+	}
+
 	// Early exit for worker nodes
 	if !controlPlane {
 		return st, nil
 	}
 
+	// the kubeconfig points at the virtual IP of a highly-available cluster, while each API server is checked directly
+	if hostname, _, port, err := driver.ClusterEndpoint(&cc, &n, host.DriverName); err != nil {
+		glog.Errorf("cluster endpoint: %v", err)
+		st.Kubeconfig = Misconfigured
+	} else if err := kubeconfig.VerifyEndpoint(cc.Name, hostname, port); err != nil {
+		glog.Errorf("kubeconfig endpoint: %v", err)
+		st.Kubeconfig = Misconfigured
+	}
+
 	hostname, _, port, err := driver.ControlPlaneEndpoint(&cc, &n, host.DriverName)
 	if err != nil {
 		glog.Errorf("forwarded endpoint: %v", err)
-		st.Kubeconfig = Misconfigured
-	} else {
-		err := kubeconfig.VerifyEndpoint(cc.Name, hostname, port)
-		if err != nil {
-			glog.Errorf("kubeconfig endpoint: %v", err)
-			st.Kubeconfig = Misconfigured
-		}
+		st.APIServer = state.Error.String()
+		return st, nil
 	}
 
 	sta, err := kverify.APIServerStatus(cr, hostname, port)
@@ -258,10 +469,156 @@ func status(api libmachine.API, cc config.ClusterConfig, n config.Node) (*Status
 	} else {
 		st.APIServer = sta.String()
 	}
-
 	return st, nil
 }
 
+// addNodeDetails adds the container runtime state, resource usage and versions of a running node
+func addNodeDetails(cc config.ClusterConfig, r command.Runner, st *Status) {
+	st.Versions = &Versions{
+		Minikube:   version.GetVersion(),
+		Kubernetes: cc.KubernetesConfig.KubernetesVersion,
+	}
+	if driver.IsKIC(cc.Driver) {
+		st.Versions.BaseImage = baseImageVersion(cc.KicBaseImage)
+	} else if !driver.BareMetal(cc.Driver) {
+		st.Versions.ISO = isoVersion(cc.MinikubeISO)
+	}
+
+	if used, err := machine.DiskUsed(r, "/var"); err != nil {
+		glog.Warningf("disk usage: %v", err)
+	} else {
+		st.DiskUsed = used
+	}
+	if used, err := machine.MemoryUsed(r); err != nil {
+		glog.Warningf("memory usage: %v", err)
+	} else {
+		st.MemoryUsed = used
+	}
+
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: r})
+	if err != nil {
+		glog.Warningf("runtime: %v", err)
+		return
+	}
+	if !cr.Active() {
+		st.Runtime = state.Stopped.String()
+		return
+	}
+	st.Runtime = state.Running.String()
+
+	if v, err := cr.Version(); err != nil {
+		glog.Warningf("%s version: %v", cr.Name(), err)
+	} else {
+		st.Versions.Runtime = fmt.Sprintf("%s %s", cr.Name(), v)
+	}
+
+	paused, err := cluster.CheckIfPaused(cr, nil)
+	if err != nil {
+		glog.Warningf("paused: %v", err)
+	}
+	st.Paused = paused
+}
+
+// addNodeConditions adds the unwanted conditions Kubernetes reports for a node
+func addNodeConditions(cc config.ClusterConfig, n config.Node, st *Status) {
+	c, err := kapi.Client(cc.Name)
+	if err != nil {
+		glog.Warningf("kubernetes client: %v", err)
+		return
+	}
+	kn, err := c.CoreV1().Nodes().Get(driver.MachineName(cc, n), meta.GetOptions{})
+	if err != nil {
+		glog.Warningf("get node: %v", err)
+		return
+	}
+
+	st.Pressure = OK
+	if err := kverify.CheckNodePressure(*kn); err != nil {
+		st.Pressure = err.Error()
+	}
+}
+
+// addClusterDetails adds the health of enabled addons, and the tunnel and mount processes of the cluster
+func addClusterDetails(cc config.ClusterConfig, st *Status) {
+	if st.APIServer == state.Running.String() {
+		c, err := kapi.Client(cc.Name)
+		if err != nil {
+			glog.Warningf("kubernetes client: %v", err)
+		} else {
+			loadUserAddons()
+			st.Addons = addonHealth(c, &cc)
+		}
+	}
+
+	tunnels, err := tunnel.RunningTunnels(cc.Name)
+	if err != nil {
+		glog.Warningf("tunnels: %v", err)
+	}
+	for _, t := range tunnels {
+		st.Tunnels = append(st.Tunnels, t.Route.String())
+	}
+
+	if pid, ok := mountProcess(cc.Name); ok {
+		st.Mount = fmt.Sprintf("%s (pid %d)", state.Running, pid)
+	}
+
+	if _, ok := schedule.Running(cc.Name); ok {
+		st.ScheduledStop = schedule.Pending(cc)
+	}
+}
+
+// addonHealth returns the health of each enabled addon
+func addonHealth(c kubernetes.Interface, cc *config.ClusterConfig) map[string]string {
+	health := map[string]string{}
+	for name, a := range assets.Addons {
+		if !a.IsEnabled(cc) {
+			continue
+		}
+		checked, err := addons.Health(c, name)
+		switch {
+		case err != nil:
+			health[name] = fmt.Sprintf("%s: %v", Unhealthy, err)
+		case checked:
+			health[name] = Healthy
+		default:
+			health[name] = Enabled
+		}
+	}
+	return health
+}
+
+// mountProcess returns the pid of the mount process started by minikube for a profile, if it is running
+func mountProcess(profile string) (int, bool) {
+	b, err := ioutil.ReadFile(filepath.Join(localpath.Profile(profile), constants.MountProcessFileName))
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, false
+	}
+	p, err := ps.FindProcess(pid)
+	if err != nil || p == nil {
+		return 0, false
+	}
+	return pid, true
+}
+
+// isoVersion returns the version of an ISO from its URL, such as v1.11.0 for .../minikube-v1.11.0.iso
+func isoVersion(url string) string {
+	name := url[strings.LastIndex(url, "/")+1:]
+	return strings.TrimSuffix(strings.TrimPrefix(name, "minikube-"), ".iso")
+}
+
+// baseImageVersion returns the tag of the base image, without its digest
+func baseImageVersion(img string) string {
+	img = strings.Split(img, "@")[0]
+	if i := strings.LastIndex(img, ":"); i > strings.LastIndex(img, "/") {
+		return img[i+1:]
+	}
+	return img
+}
+
 func init() {
 	statusCmd.Flags().StringVarP(&statusFormat, "format", "f", defaultStatusFormat,
 		`Go template format string for the status output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
@@ -269,6 +626,8 @@ For the list accessible variables for the template, see the struct values here:
 	statusCmd.Flags().StringVarP(&output, "output", "o", "text",
 		`minikube status --output OUTPUT. json, text`)
 	statusCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to check status for. Defaults to control plane. Leave blank with default format for status on all nodes.")
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Keep checking the status, printing it whenever it changes. With --output=json, each status is printed as a line of JSON")
+	statusCmd.Flags().DurationVar(&statusWatchInterval, "watch-interval", defaultStatusWatchInterval, "How often to check the status with --watch")
 }
 
 func statusText(st *Status, w io.Writer) error {
@@ -32,6 +32,10 @@ func TestExitCode(t *testing.T) {
 		{"paused", 2, &Status{Host: "Running", Kubelet: "Stopped", APIServer: "Paused", Kubeconfig: Configured}},
 		{"down", 7, &Status{Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured}},
 		{"missing", 7, &Status{Host: "Nonexistent", Kubelet: "Nonexistent", APIServer: "Nonexistent", Kubeconfig: "Nonexistent"}},
+		{"healthy", 0, &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, Runtime: "Running", Pressure: OK, Addons: map[string]string{"dashboard": Healthy, "olm": Enabled}}},
+		{"pressure", 8, &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, Runtime: "Running", Pressure: "DiskPressure"}},
+		{"runtime", 8, &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, Runtime: "Stopped"}},
+		{"addons", 16, &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, Addons: map[string]string{"dashboard": Unhealthy + ": no pods found"}}},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -43,6 +47,41 @@ func TestExitCode(t *testing.T) {
 	}
 }
 
+func TestExitCodeHA(t *testing.T) {
+	running := func() *Status {
+		return &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured}
+	}
+	stopped := func() *Status {
+		return &Status{Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: "Stopped"}
+	}
+	worker := func(st *Status) *Status {
+		st.Worker = true
+		st.APIServer = Irrelevant
+		st.Kubeconfig = Irrelevant
+		return st
+	}
+
+	var tests = []struct {
+		name     string
+		want     int
+		statuses []*Status
+	}{
+		{"ok", 0, []*Status{running(), running(), running()}},
+		{"minority down", 9, []*Status{running(), stopped(), running()}},
+		{"majority down", 7, []*Status{running(), stopped(), stopped()}},
+		{"worker down", 3, []*Status{running(), running(), running(), worker(stopped())}},
+		{"single control plane", 7, []*Status{stopped(), worker(running())}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := exitCode(tc.statuses)
+			if got != tc.want {
+				t.Errorf("exitcode = %d, want: %d", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestStatusText(t *testing.T) {
 	var tests = []struct {
 		name  string
@@ -64,6 +103,20 @@ func TestStatusText(t *testing.T) {
 			state: &Status{Name: "minikube", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured},
 			want:  "minikube\ntype: Control Plane\nhost: Stopped\nkubelet: Stopped\napiserver: Stopped\nkubeconfig: Misconfigured\n\n\nWARNING: Your kubectl is pointing to stale minikube-vm.\nTo fix the kubectl context, run `minikube update-context`\n",
 		},
+		{
+			name: "details",
+			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured,
+				Runtime: "Running", Paused: true, Pressure: OK, DiskUsed: 42, MemoryUsed: 30,
+				Versions: &Versions{Minikube: "v1.11.0", Kubernetes: "v1.18.3", Runtime: "docker 19.03.8", ISO: "v1.11.0"},
+				Addons:   map[string]string{"storage-provisioner": Healthy, "dashboard": Enabled},
+				Tunnels:  []string{"10.96.0.0/12 -> 192.168.39.2"},
+				Mount:    "Running (pid 12)"},
+			want: "minikube\ntype: Control Plane\nhost: Running\nkubelet: Running\napiserver: Running\nkubeconfig: Configured\n" +
+				"runtime: Running (paused)\nconditions: OK\ndisk: 42% used\nmemory: 30% used\n" +
+				"versions: minikube v1.11.0, kubernetes v1.18.3, docker 19.03.8, iso v1.11.0\n" +
+				"addons:\n  dashboard: Enabled\n  storage-provisioner: Healthy\n" +
+				"tunnels:\n  10.96.0.0/12 -> 192.168.39.2\nmount: Running (pid 12)\n\n",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -105,3 +158,15 @@ func TestStatusJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestVersions(t *testing.T) {
+	if got := isoVersion("https://storage.googleapis.com/minikube/iso/minikube-v1.11.0.iso"); got != "v1.11.0" {
+		t.Errorf("isoVersion = %q, want v1.11.0", got)
+	}
+	if got := baseImageVersion("gcr.io/k8s-minikube/kicbase:v0.0.10@sha256:f58e0c4662bac8a9b5dda7984b185bad8502ade5d9fa364bf2755d636ab51438"); got != "v0.0.10" {
+		t.Errorf("baseImageVersion = %q, want v0.0.10", got)
+	}
+	if got := baseImageVersion("localhost:5000/kicbase"); got != "localhost:5000/kicbase" {
+		t.Errorf("baseImageVersion = %q, want the untagged image", got)
+	}
+}
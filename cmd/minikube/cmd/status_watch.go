@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+const defaultStatusWatchInterval = 5 * time.Second
+
+var (
+	statusWatch         bool
+	statusWatchInterval time.Duration
+	statusServeAddress  string
+	statusServeSocket   string
+)
+
+// statusServeCmd represents the status serve command
+var statusServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves the status of a local Kubernetes cluster over HTTP",
+	Long: `Serves the status of a local Kubernetes cluster over HTTP, on a Unix socket within the profile directory or on a local TCP address.
+	GET /status returns the status as JSON, and GET /status?watch=true streams a line of JSON whenever the status changes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		api, _ := mustload.Partial(cname)
+
+		srv := newStatusServer()
+		runners := runnerCache{}
+		b, err := statusLine(api, cname, runners)
+		if err != nil {
+			exit.WithError("status failure", err)
+		}
+		srv.set(b)
+
+		l, url, err := statusListener(cname)
+		if err != nil {
+			exit.WithError("listen failure", err)
+		}
+
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		stopped := make(chan struct{})
+		go func() {
+			<-c
+			close(stopped)
+			// Closing the listener also removes the Unix socket
+			l.Close()
+		}()
+
+		go srv.poll(api, cname, runners, statusWatchInterval)
+		out.T(out.Running, "Serving the status of {{.name}} on {{.url}}", out.V{"name": cname, "url": url})
+		err = http.Serve(l, srv)
+		select {
+		case <-stopped:
+		default:
+			exit.WithError("status server failure", err)
+		}
+	},
+}
+
+func init() {
+	statusServeCmd.Flags().StringVar(&statusServeAddress, "address", "", "Local TCP address to listen on, such as 127.0.0.1:8765, instead of a Unix socket")
+	statusServeCmd.Flags().StringVar(&statusServeSocket, "socket", "", "Path of the Unix socket to listen on. Defaults to status.sock within the profile directory")
+	statusServeCmd.Flags().DurationVar(&statusWatchInterval, "interval", defaultStatusWatchInterval, "How often to check the status")
+	statusCmd.AddCommand(statusServeCmd)
+}
+
+// watchStatus checks the status of a cluster every interval, writing it in the output format whenever it
+// changes: as text separated by blank lines, or as a line of JSON. The same libmachine API and command
+// runners are used for every check.
+func watchStatus(api libmachine.API, cname string, interval time.Duration, format string, w io.Writer) {
+	runners := runnerCache{}
+	var last []byte
+	for {
+		b, err := formatStatus(api, cname, runners, format)
+		if err != nil {
+			glog.Warningf("status failure: %v", err)
+		} else if !bytes.Equal(b, last) {
+			if last != nil && format == "text" {
+				if _, err := w.Write([]byte("\n")); err != nil {
+					exit.WithError("status write failure", err)
+				}
+			}
+			if _, err := w.Write(b); err != nil {
+				exit.WithError("status write failure", err)
+			}
+			last = b
+		}
+		time.Sleep(interval)
+	}
+}
+
+// statusLine returns the current status of a cluster as a line of JSON
+func statusLine(api libmachine.API, cname string, runners runnerCache) ([]byte, error) {
+	return formatStatus(api, cname, runners, "json")
+}
+
+// formatStatus returns the current status of a cluster as text, or as a line of JSON
+func formatStatus(api libmachine.API, cname string, runners runnerCache, format string) ([]byte, error) {
+	var statuses []*Status
+	cc, err := config.Load(cname)
+	switch {
+	case config.IsNotExist(err):
+		statuses = []*Status{{Name: cname, Host: Nonexistent, Kubelet: Nonexistent, APIServer: Nonexistent, Kubeconfig: Nonexistent}}
+	case err != nil:
+		return nil, errors.Wrap(err, "load config")
+	default:
+		statuses, err = clusterStatus(api, cc, runners)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return renderStatus(statuses, format)
+}
+
+// renderStatus writes statuses as text, or as a line of JSON
+func renderStatus(statuses []*Status, format string) ([]byte, error) {
+	var b bytes.Buffer
+	if format == "text" {
+		for _, st := range statuses {
+			if err := statusText(st, &b); err != nil {
+				return nil, err
+			}
+		}
+		return b.Bytes(), nil
+	}
+
+	if err := statusJSON(statuses, &b); err != nil {
+		return nil, err
+	}
+	b.WriteString("\n")
+	return b.Bytes(), nil
+}
+
+// statusListener listens on --address if set, or else on a Unix socket
+func statusListener(cname string) (net.Listener, string, error) {
+	if statusServeAddress != "" {
+		l, err := net.Listen("tcp", statusServeAddress)
+		if err != nil {
+			return nil, "", err
+		}
+		return l, "http://" + l.Addr().String() + "/status", nil
+	}
+
+	sock := statusServeSocket
+	if sock == "" {
+		sock = filepath.Join(localpath.Profile(cname), "status.sock")
+	}
+	// Remove the socket left behind by a previous server which did not exit cleanly
+	if err := os.Remove(sock); err != nil && !os.IsNotExist(err) {
+		return nil, "", err
+	}
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		return nil, "", err
+	}
+	return l, "unix://" + sock, nil
+}
+
+// statusServer serves the latest status of a cluster over HTTP
+type statusServer struct {
+	mu      sync.Mutex
+	latest  []byte
+	changed chan struct{} // closed when latest changes
+}
+
+func newStatusServer() *statusServer {
+	return &statusServer{changed: make(chan struct{})}
+}
+
+// set updates the latest status, notifying any watchers if it changed
+func (s *statusServer) set(b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bytes.Equal(b, s.latest) {
+		return
+	}
+	s.latest = b
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// get returns the latest status, and a channel which is closed when it changes
+func (s *statusServer) get() ([]byte, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest, s.changed
+}
+
+// poll checks the status of a cluster every interval
+func (s *statusServer) poll(api libmachine.API, cname string, runners runnerCache, interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		b, err := statusLine(api, cname, runners)
+		if err != nil {
+			glog.Warningf("status failure: %v", err)
+			continue
+		}
+		s.set(b)
+	}
+}
+
+func (s *statusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/status" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	b, changed := s.get()
+	if watch, _ := strconv.ParseBool(r.URL.Query().Get("watch")); !watch {
+		if _, err := w.Write(b); err != nil {
+			glog.Warningf("status write failure: %v", err)
+		}
+		return
+	}
+
+	flusher, canFlush := w.(http.Flusher)
+	for {
+		if _, err := w.Write(b); err != nil {
+			glog.Infof("status watcher went away: %v", err)
+			return
+		}
+		if canFlush {
+			flusher.Flush()
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+		b, changed = s.get()
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestStatusServer(t *testing.T) {
+	srv := newStatusServer()
+	srv.set([]byte("{\"Host\":\"Running\"}\n"))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/unknown")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown path returned %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	resp, err = http.Get(ts.URL + "/status")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	resp.Body.Close()
+	if err != nil || line != "{\"Host\":\"Running\"}\n" {
+		t.Errorf("status = %q (err=%v)", line, err)
+	}
+
+	resp, err = http.Get(ts.URL + "/status?watch=true")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	if line, err := r.ReadString('\n'); err != nil || line != "{\"Host\":\"Running\"}\n" {
+		t.Errorf("first watched status = %q (err=%v)", line, err)
+	}
+
+	// Unchanged statuses are not sent to watchers
+	srv.set([]byte("{\"Host\":\"Running\"}\n"))
+	srv.set([]byte("{\"Host\":\"Stopped\"}\n"))
+	if line, err := r.ReadString('\n'); err != nil || line != "{\"Host\":\"Stopped\"}\n" {
+		t.Errorf("second watched status = %q (err=%v)", line, err)
+	}
+}
+
+func TestRenderStatus(t *testing.T) {
+	statuses := []*Status{{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured}}
+
+	b, err := renderStatus(statuses, "json")
+	if err != nil {
+		t.Fatalf("render json: %v", err)
+	}
+	st := &Status{}
+	if err := json.Unmarshal(b, st); err != nil || !strings.HasSuffix(string(b), "}\n") || st.Name != "minikube" {
+		t.Errorf("json = %q (err=%v), expected a line of JSON", b, err)
+	}
+
+	b, err = renderStatus(statuses, "text")
+	if err != nil {
+		t.Fatalf("render text: %v", err)
+	}
+	if want := "minikube\ntype: Control Plane\nhost: Running\nkubelet: Running\napiserver: Running\nkubeconfig: Configured\n\n"; string(b) != want {
+		t.Errorf("text = %q, want %q", b, want)
+	}
+}
+
+func TestEvictingRunner(t *testing.T) {
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{"true": ""})
+	rc := runnerCache{}
+	r := evictingRunner{Runner: f, cache: rc, name: "m01"}
+	rc["m01"] = r
+
+	if _, err := r.RunCmd(exec.Command("true")); err != nil {
+		t.Fatalf("RunCmd: %v", err)
+	}
+	if _, ok := rc["m01"]; !ok {
+		t.Errorf("runner was evicted after a successful command")
+	}
+
+	if _, err := r.RunCmd(exec.Command("false")); err == nil {
+		t.Fatalf("expected an error for an unregistered command")
+	}
+	if _, ok := rc["m01"]; ok {
+		t.Errorf("runner was not evicted after a failed command")
+	}
+}
@@ -24,12 +24,16 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/forward"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
@@ -42,13 +46,52 @@ itself, leaving all files intact. The cluster can be started again with the "sta
 	Run: runStop,
 }
 
+var (
+	scheduledStop       time.Duration
+	cancelScheduledStop bool
+)
+
 // runStop handles the executes the flow of "minikube stop"
 func runStop(cmd *cobra.Command, args []string) {
 	cname := ClusterFlagValue()
 
+	if scheduledStop != 0 && cancelScheduledStop {
+		exit.UsageT("Cannot use both --schedule and --cancel-scheduled")
+	}
+	if scheduledStop < 0 {
+		exit.UsageT("--schedule must be a positive duration, such as 30m")
+	}
+
+	if cancelScheduledStop {
+		cancelStop(cname)
+		return
+	}
+	if scheduledStop > 0 {
+		scheduleStop(cname, scheduledStop)
+		return
+	}
+
+	if err := schedule.Cancel(cname); err != nil {
+		out.WarningT("Unable to cancel the scheduled stop: {{.error}}", out.V{"error": err})
+	}
+	stopProfile(cname)
+}
+
+// stopProfile stops every node of a cluster, and the processes minikube runs for it
+func stopProfile(cname string) {
 	api, cc := mustload.Partial(cname)
 	defer api.Close()
 
+	// Secondary control planes leave etcd before any machine stops, so that the primary control plane keeps the
+	// quorum and can restart on its own. They rejoin the cluster on start.
+	if config.IsHA(*cc) {
+		for _, n := range config.ControlPlanes(*cc) {
+			if !config.IsPrimaryControlPlane(*cc, n) {
+				node.Leave(api, *cc, n)
+			}
+		}
+	}
+
 	for _, n := range cc.Nodes {
 		machineName := driver.MachineName(*cc, n)
 		nonexistent := stop(api, machineName)
@@ -58,15 +101,64 @@ func runStop(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	if err := killMountProcess(); err != nil {
+	if cc.ScheduledStop != nil {
+		cc.ScheduledStop = nil
+		if err := config.SaveProfile(cname, cc); err != nil {
+			glog.Warningf("clear scheduled stop: %v", err)
+		}
+	}
+
+	if err := killMountProcess(cname); err != nil {
 		out.WarningT("Unable to kill mount process: {{.error}}", out.V{"error": err})
 	}
 
+	if err := forward.Stop(cname); err != nil {
+		out.WarningT("Unable to stop socket forwarder: {{.error}}", out.V{"error": err})
+	}
+
 	if err := kubeconfig.UnsetCurrentContext(cname, kubeconfig.PathFromEnv()); err != nil {
 		exit.WithError("update config", err)
 	}
 }
 
+// scheduleStop records when a cluster is due to stop, and starts the watcher that stops it
+func scheduleStop(cname string, after time.Duration) {
+	co := mustload.Running(cname)
+	co.API.Close()
+	cc := co.Config
+
+	deadline := time.Now().Add(after)
+	cc.ScheduledStop = &deadline
+	if err := config.SaveProfile(cname, cc); err != nil {
+		exit.WithError("Failed to save config", err)
+	}
+	if err := schedule.Start(cname); err != nil {
+		exit.WithError("Failed to schedule stop", err)
+	}
+	out.T(out.Waiting, "{{.name}} will stop in {{.duration}}, at {{.time}}. Cancel with 'minikube stop --cancel-scheduled'.", out.V{"name": cname, "duration": after, "time": deadline.Format(schedule.TimeFormat)})
+}
+
+// cancelStop cancels the pending stop of a cluster, both at a deadline and once idle
+func cancelStop(cname string) {
+	api, cc := mustload.Partial(cname)
+	api.Close()
+
+	if err := schedule.Cancel(cname); err != nil {
+		exit.WithError("Failed to cancel scheduled stop", err)
+	}
+	if cc.ScheduledStop == nil && cc.AutoStop == 0 {
+		out.T(out.Meh, "No stop is scheduled for {{.name}}", out.V{"name": cname})
+		return
+	}
+
+	cc.ScheduledStop = nil
+	cc.AutoStop = 0
+	if err := config.SaveProfile(cname, cc); err != nil {
+		exit.WithError("Failed to save config", err)
+	}
+	out.T(out.Stopped, "The scheduled stop of {{.name}} was cancelled", out.V{"name": cname})
+}
+
 func stop(api libmachine.API, machineName string) bool {
 	nonexistent := false
 
@@ -93,3 +185,8 @@ func stop(api libmachine.API, machineName string) bool {
 
 	return nonexistent
 }
+
+func init() {
+	stopCmd.Flags().DurationVar(&scheduledStop, "schedule", 0, "Stop the cluster after this long, such as 30m, instead of now")
+	stopCmd.Flags().BoolVar(&cancelScheduledStop, "cancel-scheduled", false, "Cancel the pending stop of the cluster, scheduled with --schedule or 'minikube start --auto-stop'")
+}
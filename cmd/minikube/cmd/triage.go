@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/problem"
+	"k8s.io/minikube/pkg/minikube/triage"
+)
+
+// triageFix is a remediation of what a probe finds
+type triageFix struct {
+	// description is what the fix does
+	description string
+	apply       func(profile string)
+}
+
+// triageFixes are the remediations that are safe to apply with 'minikube start --fix', by probe
+var triageFixes = map[string]triageFix{
+	triage.StaleContainers: {
+		description: "delete the containers left behind",
+		apply: func(profile string) {
+			deletePossibleKicLeftOver(profile, driver.Docker)
+			deletePossibleKicLeftOver(profile, driver.Podman)
+		},
+	},
+}
+
+// triageTimeout bounds how long the diagnostics of a known problem may delay exiting
+const triageTimeout = 30 * time.Second
+
+func init() {
+	exit.Triage = triageProblem
+}
+
+// triageProblem runs the diagnostic probes of a known problem, and offers the fixes of those that fail
+func triageProblem(p *problem.Problem) {
+	if len(p.Probes) == 0 {
+		return
+	}
+
+	profile := ClusterFlagValue()
+	out.ErrT(out.Empty, "")
+	out.ErrT(out.HealthCheck, "Running diagnostics ...")
+	// loading the cluster and probing it may hang, for instance on an unresponsive driver
+	results := make(chan []triage.Result, 1)
+	go func() {
+		results <- triage.Run(p.Probes, triageEnv(profile, p.Err))
+	}()
+	var rs []triage.Result
+	select {
+	case rs = <-results:
+	case <-time.After(triageTimeout):
+		out.ErrT(out.Option, "Diagnostics did not complete within {{.timeout}}, skipping them", out.V{"timeout": triageTimeout})
+		return
+	}
+
+	fixable := []string{}
+	for _, r := range rs {
+		displayProbe(r)
+		if _, ok := triageFixes[r.Name]; ok && r.Status == triage.Failed {
+			fixable = append(fixable, r.Name)
+		}
+	}
+
+	for _, n := range fixable {
+		out.ErrT(out.Workaround, `minikube can {{.fix}}: run "{{.command}}"`, out.V{"fix": triageFixes[n].description, "command": mustload.ExampleCmd(profile, "start --fix")})
+	}
+}
+
+// displayProbe displays the outcome of a probe
+func displayProbe(r triage.Result) {
+	desc := r.Description
+	if desc == "" {
+		desc = r.Name
+	}
+
+	switch r.Status {
+	case triage.Passed:
+		out.ErrT(out.Check, "{{.probe}}", out.V{"probe": desc})
+	case triage.Failed:
+		out.ErrT(out.FailureType, "{{.probe}}: {{.detail}}", out.V{"probe": desc, "detail": r.Detail})
+	default:
+		out.ErrT(out.Option, "{{.probe}}: skipped, {{.detail}}", out.V{"probe": desc, "detail": r.Detail})
+	}
+}
+
+// applyFixes runs the probes that have a fix, and applies the fixes of those that fail
+func applyFixes(profile string) {
+	names := []string{}
+	for n := range triageFixes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, r := range triage.Run(names, triageEnv(profile, nil)) {
+		if r.Status != triage.Failed {
+			continue
+		}
+		displayProbe(r)
+		f := triageFixes[r.Name]
+		out.T(out.Workaround, "Trying to {{.fix}} ...", out.V{"fix": f.description})
+		f.apply(profile)
+	}
+}
+
+// triageEnv returns what the probes of a cluster inspect, leaving out what is unavailable,
+// such as the runner of a node that is not running
+func triageEnv(profile string, cause error) triage.Env {
+	env := triage.Env{Profile: profile, Err: cause}
+	cc, err := config.Load(profile)
+	if err != nil {
+		glog.Infof("triage: unable to load config of %s: %v", profile, err)
+		return env
+	}
+	env.Config = cc
+
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		glog.Infof("triage: %v", err)
+		return env
+	}
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		glog.Infof("triage: unable to get machine client: %v", err)
+		return env
+	}
+
+	machineName := driver.MachineName(*cc, cp)
+	if st, err := machine.Status(api, machineName); err != nil || st != state.Running.String() {
+		glog.Infof("triage: %s is not running (state=%s, err=%v)", machineName, st, err)
+		return env
+	}
+	h, err := machine.LoadHost(api, machineName)
+	if err != nil {
+		glog.Infof("triage: unable to load host: %v", err)
+		return env
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		glog.Infof("triage: unable to get command runner: %v", err)
+		return env
+	}
+	env.Runner = r
+	return env
+}
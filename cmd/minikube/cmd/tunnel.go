@@ -25,19 +25,32 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/minikube/tunnel"
+	"k8s.io/minikube/pkg/minikube/tunnel/dns"
 	"k8s.io/minikube/pkg/minikube/tunnel/kic"
+	"k8s.io/minikube/pkg/minikube/tunnel/userspace"
 )
 
-var cleanup bool
+var (
+	cleanup         bool
+	userspaceTunnel bool
+	bindAddress     string
+	portOffset      int
+	dnsResponder    bool
+	dnsPort         int
+)
 
 // tunnelCmd represents the tunnel command
 var tunnelCmd = &cobra.Command{
@@ -76,6 +89,19 @@ var tunnelCmd = &cobra.Command{
 			cancel()
 		}()
 
+		if dnsResponder {
+			done := startDNS(ctx, co, clientset)
+			defer func() { <-done }()
+		}
+
+		if userspaceTunnel {
+			t := userspace.NewTunnel(ctx, bindAddress, portOffset, tunnelDialer(co), clientset.CoreV1())
+			if err := t.Start(); err != nil {
+				exit.WithError("error starting tunnel", err)
+			}
+			return
+		}
+
 		if driver.NeedsPortForward(co.Config.Driver) {
 
 			port, err := oci.ForwardedPort(oci.Docker, cname, 22)
@@ -102,6 +128,46 @@ var tunnelCmd = &cobra.Command{
 	},
 }
 
+// startDNS answers DNS queries for the hostnames of the cluster, and returns a channel that is closed
+// once the DNS configuration of the host has been restored
+func startDNS(ctx context.Context, co mustload.ClusterController, client kubernetes.Interface) <-chan struct{} {
+	domain := co.Config.KubernetesConfig.DNSDomain
+	if domain == "" {
+		domain = constants.ClusterDNSDomain
+	}
+	r := dns.NewResolver(client, co.Config.Name, domain, co.CP.IP)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		out.T(out.Connectivity, "Resolving *.{{.profile}}, services and ingress hosts from the host", out.V{"profile": dns.ProfileDomain(co.Config.Name)})
+		if err := dns.Start(ctx, co.Config.Name, r, dnsPort); err != nil {
+			out.WarningT("Unable to resolve the hostnames of the cluster: {{.error}}", out.V{"error": err})
+		}
+	}()
+	return done
+}
+
+// tunnelDialer returns how the userspace tunnel reaches services: through their NodePort if the node is
+// reachable from the host, or else over SSH
+func tunnelDialer(co mustload.ClusterController) userspace.Dialer {
+	if !driver.NeedsPortForward(co.Config.Driver) {
+		return userspace.NodePortDialer{IP: co.CP.IP.String()}
+	}
+
+	client, err := sshutil.NewSSHClient(co.CP.Host.Driver)
+	if err != nil {
+		exit.WithError("error creating ssh client", err)
+	}
+	out.WarningT("UDP ports cannot be forwarded with the {{.driver}} driver, only TCP ports will be reachable", out.V{"driver": co.Config.Driver})
+	return userspace.SSHDialer{Client: client}
+}
+
 func init() {
 	tunnelCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", true, "call with cleanup=true to remove old tunnels")
+	tunnelCmd.Flags().BoolVar(&userspaceTunnel, "userspace", false, "Forward LoadBalancer services with in-process proxies instead of routes, which does not require root")
+	tunnelCmd.Flags().BoolVar(&dnsResponder, "dns", false, "Resolve the hostnames of services and ingresses from the host, with split DNS through systemd-resolved or NetworkManager (Linux only)")
+	tunnelCmd.Flags().IntVar(&dnsPort, "dns-port", 53, "The port the DNS responder listens on. Ports other than 53 require systemd 246 or later. Used in conjunction with \"--dns\".")
+	tunnelCmd.Flags().StringVar(&bindAddress, "bind-address", "127.0.0.1", "The host IP that the userspace tunnel listens on. Used in conjunction with \"--userspace\".")
+	tunnelCmd.Flags().IntVar(&portOffset, "port-offset", 0, "Added to the service ports below 1024 that the userspace tunnel listens on, for example 8000 exposes port 80 on 8080. Used in conjunction with \"--userspace\".")
 }
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/mustload"
@@ -34,12 +35,17 @@ var updateContextCmd = &cobra.Command{
 		cname := ClusterFlagValue()
 		co := mustload.Running(cname)
 
-		updated, err := kubeconfig.UpdateEndpoint(cname, co.CP.Hostname, co.CP.Port, kubeconfig.PathFromEnv())
+		hostname, _, port, err := driver.ClusterEndpoint(co.Config, co.CP.Node, co.CP.Host.DriverName)
+		if err != nil {
+			exit.WithError("Unable to get cluster endpoint", err)
+		}
+
+		updated, err := kubeconfig.UpdateEndpoint(cname, hostname, port, kubeconfig.PathFromEnv())
 		if err != nil {
 			exit.WithError("update config", err)
 		}
 		if updated {
-			out.T(out.Celebrate, `"{{.context}}" context has been updated to point to {{.hostname}}:{{.port}}`, out.V{"context": cname, "hostname": co.CP.Hostname, "port": co.CP.Port})
+			out.T(out.Celebrate, `"{{.context}}" context has been updated to point to {{.hostname}}:{{.port}}`, out.V{"context": cname, "hostname": hostname, "port": port})
 		} else {
 			out.T(out.Meh, `No changes required for the "{{.context}}" context`, out.V{"context": cname})
 		}
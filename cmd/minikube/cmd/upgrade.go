@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/version"
+)
+
+var upgradeVersion string
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrades the Kubernetes version of a running cluster",
+	Long: `Upgrades a running cluster to a newer Kubernetes version in place, using kubeadm.
+
+The primary control plane is upgraded first, followed by the other control planes, then each worker node. Every node is drained while its kubelet is upgraded, and uncordoned afterwards. The new version is only saved to the cluster configuration once every node has been upgraded.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 || upgradeVersion == "" {
+			exit.UsageT("Usage: minikube upgrade --kubernetes-version=<version>")
+		}
+
+		co := mustload.Healthy(ClusterFlagValue())
+		ov := co.Config.KubernetesConfig.KubernetesVersion
+		nv := upgradeTarget(upgradeVersion)
+		if err := bsutil.CheckUpgrade(ov, nv); err != nil {
+			exit.WithCodeT(exit.Config, "Unable to upgrade {{.profile}} from Kubernetes {{.old}} to {{.new}}: {{.error}}", out.V{"profile": co.Config.Name, "old": ov, "new": nv, "error": err})
+		}
+
+		bs, err := cluster.ControlPlaneBootstrapper(co.API, co.Config, viper.GetString(cmdcfg.Bootstrapper))
+		if err != nil {
+			exit.WithError("Error getting cluster bootstrapper", err)
+		}
+		if err := bs.UpgradeCluster(*co.Config, nv); err != nil {
+			exit.WithError("Failed to upgrade cluster", err)
+		}
+
+		co.Config.KubernetesConfig.KubernetesVersion = nv
+		for i := range co.Config.Nodes {
+			co.Config.Nodes[i].KubernetesVersion = nv
+		}
+		if err := config.SaveProfile(co.Config.Name, co.Config); err != nil {
+			exit.WithError("Failed to save config", err)
+		}
+		out.T(out.Ready, "Upgraded {{.profile}} from Kubernetes {{.old}} to {{.new}}", out.V{"profile": co.Config.Name, "old": ov, "new": nv})
+	},
+}
+
+// upgradeTarget returns the Kubernetes version requested by the user, in canonical form
+func upgradeTarget(v string) string {
+	if strings.EqualFold(v, "stable") {
+		v = constants.DefaultKubernetesVersion
+	} else if strings.EqualFold(v, "latest") {
+		v = constants.NewestKubernetesVersion
+	}
+
+	sv, err := semver.Make(strings.TrimPrefix(v, version.VersionPrefix))
+	if err != nil {
+		exit.WithCodeT(exit.Data, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": v, "error": err})
+	}
+	return version.VersionPrefix + sv.String()
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeVersion, "kubernetes-version", "", fmt.Sprintf("The Kubernetes version to upgrade to (ex: v1.2.3, 'stable' for %s, 'latest' for %s)", constants.DefaultKubernetesVersion, constants.NewestKubernetesVersion))
+}
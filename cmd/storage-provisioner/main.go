@@ -25,7 +25,11 @@ import (
 	"k8s.io/minikube/pkg/storage"
 )
 
-var pvDir = "/tmp/hostpath-provisioner"
+var (
+	pvDir    = "/tmp/hostpath-provisioner"
+	nodeName = flag.String("node-name", os.Getenv("NODE_NAME"), "The name of the node this provisioner creates volumes on")
+	hostRoot = flag.String("host-root", "/", "Where the root filesystem of the node is mounted")
+)
 
 func main() {
 	// Glog requires that /tmp exists.
@@ -35,7 +39,7 @@ func main() {
 	}
 	flag.Parse()
 
-	if err := storage.StartStorageProvisioner(pvDir); err != nil {
+	if err := storage.StartStorageProvisioner(pvDir, *nodeName, *hostRoot); err != nil {
 		glog.Exit(err)
 	}
 
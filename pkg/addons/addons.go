@@ -34,6 +34,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -76,21 +77,96 @@ func Set(cc *config.ClusterConfig, name string, value string) error {
 
 // SetAndSave sets a value and saves the config
 func SetAndSave(profile string, name string, value string) error {
+	return setAndSave(profile, name, value, nil)
+}
+
+// EnableAndSave enables an addon with the given values and saves the config. The values are validated
+// before anything is enabled, and only saved along with the addon once it is enabled.
+func EnableAndSave(profile string, name string, values map[string]string) error {
+	if len(values) > 0 {
+		if _, err := validValues(name, values); err != nil {
+			return errors.Wrap(err, "invalid values")
+		}
+	}
+	return setAndSave(profile, name, "true", values)
+}
+
+func setAndSave(profile string, name string, value string, values map[string]string) error {
 	cc, err := config.Load(profile)
 	if err != nil {
 		return errors.Wrap(err, "loading profile")
 	}
 
-	if err := RunCallbacks(cc, name, value); err != nil {
-		return errors.Wrap(err, "run callbacks")
+	names := []string{name}
+	if enable, err := strconv.ParseBool(value); err == nil {
+		if enable {
+			names, err = requirements(cc, name)
+			if err != nil {
+				return errors.Wrap(err, "requirements")
+			}
+			for _, n := range names {
+				if err := checkConflicts(cc, n, names); err != nil {
+					return err
+				}
+			}
+		} else if err := checkDependents(cc, name); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range names {
+		if n != name {
+			out.T(out.AddonEnable, "Enabling '{{.name}}', which is required by '{{.addon}}'", out.V{"name": n, "addon": name})
+		}
+		// the values are needed by the callbacks of the addon, which are only run after its requirements are saved
+		if n == name && len(values) > 0 {
+			if err := SetValues(cc, name, values); err != nil {
+				return errors.Wrap(err, "set values")
+			}
+		}
+		if err := RunCallbacks(cc, n, value); err != nil {
+			return errors.Wrap(err, "run callbacks")
+		}
+
+		if err := Set(cc, n, value); err != nil {
+			return errors.Wrap(err, "set")
+		}
+
+		// Save each addon once its callbacks succeed, so that the config matches the cluster if a later one fails
+		glog.Infof("Writing out %q config to set %s=%v...", profile, n, value)
+		if err := config.Write(profile, cc); err != nil {
+			return errors.Wrap(err, "save")
+		}
 	}
+	return nil
+}
 
-	if err := Set(cc, name, value); err != nil {
-		return errors.Wrap(err, "set")
+// SetValues validates values against those declared by an addon and merges them into the config (not threadsafe)
+func SetValues(cc *config.ClusterConfig, name string, values map[string]string) error {
+	valid, err := validValues(name, values)
+	if err != nil {
+		return err
 	}
 
-	glog.Infof("Writing out %q config to set %s=%v...", profile, name, value)
-	return config.Write(profile, cc)
+	if cc.AddonValues == nil {
+		cc.AddonValues = map[string]map[string]string{}
+	}
+	if cc.AddonValues[name] == nil {
+		cc.AddonValues[name] = map[string]string{}
+	}
+	for k, v := range valid {
+		cc.AddonValues[name][k] = v
+	}
+	return nil
+}
+
+// validValues validates values against those declared by an addon
+func validValues(name string, values map[string]string) (map[string]string, error) {
+	addon, ok := assets.Addons[name]
+	if !ok {
+		return nil, errors.Errorf("%s is not a valid addon", name)
+	}
+	return addon.ValidateValues(values)
 }
 
 // Runs all the validation or callback functions and collects errors
@@ -195,7 +271,7 @@ https://github.com/kubernetes/minikube/issues/7332`, out.V{"driver_name": cc.Dri
 		return errors.Wrap(err, "command runner")
 	}
 
-	data := assets.GenerateTemplateData(cc.KubernetesConfig)
+	data := assets.GenerateTemplateData(cc.KubernetesConfig, addon.ResolveValues(cc))
 	return enableOrDisableAddonInternal(cc, addon, cmd, data, enable)
 }
 
@@ -215,6 +291,18 @@ func isAddonAlreadySet(cc *config.ClusterConfig, addon *assets.Addon, enable boo
 func enableOrDisableAddonInternal(cc *config.ClusterConfig, addon *assets.Addon, cmd command.Runner, data interface{}, enable bool) error {
 	deployFiles := []string{}
 
+	if enable && len(addon.Images) > 0 {
+		r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: cmd})
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		for _, img := range addon.Images {
+			if err := r.PullImage(img); err != nil {
+				return errors.Wrapf(err, "pulling %s", img)
+			}
+		}
+	}
+
 	for _, addon := range addon.Assets {
 		var f assets.CopyableFile
 		var err error
@@ -343,26 +431,51 @@ func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]boo
 			toEnableList = append(toEnableList, k)
 		}
 	}
-	sort.Strings(toEnableList)
 
-	var awg sync.WaitGroup
+	groups, err := enableOrder(toEnableList)
+	if err != nil {
+		out.WarningT("Unable to order addons: {{.error}}", out.V{"error": err})
+		sort.Strings(toEnableList)
+		groups = [][]string{toEnableList}
+	}
+
+	// Skip addons which conflict with one enabled before them
+	toEnableList = []string{}
+	for i, g := range groups {
+		keep := []string{}
+		for _, name := range g {
+			if c := conflictsWith(name, toEnableList); c != "" {
+				out.WarningT("Skipping '{{.name}}', as it conflicts with '{{.other}}'", out.V{"name": name, "other": c})
+				continue
+			}
+			keep = append(keep, name)
+			toEnableList = append(toEnableList, name)
+		}
+		groups[i] = keep
+	}
+	sort.Strings(toEnableList)
 
 	defer func() { // making it show after verifications( not perfect till #7613 is closed)
 		out.T(out.AddonEnable, "Enabled addons: {{.addons}}", out.V{"addons": strings.Join(toEnableList, ", ")})
 	}()
-	for _, a := range toEnableList {
-		awg.Add(1)
-		go func(name string) {
-			err := RunCallbacks(cc, name, "true")
-			if err != nil {
-				out.WarningT("Enabling '{{.name}}' returned an error: {{.error}}", out.V{"name": name, "error": err})
-			}
-			awg.Done()
-		}(a)
+
+	// Addons are enabled concurrently, but only after the addons they require
+	for _, g := range groups {
+		var awg sync.WaitGroup
+		for _, a := range g {
+			awg.Add(1)
+			go func(name string) {
+				err := RunCallbacks(cc, name, "true")
+				if err != nil {
+					out.WarningT("Enabling '{{.name}}' returned an error: {{.error}}", out.V{"name": name, "error": err})
+				}
+				awg.Done()
+			}(a)
+		}
+		awg.Wait()
 	}
 
-	// Wait until all of the addons are enabled before updating the config (not thread safe)
-	awg.Wait()
+	// Update the config once all of the addons are enabled (not thread safe)
 	for _, a := range toEnableList {
 		if err := Set(cc, a, "true"); err != nil {
 			glog.Errorf("store failed: %v", err)
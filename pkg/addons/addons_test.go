@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
@@ -121,6 +122,61 @@ func TestSetAndSave(t *testing.T) {
 	}
 }
 
+func TestEnableAndSave(t *testing.T) {
+	profile := createTestProfile(t)
+
+	if err := EnableAndSave(profile, "ingress", map[string]string{"replicas": "two"}); err == nil {
+		t.Errorf("expected error for invalid int value")
+	}
+	if err := EnableAndSave(profile, "ingress", map[string]string{"unknown": "x"}); err == nil {
+		t.Errorf("expected error for undeclared value")
+	}
+	c, err := config.DefaultLoader.LoadConfigFromFile(profile)
+	if err != nil {
+		t.Fatalf("unable to load profile: %v", err)
+	}
+	if c.Addons["ingress"] || len(c.AddonValues["ingress"]) != 0 {
+		t.Errorf("invalid values enabled ingress or were saved: addons=%v values=%v", c.Addons, c.AddonValues)
+	}
+
+	if err := EnableAndSave(profile, "ingress", map[string]string{"replicas": "2", "hostNetwork": "1"}); err != nil {
+		t.Fatalf("EnableAndSave returned unexpected error: %v", err)
+	}
+
+	c, err = config.DefaultLoader.LoadConfigFromFile(profile)
+	if err != nil {
+		t.Fatalf("unable to load profile: %v", err)
+	}
+	if !c.Addons["ingress"] {
+		t.Errorf("expected ingress to be enabled")
+	}
+	ingress := assets.Addons["ingress"]
+	values := ingress.ResolveValues(c)
+	if values["replicas"] != "2" || values["hostNetwork"] != "true" {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	var dp *assets.BinAsset
+	for _, a := range ingress.Assets {
+		if a.GetTargetName() == "ingress-dp.yaml" {
+			dp = a
+		}
+	}
+	f, err := dp.Evaluate(assets.GenerateTemplateData(c.KubernetesConfig, values))
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	for _, want := range []string{"replicas: 2\n", "hostNetwork: true\n", "dnsPolicy: ClusterFirstWithHostNet\n"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected rendered deployment to contain %q", want)
+		}
+	}
+}
+
 func TestStart(t *testing.T) {
 	cc := &config.ClusterConfig{
 		Name:             "start",
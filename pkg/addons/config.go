@@ -26,6 +26,12 @@ type Addon struct {
 	set         func(*config.ClusterConfig, string, string) error
 	validations []setFn
 	callbacks   []setFn
+	// requires are the addons which must be enabled before this one
+	requires []string
+	// conflicts are the addons which may not be enabled at the same time as this one, in either direction
+	conflicts []string
+	// workloads must be healthy for the addon to be considered ready
+	workloads []workload
 }
 
 // Addons is a list of all addons
@@ -34,6 +40,10 @@ var Addons = []*Addon{
 		name:      "dashboard",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{
+			{namespace: "kubernetes-dashboard", deployment: "kubernetes-dashboard"},
+			{namespace: "kubernetes-dashboard", selector: "k8s-app=kubernetes-dashboard"},
+		},
 	},
 
 	{
@@ -56,31 +66,44 @@ var Addons = []*Addon{
 		set:         SetBool,
 		validations: []setFn{IsRuntimeContainerd},
 		callbacks:   []setFn{enableOrDisableAddon},
+		workloads:   []workload{{namespace: "kube-system", selector: "kubernetes.io/minikube-addons=gvisor"}},
 	},
 	{
 		name:      "helm-tiller",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{
+			{namespace: "kube-system", deployment: "tiller-deploy"},
+			{namespace: "kube-system", selector: "app=helm"},
+		},
 	},
 	{
 		name:      "ingress",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		conflicts: []string{"ambassador"},
+		workloads: []workload{
+			{namespace: "kube-system", deployment: "ingress-nginx-controller"},
+			{namespace: "kube-system", selector: "app.kubernetes.io/name=ingress-nginx,app.kubernetes.io/component=controller"},
+		},
 	},
 	{
 		name:      "ingress-dns",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{{namespace: "kube-system", selector: "app=minikube-ingress-dns"}},
 	},
 	{
 		name:      "istio-provisioner",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{{namespace: "istio-operator", deployment: "istio-operator"}},
 	},
 	{
 		name:      "istio",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		requires:  []string{"istio-provisioner"},
 	},
 	{
 		name: "logviewer",
@@ -90,6 +113,10 @@ var Addons = []*Addon{
 		name:      "metrics-server",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{
+			{namespace: "kube-system", deployment: "metrics-server"},
+			{namespace: "kube-system", selector: "k8s-app=metrics-server"},
+		},
 	},
 	{
 		name:      "nvidia-driver-installer",
@@ -105,28 +132,39 @@ var Addons = []*Addon{
 		name:      "olm",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{
+			{namespace: "olm", deployment: "catalog-operator"},
+			{namespace: "olm", deployment: "olm-operator"},
+			{namespace: "olm", deployment: "packageserver"},
+		},
 	},
 	{
 		name:      "registry",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{
+			{namespace: "kube-system", selector: "actual-registry=true"},
+			{namespace: "kube-system", selector: "registry-proxy=true"},
+		},
 	},
 	{
 		name:      "registry-creds",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{{namespace: "kube-system", deployment: "registry-creds"}},
 	},
 	{
 		name:      "registry-aliases",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		requires:  []string{"registry"},
 		//TODO - add other settings
-		//TODO check if registry addon is enabled
 	},
 	{
 		name:      "storage-provisioner",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{{namespace: "kube-system", selector: "integration-test=storage-provisioner"}},
 	},
 	{
 		name:      "storage-provisioner-gluster",
@@ -137,6 +175,7 @@ var Addons = []*Addon{
 		name:      "metallb",
 		set:       SetBool,
 		callbacks: []setFn{enableOrDisableAddon},
+		workloads: []workload{{namespace: "metallb-system", selector: "app=metallb"}},
 	},
 	{
 		name:      "ambassador",
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// enableOrder returns the given addons along with the addons they require, grouped so that each addon
+// is in a later group than its requirements. Addons within a group may be enabled concurrently.
+func enableOrder(names []string) ([][]string, error) {
+	depth := map[string]int{}
+	visiting := map[string]bool{}
+
+	var visit func(name string) (int, error)
+	visit = func(name string) (int, error) {
+		if d, ok := depth[name]; ok {
+			return d, nil
+		}
+		if visiting[name] {
+			return 0, errors.Errorf("addon %s has a circular dependency", name)
+		}
+		a, valid := isAddonValid(name)
+		if !valid {
+			return 0, errors.Errorf("%s is not a valid addon", name)
+		}
+
+		visiting[name] = true
+		d := 0
+		for _, r := range a.requires {
+			rd, err := visit(r)
+			if err != nil {
+				return 0, errors.Wrapf(err, "required by %s", name)
+			}
+			if rd+1 > d {
+				d = rd + 1
+			}
+		}
+		visiting[name] = false
+		depth[name] = d
+		return d, nil
+	}
+
+	for _, name := range names {
+		if _, err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	groups := [][]string{}
+	for name, d := range depth {
+		for len(groups) <= d {
+			groups = append(groups, []string{})
+		}
+		groups[d] = append(groups[d], name)
+	}
+	for _, g := range groups {
+		sort.Strings(g)
+	}
+	return groups, nil
+}
+
+// requirements returns the addons which must be enabled for name to be enabled, in order, ending with name.
+// Requirements which are already enabled are skipped.
+func requirements(cc *config.ClusterConfig, name string) ([]string, error) {
+	groups, err := enableOrder([]string{name})
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, g := range groups {
+		for _, n := range g {
+			if n == name || !isEnabled(cc, n) {
+				names = append(names, n)
+			}
+		}
+	}
+	return names, nil
+}
+
+// conflicting returns whether either addon declares a conflict with the other
+func conflicting(a, b string) bool {
+	for _, x := range []*Addon{findAddon(a), findAddon(b)} {
+		if x == nil {
+			continue
+		}
+		for _, c := range x.conflicts {
+			if c == a || c == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkConflicts returns an error if name conflicts with an addon which is enabled, or about to be
+func checkConflicts(cc *config.ClusterConfig, name string, enabling []string) error {
+	for _, a := range Addons {
+		if a.name == name || !conflicting(name, a.name) {
+			continue
+		}
+		if isEnabled(cc, a.name) || contains(enabling, a.name) {
+			return errors.Errorf("the %s addon conflicts with the %s addon, which is enabled. Disable it first with: minikube addons disable %s", name, a.name, a.name)
+		}
+	}
+	return nil
+}
+
+// conflictsWith returns the first of names which conflicts with name, if any
+func conflictsWith(name string, names []string) string {
+	for _, n := range names {
+		if conflicting(name, n) {
+			return n
+		}
+	}
+	return ""
+}
+
+// checkDependents returns an error if an enabled addon requires name
+func checkDependents(cc *config.ClusterConfig, name string) error {
+	for _, a := range Addons {
+		if !isEnabled(cc, a.name) {
+			continue
+		}
+		if contains(a.requires, name) {
+			return errors.Errorf("the %s addon is required by the %s addon, which is enabled. Disable it first with: minikube addons disable %s", name, a.name, a.name)
+		}
+	}
+	return nil
+}
+
+func findAddon(name string) *Addon {
+	a, _ := isAddonValid(name)
+	return a
+}
+
+func isEnabled(cc *config.ClusterConfig, name string) bool {
+	a, ok := assets.Addons[name]
+	return ok && a.IsEnabled(cc)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
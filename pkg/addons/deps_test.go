@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestEnableOrder(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  [][]string
+	}{
+		{[]string{"dashboard"}, [][]string{{"dashboard"}}},
+		{[]string{"istio", "dashboard"}, [][]string{{"dashboard", "istio-provisioner"}, {"istio"}}},
+		{[]string{"registry-aliases", "registry"}, [][]string{{"registry"}, {"registry-aliases"}}},
+	}
+	for _, tc := range tests {
+		got, err := enableOrder(tc.names)
+		if err != nil {
+			t.Fatalf("enableOrder(%v): %v", tc.names, err)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("enableOrder(%v) mismatch (-want +got):\n%s", tc.names, diff)
+		}
+	}
+
+	if _, err := enableOrder([]string{"InvalidAddon"}); err == nil {
+		t.Errorf("expected error for unknown addon")
+	}
+
+	numAddons := len(Addons)
+	defer func() { Addons = Addons[:numAddons] }()
+	Addons = append(Addons, &Addon{name: "a", requires: []string{"b"}}, &Addon{name: "b", requires: []string{"a"}})
+	if _, err := enableOrder([]string{"a"}); err == nil {
+		t.Errorf("expected error for circular dependency")
+	}
+}
+
+func TestRequirementsAndConflicts(t *testing.T) {
+	cc := &config.ClusterConfig{Name: "test", Addons: map[string]bool{"istio-provisioner": true, "ambassador": true}}
+
+	got, err := requirements(cc, "istio")
+	if err != nil {
+		t.Fatalf("requirements: %v", err)
+	}
+	if diff := cmp.Diff([]string{"istio"}, got); diff != "" {
+		t.Errorf("requirements mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := checkConflicts(cc, "ingress", nil); err == nil {
+		t.Errorf("expected ingress to conflict with enabled ambassador")
+	}
+	if err := checkConflicts(cc, "ambassador", []string{"ingress"}); err == nil {
+		t.Errorf("expected ambassador to conflict with ingress being enabled")
+	}
+	if err := checkConflicts(cc, "dashboard", nil); err != nil {
+		t.Errorf("unexpected conflict: %v", err)
+	}
+
+	cc.Addons["istio"] = true
+	if err := checkDependents(cc, "istio-provisioner"); err == nil {
+		t.Errorf("expected error disabling istio-provisioner while istio is enabled")
+	}
+	if err := checkDependents(cc, "dashboard"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/hashicorp/go-getter"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+var (
+	loadUserAddons sync.Once
+	// userAddons maps the names of the registered user-defined addons to their directory
+	userAddons = map[string]string{}
+)
+
+// UserAddonsDir returns the directory user-defined addons are discovered in
+func UserAddonsDir() string {
+	return localpath.MakeMiniPath("addons")
+}
+
+// LoadUserAddons registers the user-defined addons found in UserAddonsDir, so that they can be managed
+// like the bundled addons. Addons which are invalid, or conflict with an existing addon, are skipped.
+func LoadUserAddons() error {
+	var err error
+	loadUserAddons.Do(func() {
+		err = registerUserAddons(UserAddonsDir())
+	})
+	return err
+}
+
+// registerUserAddons registers the user-defined addons found in a directory
+func registerUserAddons(root string) error {
+	ms, err := assets.LoadAddonManifests(root)
+	for dir, m := range ms {
+		if _, exists := assets.Addons[m.Name]; exists {
+			glog.Warningf("skipping user-defined addon %s in %s: an addon with this name already exists", m.Name, dir)
+			continue
+		}
+		a, aerr := m.Addon(dir)
+		if aerr != nil {
+			glog.Warningf("skipping user-defined addon %s in %s: %v", m.Name, dir, aerr)
+			continue
+		}
+
+		glog.Infof("registering user-defined addon %s from %s", m.Name, dir)
+		assets.Addons[m.Name] = a
+		userAddons[m.Name] = dir
+		addon := &Addon{
+			name:      m.Name,
+			set:       SetBool,
+			callbacks: []setFn{enableOrDisableAddon},
+			requires:  m.Requires,
+			conflicts: m.Conflicts,
+		}
+		if m.ContainerRuntime != "" {
+			addon.validations = []setFn{isRuntime(m.ContainerRuntime)}
+		}
+		Addons = append(Addons, addon)
+	}
+	return err
+}
+
+// isRuntime returns a validator which returns an error if the current runtime is not the given one
+func isRuntime(want string) setFn {
+	return func(cc *config.ClusterConfig, name string, _ string) error {
+		w, err := cruntime.New(cruntime.Config{Type: want})
+		if err != nil {
+			return errors.Wrapf(err, "addon %s", name)
+		}
+		r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime})
+		if err != nil {
+			return err
+		}
+		if r.Name() != w.Name() {
+			return fmt.Errorf("the %s addon requires the %s container runtime, but this cluster uses %s", name, w.Name(), r.Name())
+		}
+		return nil
+	}
+}
+
+// Install fetches a user-defined addon from a URL or path, such as a git repository, into UserAddonsDir.
+// Any previously installed version of the addon is replaced.
+func Install(src string) (*assets.AddonManifest, error) {
+	tmp, err := ioutil.TempDir(localpath.MakeMiniPath("cache"), "addon")
+	if err != nil {
+		return nil, errors.Wrap(err, "tempdir")
+	}
+	defer os.RemoveAll(tmp)
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "getwd")
+	}
+
+	fetched := filepath.Join(tmp, "addon")
+	client := &getter.Client{
+		Src:  src,
+		Dst:  fetched,
+		Pwd:  pwd,
+		Mode: getter.ClientModeDir,
+	}
+	glog.Infof("Fetching addon: %s -> %s", src, fetched)
+	if err := client.Get(); err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", src)
+	}
+
+	m, err := assets.LoadAddonManifest(fetched)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading %s", src)
+	}
+	if _, err := m.Addon(fetched); err != nil {
+		return nil, errors.Wrapf(err, "loading %s", src)
+	}
+	if _, exists := assets.Addons[m.Name]; exists && userAddons[m.Name] == "" {
+		return nil, fmt.Errorf("the bundled %s addon can not be replaced", m.Name)
+	}
+
+	dst := filepath.Join(UserAddonsDir(), m.Name)
+	if err := os.RemoveAll(dst); err != nil {
+		return nil, errors.Wrapf(err, "removing %s", dst)
+	}
+	if err := os.Rename(fetched, dst); err != nil {
+		return nil, errors.Wrapf(err, "installing %s", dst)
+	}
+	return m, nil
+}
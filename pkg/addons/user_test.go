@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+func writeAddon(t *testing.T, dir string, files map[string]string) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestRegisterUserAddons(t *testing.T) {
+	root, err := ioutil.TempDir("", "addons")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	writeAddon(t, filepath.Join(root, "hello"), map[string]string{
+		assets.AddonManifestFile: `name: hello
+containerRuntime: containerd
+images:
+- hello:1.0
+manifests:
+- source: hello-dp.yaml.tmpl
+- source: hello-svc.yaml
+  targetName: svc.yaml
+  permissions: "0644"
+`,
+		"hello-dp.yaml.tmpl": "image: {{.ImageRepository}}hello:1.0\n",
+		"hello-svc.yaml":     "kind: Service\n",
+	})
+	writeAddon(t, filepath.Join(root, "broken"), map[string]string{
+		assets.AddonManifestFile: "name: broken\nmanifests:\n- source: ../outside.yaml\n",
+	})
+	writeAddon(t, filepath.Join(root, "escape"), map[string]string{
+		assets.AddonManifestFile: "name: escape\nmanifests:\n- source: unit.service\n  targetDir: /etc/kubernetes/addons/../../systemd/system\n",
+		"unit.service":           "[Service]\n",
+	})
+	writeAddon(t, filepath.Join(root, "shadow"), map[string]string{
+		assets.AddonManifestFile: "name: dashboard\nmanifests:\n- source: dp.yaml\n",
+		"dp.yaml":                "kind: Deployment\n",
+	})
+
+	builtin := assets.Addons["dashboard"]
+	numAddons := len(Addons)
+	defer func() {
+		delete(assets.Addons, "hello")
+		delete(userAddons, "hello")
+		assets.Addons["dashboard"] = builtin
+		Addons = Addons[:numAddons]
+	}()
+
+	err = registerUserAddons(root)
+	if err == nil || !strings.Contains(err.Error(), "broken") || !strings.Contains(err.Error(), "escape") {
+		t.Errorf("registerUserAddons() error = %v, want an error about the broken and escape addons", err)
+	}
+	if _, ok := assets.Addons["escape"]; ok {
+		t.Errorf("registered an addon copying files outside of %s", vmpath.GuestAddonsDir)
+	}
+	if assets.Addons["dashboard"] != builtin {
+		t.Errorf("user-defined addon replaced the bundled dashboard addon")
+	}
+
+	a, valid := isAddonValid("hello")
+	if !valid {
+		t.Fatalf("user-defined addon is not valid")
+	}
+	if err := run(&config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}, "hello", "true", a.validations); err == nil {
+		t.Errorf("expected validation error enabling a containerd addon with docker")
+	}
+	if err := run(&config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "containerd"}}, "hello", "true", a.validations); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	addon := assets.Addons["hello"]
+	if addon.IsEnabled(&config.ClusterConfig{}) {
+		t.Errorf("user-defined addon should be disabled by default")
+	}
+	if len(addon.Images) != 1 || addon.Images[0] != "hello:1.0" {
+		t.Errorf("Images = %v, want [hello:1.0]", addon.Images)
+	}
+	if len(addon.Assets) != 2 {
+		t.Fatalf("got %d assets, want 2", len(addon.Assets))
+	}
+
+	dp := addon.Assets[0]
+	if !dp.IsTemplate() || dp.GetTargetName() != "hello-dp.yaml" || dp.GetTargetDir() != vmpath.GuestAddonsDir || dp.GetPermissions() != "0640" {
+		t.Errorf("unexpected template asset: %+v", dp.BaseAsset)
+	}
+	svc := addon.Assets[1]
+	if svc.IsTemplate() || svc.GetTargetName() != "svc.yaml" || svc.GetPermissions() != "0644" {
+		t.Errorf("unexpected asset: %+v", svc.BaseAsset)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// VerifyTimeout is how long to wait for the workloads of an addon to become healthy
+var VerifyTimeout = 6 * time.Minute
+
+// workload is a deployment which must be stable, or a set of pods which must be running
+type workload struct {
+	namespace  string
+	deployment string
+	selector   string
+}
+
+func (w workload) String() string {
+	if w.deployment != "" {
+		return fmt.Sprintf("deployment %s/%s", w.namespace, w.deployment)
+	}
+	return fmt.Sprintf("pods %q in %s", w.selector, w.namespace)
+}
+
+// wait waits for the workload to become healthy
+func (w workload) wait(c kubernetes.Interface, timeout time.Duration) error {
+	if w.deployment != "" {
+		return kapi.WaitForDeploymentToStabilize(c, w.namespace, w.deployment, timeout)
+	}
+	sel, err := labels.Parse(w.selector)
+	if err != nil {
+		return errors.Wrapf(err, "selector %q", w.selector)
+	}
+	return kapi.WaitForPodsWithLabelRunning(c, w.namespace, sel, timeout)
+}
+
+// check returns an error if the workload is not currently healthy
+func (w workload) check(c kubernetes.Interface) error {
+	if w.deployment != "" {
+		dp, err := c.AppsV1().Deployments(w.namespace).Get(w.deployment, meta.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if dp.Spec.Replicas != nil && dp.Status.ReadyReplicas < *dp.Spec.Replicas {
+			return fmt.Errorf("%s has %d of %d replicas ready", w, dp.Status.ReadyReplicas, *dp.Spec.Replicas)
+		}
+		return nil
+	}
+
+	pods, err := c.CoreV1().Pods(w.namespace).List(meta.ListOptions{LabelSelector: w.selector})
+	if err != nil {
+		return err
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("no %s found", w)
+	}
+	for _, p := range pods.Items {
+		if p.Status.Phase != core.PodRunning {
+			return fmt.Errorf("pod %s/%s is %s", w.namespace, p.Name, p.Status.Phase)
+		}
+	}
+	return nil
+}
+
+// podSelector returns the selector of the pods which make up the workload
+func (w workload) podSelector(c kubernetes.Interface) (string, error) {
+	if w.deployment == "" {
+		return w.selector, nil
+	}
+	dp, err := c.AppsV1().Deployments(w.namespace).Get(w.deployment, meta.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	sel, err := meta.LabelSelectorAsSelector(dp.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	return sel.String(), nil
+}
+
+// Verify waits for the workloads of an addon, and of the addons it requires, to become healthy.
+// The error describes the workloads which did not, and the state of their pods.
+func Verify(cc *config.ClusterConfig, name string, timeout time.Duration) error {
+	groups, err := enableOrder([]string{name})
+	if err != nil {
+		return err
+	}
+
+	c, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+
+	deadline := time.Now().Add(timeout)
+	failed := []string{}
+	for _, g := range groups {
+		for _, n := range g {
+			a, _ := isAddonValid(n)
+			for _, w := range a.workloads {
+				start := time.Now()
+				err := w.wait(c, time.Until(deadline))
+				glog.Infof("duration metric: took %s to verify %s for addon %s: err=%v", time.Since(start), w, n, err)
+				if err != nil {
+					failed = append(failed, fmt.Sprintf("%s: %s is not healthy: %v%s", n, w, err, describePods(c, w)))
+				}
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("addon %s is not healthy:\n%s", name, strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// Health returns whether an addon has workloads to check and, if so, an error describing the first which is
+// not currently healthy. Unlike Verify, it does not wait.
+func Health(c kubernetes.Interface, name string) (bool, error) {
+	a, valid := isAddonValid(name)
+	if !valid {
+		return false, errors.Errorf("%s is not a valid addon", name)
+	}
+	for _, w := range a.workloads {
+		if err := w.check(c); err != nil {
+			return true, err
+		}
+	}
+	return len(a.workloads) > 0, nil
+}
+
+// describePods returns a description of the pods of a workload which are not running and ready
+func describePods(c kubernetes.Interface, w workload) string {
+	sel, err := w.podSelector(c)
+	if err != nil {
+		return fmt.Sprintf("\n  unable to get pods: %v", err)
+	}
+	pods, err := c.CoreV1().Pods(w.namespace).List(meta.ListOptions{LabelSelector: sel})
+	if err != nil {
+		return fmt.Sprintf("\n  unable to list pods: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return "\n  no pods found"
+	}
+
+	var b strings.Builder
+	for _, p := range pods.Items {
+		if p.Status.Phase == core.PodRunning && podReady(p) {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  pod %s: %s", p.Name, p.Status.Phase)
+		if p.Status.Reason != "" {
+			fmt.Fprintf(&b, " (%s: %s)", p.Status.Reason, p.Status.Message)
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			switch {
+			case cs.State.Waiting != nil:
+				fmt.Fprintf(&b, "\n    container %s waiting: %s %s", cs.Name, cs.State.Waiting.Reason, cs.State.Waiting.Message)
+			case cs.State.Terminated != nil:
+				fmt.Fprintf(&b, "\n    container %s terminated: %s (exit code %d)", cs.Name, cs.State.Terminated.Reason, cs.State.Terminated.ExitCode)
+			case !cs.Ready:
+				fmt.Fprintf(&b, "\n    container %s not ready (%d restarts)", cs.Name, cs.RestartCount)
+			}
+		}
+	}
+	return b.String()
+}
+
+func podReady(p core.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == core.PodReady {
+			return c.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDescribePods(t *testing.T) {
+	pod := func(name string, phase core.PodPhase, ready core.ConditionStatus, cs ...core.ContainerStatus) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "kube-system", Labels: map[string]string{"app": "test"}},
+			Status: core.PodStatus{
+				Phase:             phase,
+				Conditions:        []core.PodCondition{{Type: core.PodReady, Status: ready}},
+				ContainerStatuses: cs,
+			},
+		}
+	}
+	c := fake.NewSimpleClientset(
+		pod("healthy", core.PodRunning, core.ConditionTrue),
+		pod("pulling", core.PodPending, core.ConditionFalse, core.ContainerStatus{
+			Name:  "app",
+			State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}},
+		}),
+	)
+
+	got := describePods(c, workload{namespace: "kube-system", selector: "app=test"})
+	if strings.Contains(got, "healthy") {
+		t.Errorf("healthy pod should not be described: %s", got)
+	}
+	for _, want := range []string{"pod pulling: Pending", "container app waiting: ImagePullBackOff not found"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in description: %s", want, got)
+		}
+	}
+
+	got = describePods(c, workload{namespace: "kube-system", selector: "app=missing"})
+	if !strings.Contains(got, "no pods found") {
+		t.Errorf("expected no pods found, got: %s", got)
+	}
+}
+
+func TestWorkloadCheck(t *testing.T) {
+	running := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: "running", Namespace: "kube-system", Labels: map[string]string{"app": "running"}},
+		Status:     core.PodStatus{Phase: core.PodRunning},
+	}
+	pending := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: "pending", Namespace: "kube-system", Labels: map[string]string{"app": "pending"}},
+		Status:     core.PodStatus{Phase: core.PodPending},
+	}
+	c := fake.NewSimpleClientset(running, pending)
+
+	if err := (workload{namespace: "kube-system", selector: "app=running"}).check(c); err != nil {
+		t.Errorf("unexpected error for running pod: %v", err)
+	}
+	if err := (workload{namespace: "kube-system", selector: "app=pending"}).check(c); err == nil {
+		t.Errorf("expected error for pending pod")
+	}
+	if err := (workload{namespace: "kube-system", selector: "app=missing"}).check(c); err == nil {
+		t.Errorf("expected error for missing pods")
+	}
+	if err := (workload{namespace: "kube-system", deployment: "missing"}).check(c); err == nil {
+		t.Errorf("expected error for missing deployment")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"sigs.k8s.io/yaml"
+)
+
+// AddonManifestFile is the name of the file describing a user-defined addon
+const AddonManifestFile = "addon.yaml"
+
+// validAddonName matches the names that user-defined addons may have
+var validAddonName = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*$`)
+
+// AddonManifest describes a user-defined addon, as loaded from an addon.yaml file
+type AddonManifest struct {
+	// Name is the name of the addon
+	Name string `json:"name"`
+	// Enabled is the default state of the addon
+	Enabled bool `json:"enabled,omitempty"`
+	// ContainerRuntime is the container runtime the addon requires, if any
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+	// Requires are the addons which are enabled before this one
+	Requires []string `json:"requires,omitempty"`
+	// Conflicts are the addons which may not be enabled at the same time as this one
+	Conflicts []string `json:"conflicts,omitempty"`
+	// Images are pulled into the container runtime when the addon is enabled
+	Images []string `json:"images,omitempty"`
+	// Values are the settings which may be passed to the templates with `minikube addons enable --set`
+	Values map[string]AddonValue `json:"values,omitempty"`
+	// Manifests are the files to copy into the cluster when the addon is enabled
+	Manifests []AddonFile `json:"manifests"`
+}
+
+// AddonFile is a file of a user-defined addon
+type AddonFile struct {
+	// Source is the path of the file, relative to addon.yaml
+	Source string `json:"source"`
+	// TargetDir is the directory to copy the file to, which must be within the addons directory it defaults to
+	TargetDir string `json:"targetDir,omitempty"`
+	// TargetName is the name of the copied file, defaults to the source name without a .tmpl suffix
+	TargetName string `json:"targetName,omitempty"`
+	// Permissions are the permissions of the copied file, defaults to 0640
+	Permissions string `json:"permissions,omitempty"`
+	// Template is whether the file is a Go template, defaults to true for .tmpl files
+	Template *bool `json:"template,omitempty"`
+}
+
+// LoadAddonManifest loads the addon.yaml within a directory
+func LoadAddonManifest(dir string) (*AddonManifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, AddonManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &AddonManifest{}
+	if err := yaml.UnmarshalStrict(data, m); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", AddonManifestFile)
+	}
+	if !validAddonName.MatchString(m.Name) {
+		return nil, fmt.Errorf("invalid addon name %q: only lowercase alphanumerics, dots and dashes are permitted", m.Name)
+	}
+	if len(m.Manifests) == 0 {
+		return nil, fmt.Errorf("addon %q has no manifests", m.Name)
+	}
+	for _, f := range m.Manifests {
+		if f.Source == "" || filepath.IsAbs(f.Source) || strings.HasPrefix(filepath.Clean(f.Source), "..") {
+			return nil, fmt.Errorf("addon %q: manifest source %q must be a path within the addon directory", m.Name, f.Source)
+		}
+		if f.TargetDir != "" && !inAddonsDir(f.TargetDir) {
+			return nil, fmt.Errorf("addon %q: manifest target directory %q must be within %s", m.Name, f.TargetDir, vmpath.GuestAddonsDir)
+		}
+		if strings.Contains(f.TargetName, "/") || f.TargetName == ".." {
+			return nil, fmt.Errorf("addon %q: manifest target name %q must be a file name", m.Name, f.TargetName)
+		}
+	}
+	for k, v := range m.Values {
+		if err := v.validate(); err != nil {
+			return nil, fmt.Errorf("addon %q: value %q: %v", m.Name, k, err)
+		}
+	}
+	return m, nil
+}
+
+// inAddonsDir returns whether an absolute path on the node is the addons directory, or within it
+func inAddonsDir(p string) bool {
+	p = path.Clean(p)
+	return p == vmpath.GuestAddonsDir || strings.HasPrefix(p, vmpath.GuestAddonsDir+"/")
+}
+
+// LoadAddonManifests loads the addons within each subdirectory of a directory, skipping any that are invalid.
+// The error lists the addons which could not be loaded.
+func LoadAddonManifests(root string) (map[string]*AddonManifest, error) {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ms := map[string]*AddonManifest{}
+	failed := []string{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if _, err := os.Stat(filepath.Join(dir, AddonManifestFile)); err != nil {
+			continue
+		}
+		m, err := LoadAddonManifest(dir)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", dir, err))
+			continue
+		}
+		ms[dir] = m
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return ms, fmt.Errorf("invalid addons: %s", strings.Join(failed, "; "))
+	}
+	return ms, nil
+}
+
+// Addon returns the addon described by the manifest, reading its files from dir
+func (m *AddonManifest) Addon(dir string) (*Addon, error) {
+	as := []*BinAsset{}
+	for _, f := range m.Manifests {
+		src := filepath.Join(dir, f.Source)
+		data, err := ioutil.ReadFile(src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", src)
+		}
+
+		targetDir := f.TargetDir
+		if targetDir == "" {
+			targetDir = vmpath.GuestAddonsDir
+		}
+		targetName := f.TargetName
+		if targetName == "" {
+			targetName = strings.TrimSuffix(path.Base(filepath.ToSlash(f.Source)), ".tmpl")
+		}
+		perms := f.Permissions
+		if perms == "" {
+			perms = "0640"
+		}
+		isTemplate := strings.HasSuffix(f.Source, ".tmpl")
+		if f.Template != nil {
+			isTemplate = *f.Template
+		}
+
+		a, err := NewBinAssetFromData(data, src, targetDir, targetName, perms, isTemplate)
+		if err != nil {
+			return nil, errors.Wrapf(err, "asset %s", src)
+		}
+		as = append(as, a)
+	}
+
+	a := NewAddon(as, m.Enabled, m.Name)
+	a.Images = m.Images
+	a.Values = m.Values
+	return a, nil
+}
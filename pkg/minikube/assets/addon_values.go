@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Types of addon values
+const (
+	ValueString = "string"
+	ValueInt    = "int"
+	ValueBool   = "bool"
+)
+
+// AddonValue describes a value which can be set for an addon, e.g. `minikube addons enable ingress --set replicas=2`
+type AddonValue struct {
+	// Type is one of "string", "int" or "bool", defaults to "string"
+	Type string `json:"type,omitempty"`
+	// Default is the value used when none has been set
+	Default string `json:"default,omitempty"`
+	// Description is a short explanation of the value
+	Description string `json:"description,omitempty"`
+}
+
+// normalize returns s in the canonical form for this type, or an error if it is not a valid value
+func (v AddonValue) normalize(s string) (string, error) {
+	switch v.Type {
+	case "", ValueString:
+		return s, nil
+	case ValueInt:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(i), nil
+	case ValueBool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	default:
+		return "", fmt.Errorf("unknown value type %q", v.Type)
+	}
+}
+
+// validate returns an error if the type is unknown or the default is not of that type
+func (v AddonValue) validate() error {
+	switch v.Type {
+	case "", ValueString, ValueInt, ValueBool:
+	default:
+		return fmt.Errorf("unknown type %q", v.Type)
+	}
+	if v.Default == "" {
+		return nil
+	}
+	if _, err := v.normalize(v.Default); err != nil {
+		return fmt.Errorf("invalid %s default %q", v.Type, v.Default)
+	}
+	return nil
+}
+
+// withValues declares the values which can be set for an addon
+func (a *Addon) withValues(values map[string]AddonValue) *Addon {
+	a.Values = values
+	return a
+}
+
+// ValidateValues checks values against the values declared by the addon, returning them in canonical form
+func (a *Addon) ValidateValues(values map[string]string) (map[string]string, error) {
+	valid := map[string]string{}
+	for k, s := range values {
+		v, ok := a.Values[k]
+		if !ok {
+			return nil, fmt.Errorf("addon %q has no value %q, valid values: %s", a.Name(), k, strings.Join(a.valueNames(), ", "))
+		}
+		n, err := v.normalize(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value for %q: %q", v.Type, k, s)
+		}
+		valid[k] = n
+	}
+	return valid, nil
+}
+
+// ResolveValues returns the values set for the addon in the given profile, falling back to their defaults
+func (a *Addon) ResolveValues(cc *config.ClusterConfig) map[string]string {
+	values := map[string]string{}
+	for k, v := range a.Values {
+		values[k] = v.Default
+	}
+	for k, s := range cc.AddonValues[a.Name()] {
+		if _, ok := a.Values[k]; ok {
+			values[k] = s
+		}
+	}
+	return values
+}
+
+func (a *Addon) valueNames() []string {
+	names := []string{}
+	for k := range a.Values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseValues parses a comma separated list of key=value pairs
+func ParseValues(s string) (map[string]string, error) {
+	values := map[string]string{}
+	if s == "" {
+		return values, nil
+	}
+	for _, kv := range strings.Split(s, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid value %q, expected key=value", kv)
+		}
+		values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return values, nil
+}
@@ -26,7 +26,11 @@ import (
 
 // Addon is a named list of assets, that can be enabled
 type Addon struct {
-	Assets    []*BinAsset
+	Assets []*BinAsset
+	// Images are pulled into the container runtime before the assets are applied
+	Images []string
+	// Values are the settings which can be passed to the templates of the addon
+	Values    map[string]AddonValue
 	enabled   bool
 	addonName string
 }
@@ -172,7 +176,10 @@ var Addons = map[string]*Addon{
 			"ingress-dp.yaml",
 			"0640",
 			true),
-	}, false, "ingress"),
+	}, false, "ingress").withValues(map[string]AddonValue{
+		"replicas":    {Type: ValueInt, Default: "1", Description: "number of ingress controller replicas"},
+		"hostNetwork": {Type: ValueBool, Default: "false", Description: "run the ingress controller in the host network namespace"},
+	}),
 	"istio-provisioner": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/istio-provisioner/istio-operator.yaml.tmpl",
@@ -382,7 +389,10 @@ var Addons = map[string]*Addon{
 			"metallb-config.yaml",
 			"0640",
 			true),
-	}, false, "metallb"),
+	}, false, "metallb").withValues(map[string]AddonValue{
+		"startIP": {Type: ValueString, Description: "first address of the load balancer pool, overrides 'addons configure'"},
+		"endIP":   {Type: ValueString, Description: "last address of the load balancer pool, overrides 'addons configure'"},
+	}),
 	"ambassador": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/ambassador/ambassador-operator-crds.yaml",
@@ -405,8 +415,8 @@ var Addons = map[string]*Addon{
 	}, false, "ambassador"),
 }
 
-// GenerateTemplateData generates template data for template assets
-func GenerateTemplateData(cfg config.KubernetesConfig) interface{} {
+// GenerateTemplateData generates template data for template assets, values are the settings of the addon
+func GenerateTemplateData(cfg config.KubernetesConfig, values map[string]string) interface{} {
 
 	a := runtime.GOARCH
 	// Some legacy docker images still need the -arch suffix
@@ -421,12 +431,14 @@ func GenerateTemplateData(cfg config.KubernetesConfig) interface{} {
 		ImageRepository     string
 		LoadBalancerStartIP string
 		LoadBalancerEndIP   string
+		Values              map[string]string
 	}{
 		Arch:                a,
 		ExoticArch:          ea,
 		ImageRepository:     cfg.ImageRepository,
 		LoadBalancerStartIP: cfg.LoadBalancerStartIP,
 		LoadBalancerEndIP:   cfg.LoadBalancerEndIP,
+		Values:              values,
 	}
 
 	return opts
@@ -231,12 +231,31 @@ func defaultValue(defValue string, val interface{}) string {
 	return strVal
 }
 
+// NewBinAssetFromData creates a new BinAsset from data which is not bundled into the binary, such as
+// the files of a user-defined addon
+func NewBinAssetFromData(data []byte, name, targetDir, targetName, permissions string, isTemplate bool) (*BinAsset, error) {
+	m := &BinAsset{
+		BaseAsset: BaseAsset{
+			SourcePath:  name,
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+		template: nil,
+	}
+	err := m.setData(data, isTemplate)
+	return m, err
+}
+
 func (m *BinAsset) loadData(isTemplate bool) error {
 	contents, err := Asset(m.SourcePath)
 	if err != nil {
 		return err
 	}
+	return m.setData(contents, isTemplate)
+}
 
+func (m *BinAsset) setData(contents []byte, isTemplate bool) error {
 	if isTemplate {
 		tpl, err := template.New(m.SourcePath).Funcs(template.FuncMap{"default": defaultValue}).Parse(string(contents))
 		if err != nil {
@@ -31,6 +31,10 @@ type LogOptions struct {
 	Lines int
 	// Follow is whether or not to actively follow the logs, as in tail -f.
 	Follow bool
+	// Since excludes the lines older than this, if set.
+	Since time.Duration
+	// Timestamps prefixes every line with its time, in ISO 8601 format.
+	Timestamps bool
 }
 
 // Bootstrapper contains all the methods needed to bootstrap a Kubernetes cluster
@@ -41,7 +45,13 @@ type Bootstrapper interface {
 	WaitForNode(config.ClusterConfig, config.Node, time.Duration) error
 	JoinCluster(config.ClusterConfig, config.Node, string) error
 	UpdateNode(config.ClusterConfig, config.Node, cruntime.Manager) error
-	GenerateToken(config.ClusterConfig) (string, error)
+	// UpgradeCluster upgrades every node of the cluster to the given Kubernetes version.
+	UpgradeCluster(config.ClusterConfig, string) error
+	GenerateToken(config.ClusterConfig, config.Node) (string, error)
+	// LabelNode applies the labels, topology and taints of a node.
+	LabelNode(config.ClusterConfig, config.Node) error
+	// RemoveNode removes a node, and its etcd member if it is a control plane, from the cluster.
+	RemoveNode(config.ClusterConfig, config.Node) error
 	// LogCommands returns a map of log type to a command which will display that log.
 	LogCommands(config.ClusterConfig, LogOptions) map[string]string
 	SetupCerts(config.KubernetesConfig, config.Node) error
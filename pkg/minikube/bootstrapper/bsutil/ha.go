@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// KubeVipManifestPath is where the kube-vip static pod of a control plane is written
+var KubeVipManifestPath = path.Join(vmpath.GuestManifestsDir, "kube-vip.yaml")
+
+// vipCandidates is the number of addresses at the end of the /24 of the control plane tried for the virtual IP
+const vipCandidates = 10
+
+// HAVirtualIP returns a free virtual IP for a highly-available cluster, given the IP of its primary control plane.
+// It is one of the last addresses of the /24 of the control plane, which DHCP servers and container networks hand
+// out last, skipping those which answer a ping from the control plane.
+func HAVirtualIP(r command.Runner, ip string) (string, error) {
+	v4 := net.ParseIP(ip).To4()
+	if v4 == nil {
+		return "", fmt.Errorf("%q is not an IPv4 address", ip)
+	}
+	vip := make(net.IP, len(v4))
+	copy(vip, v4)
+	for last := 254; last > 254-vipCandidates; last-- {
+		vip[3] = byte(last)
+		if vip.Equal(v4) {
+			continue
+		}
+		if AddressInUse(r, vip.String()) {
+			glog.Infof("%s is in use, trying the next address for the virtual IP", vip)
+			continue
+		}
+		return vip.String(), nil
+	}
+	return "", fmt.Errorf("the last %d addresses of %s/24 are in use", vipCandidates, v4.Mask(net.CIDRMask(24, 32)))
+}
+
+// AddressInUse returns whether an address answers a ping from a node
+func AddressInUse(r command.Runner, ip string) bool {
+	_, err := r.RunCmd(exec.Command("ping", "-c", "1", "-W", "1", ip))
+	return err == nil
+}
+
+// NewKubeVipConfig generates the kube-vip static pod of a control plane, announcing the virtual IP on the given interface
+func NewKubeVipConfig(cc config.ClusterConfig, iface string) ([]byte, error) {
+	opts := struct {
+		Port       int
+		Interface  string
+		VIP        string
+		Image      string
+		KubeConfig string
+	}{
+		Port:       cc.KubernetesConfig.NodePort,
+		Interface:  iface,
+		VIP:        cc.KubernetesConfig.APIServerHAVIP,
+		Image:      images.KubeVip(cc.KubernetesConfig.ImageRepository),
+		KubeConfig: "/etc/kubernetes/admin.conf",
+	}
+
+	var b bytes.Buffer
+	if err := ktmpl.KubeVipTemplate.Execute(&b, opts); err != nil {
+		return nil, errors.Wrap(err, "template execute")
+	}
+	return b.Bytes(), nil
+}
+
+// NetworkInterface returns the name of the network interface of a node which holds the given IP
+func NetworkInterface(r command.Runner, ip string) (string, error) {
+	rr, err := r.RunCmd(exec.Command("ip", "-o", "-4", "addr", "show"))
+	if err != nil {
+		return "", errors.Wrap(err, "ip addr")
+	}
+	return parseNetworkInterface(rr.Stdout.String(), ip)
+}
+
+// parseNetworkInterface parses the output of "ip -o -4 addr show", such as:
+// 2: eth0    inet 192.168.39.2/24 brd 192.168.39.255 scope global dynamic eth0\       valid_lft 3599sec preferred_lft 3599sec
+func parseNetworkInterface(out string, ip string) (string, error) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "inet" {
+			continue
+		}
+		if strings.Split(fields[3], "/")[0] == ip {
+			return strings.Split(fields[1], "@")[0], nil
+		}
+	}
+	return "", fmt.Errorf("no interface has the address %s", ip)
+}
+
+// EtcdMemberID returns the ID of the etcd member of a control plane, given the output of "etcdctl member list", such as:
+// 8e9e05c52164694d, started, minikube, https://192.168.39.2:2380, https://192.168.39.2:2379, false
+func EtcdMemberID(list string, name string) (string, bool) {
+	for _, line := range strings.Split(list, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		if strings.TrimSpace(fields[2]) == name {
+			return strings.TrimSpace(fields[0]), true
+		}
+	}
+	return "", false
+}
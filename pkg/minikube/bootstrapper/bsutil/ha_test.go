@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestHAVirtualIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		inUse   []string
+		want    string
+		wantErr bool
+	}{
+		{ip: "192.168.39.2", want: "192.168.39.254"},
+		{ip: "172.17.0.3", inUse: []string{"172.17.0.254", "172.17.0.253"}, want: "172.17.0.252"},
+		{ip: "10.0.0.254", want: "10.0.0.253"},
+		{ip: "10.0.0.2", inUse: []string{"10.0.0.254", "10.0.0.253", "10.0.0.252", "10.0.0.251", "10.0.0.250", "10.0.0.249", "10.0.0.248", "10.0.0.247", "10.0.0.246", "10.0.0.245"}, wantErr: true},
+		{ip: "fe80::1", wantErr: true},
+		{ip: "", wantErr: true},
+	}
+	for _, tc := range tests {
+		// pings of unregistered addresses fail, as if nothing answered them
+		r := command.NewFakeCommandRunner()
+		pings := map[string]string{}
+		for _, ip := range tc.inUse {
+			pings[fmt.Sprintf("ping -c 1 -W 1 %s", ip)] = ""
+		}
+		r.SetCommandToOutput(pings)
+
+		got, err := HAVirtualIP(r, tc.ip)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("HAVirtualIP(%q) = %s, expected an error", tc.ip, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("HAVirtualIP(%q) = %s, %v, expected %s", tc.ip, got, err, tc.want)
+		}
+	}
+}
+
+func TestParseNetworkInterface(t *testing.T) {
+	out := `1: lo    inet 127.0.0.1/8 scope host lo\       valid_lft forever preferred_lft forever
+2: eth0    inet 10.0.2.15/24 brd 10.0.2.255 scope global dynamic eth0\       valid_lft 86372sec preferred_lft 86372sec
+3: eth1    inet 192.168.99.101/24 brd 192.168.99.255 scope global dynamic eth1\       valid_lft 572sec preferred_lft 572sec
+`
+	got, err := parseNetworkInterface(out, "192.168.99.101")
+	if err != nil || got != "eth1" {
+		t.Errorf("parseNetworkInterface = %s, %v, expected eth1", got, err)
+	}
+	if got, err := parseNetworkInterface(out, "192.168.99.1"); err == nil {
+		t.Errorf("parseNetworkInterface = %s, expected an error for a missing address", got)
+	}
+}
+
+func TestNewKubeVipConfig(t *testing.T) {
+	cc := config.ClusterConfig{
+		KubernetesConfig: config.KubernetesConfig{
+			NodePort:       8443,
+			APIServerHAVIP: "192.168.39.254",
+		},
+	}
+	b, err := NewKubeVipConfig(cc, "eth0")
+	if err != nil {
+		t.Fatalf("NewKubeVipConfig: %v", err)
+	}
+	for _, want := range []string{"value: 192.168.39.254", "value: eth0", `value: "8443"`, "image: ghcr.io/kube-vip/kube-vip:"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("kube-vip manifest does not contain %q:\n%s", want, b)
+		}
+	}
+}
+
+func TestEtcdMemberID(t *testing.T) {
+	list := `8e9e05c52164694d, started, ha, https://192.168.39.2:2380, https://192.168.39.2:2379, false
+91bc3c398fb3c146, started, ha-m02, https://192.168.39.3:2380, https://192.168.39.3:2379, false
+`
+	if id, ok := EtcdMemberID(list, "ha-m02"); !ok || id != "91bc3c398fb3c146" {
+		t.Errorf("EtcdMemberID(ha-m02) = %s, %t, expected 91bc3c398fb3c146", id, ok)
+	}
+	if id, ok := EtcdMemberID(list, "ha-m03"); ok {
+		t.Errorf("EtcdMemberID(ha-m03) = %s, expected no member", id)
+	}
+}
@@ -21,10 +21,12 @@ import (
 	"bytes"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
@@ -53,6 +55,10 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 	}
 	if k8s.NetworkPlugin != "" {
 		extraOpts["network-plugin"] = k8s.NetworkPlugin
+	} else if cnm, err := cni.New(mc); err != nil {
+		return nil, errors.Wrap(err, "cni")
+	} else if !cni.IsDisabled(cnm) {
+		extraOpts["network-plugin"] = "cni"
 	}
 	if _, ok := extraOpts["node-ip"]; !ok {
 		extraOpts["node-ip"] = nc.IP
@@ -62,6 +68,16 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 		extraOpts["hostname-override"] = nodeName
 	}
 
+	// The kubelet registers the node with its labels and taints, so that no pod is scheduled before they are set
+	if _, ok := extraOpts["node-labels"]; !ok {
+		if labels := KubeletNodeLabels(nc); len(labels) > 0 {
+			extraOpts["node-labels"] = strings.Join(labels, ",")
+		}
+	}
+	if _, ok := extraOpts["register-with-taints"]; !ok && len(nc.Taints) > 0 {
+		extraOpts["register-with-taints"] = strings.Join(nc.Taints, ",")
+	}
+
 	pauseImage := images.Pause(version, k8s.ImageRepository)
 	if _, ok := extraOpts["pod-infra-container-image"]; !ok && k8s.ImageRepository != "" && pauseImage != "" && k8s.ContainerRuntime != remoteContainerRuntime {
 		extraOpts["pod-infra-container-image"] = pauseImage
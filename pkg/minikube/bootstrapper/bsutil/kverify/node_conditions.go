@@ -110,32 +110,39 @@ func NodePressure(cs *kubernetes.Clientset) error {
 	for _, n := range ns.Items {
 		glog.Infof("node storage ephemeral capacity is %s", n.Status.Capacity.StorageEphemeral())
 		glog.Infof("node cpu capacity is %s", n.Status.Capacity.Cpu().AsDec())
-		for _, c := range n.Status.Conditions {
-			pc := NodeCondition{Type: c.Type, Status: c.Status, Reason: c.Reason, Message: c.Message}
-			if pc.DiskPressure() {
-				return &ErrDiskPressure{
-					NodeCondition: pc,
-				}
-			}
+		if err := CheckNodePressure(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			if pc.MemoryPressure() {
-				return &ErrMemoryPressure{
-					NodeCondition: pc,
-				}
+// CheckNodePressure returns an error if the node is under disk, memory, pid or network pressure.
+func CheckNodePressure(n v1.Node) error {
+	for _, c := range n.Status.Conditions {
+		pc := NodeCondition{Type: c.Type, Status: c.Status, Reason: c.Reason, Message: c.Message}
+		if pc.DiskPressure() {
+			return &ErrDiskPressure{
+				NodeCondition: pc,
 			}
+		}
 
-			if pc.PIDPressure() {
-				return &ErrPIDPressure{
-					NodeCondition: pc,
-				}
+		if pc.MemoryPressure() {
+			return &ErrMemoryPressure{
+				NodeCondition: pc,
 			}
+		}
 
-			if pc.NetworkUnavailable() {
-				return &ErrNetworkNotReady{
-					NodeCondition: pc,
-				}
+		if pc.PIDPressure() {
+			return &ErrPIDPressure{
+				NodeCondition: pc,
 			}
+		}
 
+		if pc.NetworkUnavailable() {
+			return &ErrNetworkNotReady{
+				NodeCondition: pc,
+			}
 		}
 	}
 	return nil
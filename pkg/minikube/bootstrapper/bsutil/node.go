@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+const (
+	// ZoneLabel is the well-known label of the zone of a node
+	ZoneLabel = "topology.kubernetes.io/zone"
+	// RegionLabel is the well-known label of the region of a node
+	RegionLabel = "topology.kubernetes.io/region"
+)
+
+// taintEffects are the effects a taint may have
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// NodeLabels returns the labels of a node, including its topology, as sorted key=value pairs
+func NodeLabels(n config.Node) []string {
+	labels := map[string]string{}
+	for k, v := range n.Labels {
+		labels[k] = v
+	}
+	if n.Zone != "" {
+		labels[ZoneLabel] = n.Zone
+	}
+	if n.Region != "" {
+		labels[RegionLabel] = n.Region
+	}
+
+	kvs := []string{}
+	for k, v := range labels {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+	return kvs
+}
+
+// KubeletNodeLabels returns the labels of a node that a kubelet may set on its own node. The kubelet refuses
+// to register with labels in the kubernetes.io and k8s.io namespaces other than the topology ones, which are
+// set by LabelNode once the node has joined.
+func KubeletNodeLabels(n config.Node) []string {
+	kvs := []string{}
+	for _, l := range NodeLabels(n) {
+		key := strings.SplitN(l, "=", 2)[0]
+		if key == ZoneLabel || key == RegionLabel {
+			kvs = append(kvs, l)
+			continue
+		}
+		domain := ""
+		if i := strings.Index(key, "/"); i >= 0 {
+			domain = key[:i]
+		}
+		if restricted(domain, "kubernetes.io") || restricted(domain, "k8s.io") {
+			continue
+		}
+		kvs = append(kvs, l)
+	}
+	return kvs
+}
+
+// restricted returns whether a label domain belongs to a namespace reserved by Kubernetes
+func restricted(domain string, namespace string) bool {
+	return domain == namespace || strings.HasSuffix(domain, "."+namespace)
+}
+
+// ValidateTaint checks that a taint is formatted as key[=value]:effect, with an effect Kubernetes knows
+func ValidateTaint(taint string) error {
+	i := strings.LastIndex(taint, ":")
+	if i < 0 {
+		return fmt.Errorf("taint %q has no effect, expected key[=value]:effect", taint)
+	}
+	kv, effect := taint[:i], taint[i+1:]
+	if kv == "" || strings.HasPrefix(kv, "=") {
+		return fmt.Errorf("taint %q has no key, expected key[=value]:effect", taint)
+	}
+	known := false
+	for _, e := range taintEffects {
+		if effect == e {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("taint %q has an unknown effect %q, expected one of %s", taint, effect, strings.Join(taintEffects, ", "))
+	}
+	key, value := kv, ""
+	if i := strings.Index(kv, "="); i >= 0 {
+		key, value = kv[:i], kv[i+1:]
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("taint %q has an invalid key: %s", taint, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return fmt.Errorf("taint %q has an invalid value: %s", taint, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateNode checks that the labels, topology and taints of a node would be accepted by Kubernetes
+func ValidateNode(n config.Node) error {
+	for _, l := range NodeLabels(n) {
+		kv := strings.SplitN(l, "=", 2)
+		if errs := validation.IsQualifiedName(kv[0]); len(errs) > 0 {
+			return fmt.Errorf("label %q has an invalid key: %s", l, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(kv[1]); len(errs) > 0 {
+			return fmt.Errorf("label %q has an invalid value: %s", l, strings.Join(errs, "; "))
+		}
+	}
+	for _, t := range n.Taints {
+		if err := ValidateTaint(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNodeLabels(t *testing.T) {
+	n := config.Node{
+		Name:   "m02",
+		Labels: map[string]string{"pool": "infra", ZoneLabel: "overridden"},
+		Zone:   "zone-a",
+		Region: "region-1",
+	}
+	want := []string{"pool=infra", RegionLabel + "=region-1", ZoneLabel + "=zone-a"}
+	if diff := cmp.Diff(want, NodeLabels(n)); diff != "" {
+		t.Errorf("NodeLabels mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := NodeLabels(config.Node{Name: "m03"}); len(got) != 0 {
+		t.Errorf("NodeLabels of a node without labels = %v, expected none", got)
+	}
+}
+
+func TestKubeletNodeLabels(t *testing.T) {
+	n := config.Node{
+		Name:   "m02",
+		Labels: map[string]string{"pool": "infra", "node-role.kubernetes.io/infra": "", "example.k8s.io/x": "y"},
+		Zone:   "zone-a",
+	}
+	want := []string{"pool=infra", ZoneLabel + "=zone-a"}
+	if diff := cmp.Diff(want, KubeletNodeLabels(n)); diff != "" {
+		t.Errorf("KubeletNodeLabels mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateTaint(t *testing.T) {
+	tests := []struct {
+		taint   string
+		wantErr bool
+	}{
+		{taint: "dedicated=infra:NoSchedule"},
+		{taint: "gpu:NoExecute"},
+		{taint: "example.com/key=a:PreferNoSchedule"},
+		{taint: "example.com/key=a:b:PreferNoSchedule", wantErr: true},
+		{taint: "bad key=infra:NoSchedule", wantErr: true},
+		{taint: "dedicated=infra", wantErr: true},
+		{taint: "dedicated=infra:Never", wantErr: true},
+		{taint: ":NoSchedule", wantErr: true},
+		{taint: "=infra:NoSchedule", wantErr: true},
+	}
+	for _, tc := range tests {
+		err := ValidateTaint(tc.taint)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ValidateTaint(%q) = %v, expected error: %t", tc.taint, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    config.Node
+		wantErr bool
+	}{
+		{name: "valid", node: config.Node{Labels: map[string]string{"example.com/pool": "infra"}, Zone: "zone-a", Taints: []string{"dedicated=infra:NoSchedule"}}},
+		{name: "invalid label key", node: config.Node{Labels: map[string]string{"pool/": "infra"}}, wantErr: true},
+		{name: "invalid label value", node: config.Node{Labels: map[string]string{"pool": "infra pool"}}, wantErr: true},
+		{name: "invalid zone", node: config.Node{Zone: "zone/a"}, wantErr: true},
+		{name: "invalid taint", node: config.Node{Taints: []string{"dedicated"}}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNode(tc.node)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateNode() = %v, expected error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package bsutil
 
 import (
+	"fmt"
 	"path"
 	"strings"
 
 	"github.com/blang/semver"
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 	"k8s.io/minikube/pkg/util"
@@ -109,3 +111,26 @@ var versionSpecificOpts = []config.VersionedExtraOption{
 		LessThanOrEqual: semver.MustParse("1.11.1000"),
 	},
 }
+
+// CheckUpgrade returns an error if kubeadm cannot upgrade a cluster between the two versions.
+// kubeadm only supports upgrading to a newer patch release, or to the next minor release.
+func CheckUpgrade(from string, to string) error {
+	fv, err := util.ParseKubernetesVersion(from)
+	if err != nil {
+		return errors.Wrap(err, "parsing current version")
+	}
+	tv, err := util.ParseKubernetesVersion(to)
+	if err != nil {
+		return errors.Wrap(err, "parsing new version")
+	}
+	if tv.LTE(fv) {
+		return fmt.Errorf("%s is not newer than the current version %s", to, from)
+	}
+	if tv.Major != fv.Major {
+		return fmt.Errorf("unable to upgrade across major versions, from %s to %s: create a new cluster with %s instead", from, to, to)
+	}
+	if tv.Minor > fv.Minor+1 {
+		return fmt.Errorf("unable to skip minor versions: upgrade %s to v%d.%d first", from, fv.Major, fv.Minor+1)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package bsutil
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/blang/semver"
@@ -91,3 +92,37 @@ func TestVersionIsBetween(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckUpgrade(t *testing.T) {
+	tests := []struct {
+		description string
+		from        string
+		to          string
+		valid       bool
+		wantErr     string
+	}{
+		{description: "patch", from: "v1.17.0", to: "v1.17.3", valid: true},
+		{description: "next minor", from: "v1.17.3", to: "v1.18.3", valid: true},
+		{description: "release candidate", from: "v1.18.3", to: "v1.18.4-rc.0", valid: true},
+		{description: "same version", from: "v1.18.3", to: "v1.18.3", valid: false},
+		{description: "downgrade", from: "v1.18.3", to: "v1.17.3", valid: false},
+		{description: "skip minor", from: "v1.16.0", to: "v1.18.3", valid: false, wantErr: "upgrade v1.16.0 to v1.17 first"},
+		{description: "next major", from: "v1.18.3", to: "v2.0.0", valid: false, wantErr: "across major versions"},
+		{description: "unparseable", from: "v1.18.3", to: "vfoo", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := CheckUpgrade(test.from, test.to)
+			if test.valid && err != nil {
+				t.Errorf("CheckUpgrade(%s, %s) returned unexpected error: %v", test.from, test.to, err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("CheckUpgrade(%s, %s) expected an error", test.from, test.to)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("CheckUpgrade(%s, %s) = %v, expected an error containing %q", test.from, test.to, err, test.wantErr)
+			}
+		})
+	}
+}
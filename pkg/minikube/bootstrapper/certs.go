@@ -197,6 +197,9 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 	apiServerIPs := append(
 		k8s.APIServerIPs,
 		[]net.IP{net.ParseIP(n.IP), serviceIP, net.ParseIP(oci.DefaultBindIPV4), net.ParseIP("10.0.0.1")}...)
+	if k8s.APIServerHAVIP != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(k8s.APIServerHAVIP))
+	}
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
 	apiServerAlternateNames := append(
 		apiServerNames,
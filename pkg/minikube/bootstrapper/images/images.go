@@ -126,7 +126,15 @@ func auxiliary(mirror string) []string {
 
 // storageProvisioner returns the minikube storage provisioner image
 func storageProvisioner(mirror string) string {
-	return path.Join(minikubeRepo(mirror), "storage-provisioner"+archTag(false)+"v1.8.1")
+	return path.Join(minikubeRepo(mirror), "storage-provisioner"+archTag(false)+"v2.0.0")
+}
+
+// KubeVip returns the image announcing the virtual IP of a highly-available cluster
+func KubeVip(repo string) string {
+	if repo == "" {
+		repo = "ghcr.io/kube-vip"
+	}
+	return path.Join(repo, "kube-vip:v0.6.4")
 }
 
 // dashboardFrontend returns the image used for the dashboard frontend
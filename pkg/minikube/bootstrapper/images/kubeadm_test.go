@@ -37,7 +37,7 @@ func TestKubeadmImages(t *testing.T) {
 			"k8s.gcr.io/coredns:1.6.5",
 			"k8s.gcr.io/etcd:3.4.3-0",
 			"k8s.gcr.io/pause:3.1",
-			"gcr.io/k8s-minikube/storage-provisioner:v1.8.1",
+			"gcr.io/k8s-minikube/storage-provisioner:v2.0.0",
 			"kubernetesui/dashboard:v2.0.0",
 			"kubernetesui/metrics-scraper:v1.0.2",
 		}},
@@ -49,7 +49,7 @@ func TestKubeadmImages(t *testing.T) {
 			"mirror.k8s.io/coredns:1.6.2",
 			"mirror.k8s.io/etcd:3.3.15-0",
 			"mirror.k8s.io/pause:3.1",
-			"mirror.k8s.io/storage-provisioner:v1.8.1",
+			"mirror.k8s.io/storage-provisioner:v2.0.0",
 			"mirror.k8s.io/dashboard:v2.0.0",
 			"mirror.k8s.io/metrics-scraper:v1.0.2",
 		}},
@@ -61,7 +61,7 @@ func TestKubeadmImages(t *testing.T) {
 			"k8s.gcr.io/coredns:1.3.1",
 			"k8s.gcr.io/etcd:3.3.10",
 			"k8s.gcr.io/pause:3.1",
-			"gcr.io/k8s-minikube/storage-provisioner:v1.8.1",
+			"gcr.io/k8s-minikube/storage-provisioner:v2.0.0",
 			"kubernetesui/dashboard:v2.0.0",
 			"kubernetesui/metrics-scraper:v1.0.2",
 		}},
@@ -73,7 +73,7 @@ func TestKubeadmImages(t *testing.T) {
 			"k8s.gcr.io/coredns:1.3.1",
 			"k8s.gcr.io/etcd:3.3.10",
 			"k8s.gcr.io/pause:3.1",
-			"gcr.io/k8s-minikube/storage-provisioner:v1.8.1",
+			"gcr.io/k8s-minikube/storage-provisioner:v2.0.0",
 			"kubernetesui/dashboard:v2.0.0",
 			"kubernetesui/metrics-scraper:v1.0.2",
 		}},
@@ -85,7 +85,7 @@ func TestKubeadmImages(t *testing.T) {
 			"k8s.gcr.io/coredns:1.2.6",
 			"k8s.gcr.io/etcd:3.2.24",
 			"k8s.gcr.io/pause:3.1",
-			"gcr.io/k8s-minikube/storage-provisioner:v1.8.1",
+			"gcr.io/k8s-minikube/storage-provisioner:v2.0.0",
 			"kubernetesui/dashboard:v2.0.0",
 			"kubernetesui/metrics-scraper:v1.0.2",
 		}},
@@ -97,7 +97,7 @@ func TestKubeadmImages(t *testing.T) {
 			"k8s.gcr.io/coredns:1.2.2",
 			"k8s.gcr.io/etcd:3.2.24",
 			"k8s.gcr.io/pause:3.1",
-			"gcr.io/k8s-minikube/storage-provisioner:v1.8.1",
+			"gcr.io/k8s-minikube/storage-provisioner:v2.0.0",
 			"kubernetesui/dashboard:v2.0.0",
 			"kubernetesui/metrics-scraper:v1.0.2",
 		}},
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+// certificateKeyRe matches the key printed by "kubeadm init phase upload-certs"
+var certificateKeyRe = regexp.MustCompile(`(?m)^([0-9a-f]{64})$`)
+
+// uploadCerts uploads the certificates shared by the control planes into the cluster, so that new control planes
+// can download them while joining, and returns the key they are encrypted with.
+func (k *Bootstrapper) uploadCerts(cfg config.ClusterConfig) (string, error) {
+	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing Kubernetes version")
+	}
+	if version.LT(semver.MustParse("1.15.0")) {
+		return "", fmt.Errorf("joining control planes requires Kubernetes v1.15.0 or newer, got %s", cfg.KubernetesConfig.KubernetesVersion)
+	}
+
+	c := fmt.Sprintf("%s init phase upload-certs --upload-certs --config %s", bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion), bsutil.KubeadmYamlPath)
+	rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", c))
+	if err != nil {
+		return "", errors.Wrap(err, "upload certs")
+	}
+
+	m := certificateKeyRe.FindStringSubmatch(rr.Stdout.String())
+	if m == nil {
+		return "", fmt.Errorf("no certificate key in output: %s", rr.Stdout.String())
+	}
+	return m[1], nil
+}
+
+// joinControlPlane finishes setting up a control plane once it has joined the cluster
+func (k *Bootstrapper) joinControlPlane(cfg config.ClusterConfig, n config.Node) error {
+	if config.IsHA(cfg) {
+		if err := k.applyKubeVip(cfg, n); err != nil {
+			return errors.Wrap(err, "kube-vip")
+		}
+	}
+
+	// kubeadm taints joined control planes, while minikube nodes run workloads unless told otherwise
+	if n.Worker {
+		name := bsutil.KubeNodeName(cfg, n)
+		if _, err := k.kubectl(cfg, 30*time.Second, "taint", "nodes", name, "node-role.kubernetes.io/master:NoSchedule-"); err != nil {
+			glog.Warningf("unable to untaint %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// applyKubeVip writes the kube-vip static pod of a control plane, which announces the virtual IP of the cluster
+func (k *Bootstrapper) applyKubeVip(cfg config.ClusterConfig, n config.Node) error {
+	iface, err := bsutil.NetworkInterface(k.c, n.IP)
+	if err != nil {
+		return errors.Wrap(err, "network interface")
+	}
+	manifest, err := bsutil.NewKubeVipConfig(cfg, iface)
+	if err != nil {
+		return errors.Wrap(err, "generating kube-vip config")
+	}
+	return copyFiles(k.c, []assets.CopyableFile{assets.NewMemoryAssetTarget(manifest, bsutil.KubeVipManifestPath, "0600")})
+}
+
+// RemoveNode removes a node from the cluster: its etcd member if it is a control plane, and its node object.
+// It must be called on the bootstrapper of a control plane which remains in the cluster.
+func (k *Bootstrapper) RemoveNode(cfg config.ClusterConfig, n config.Node) error {
+	name := bsutil.KubeNodeName(cfg, n)
+	if n.ControlPlane {
+		if err := k.removeEtcdMember(cfg, name); err != nil {
+			return errors.Wrap(err, "removing etcd member")
+		}
+	}
+
+	if _, err := k.kubectl(cfg, 30*time.Second, "delete", "node", name, "--ignore-not-found"); err != nil {
+		return errors.Wrap(err, "deleting node")
+	}
+	return nil
+}
+
+// removeEtcdMember removes the etcd member of a control plane, which kubeadm reset does not do if the node is gone
+func (k *Bootstrapper) removeEtcdMember(cfg config.ClusterConfig, name string) error {
+	cp, err := config.PrimaryControlPlane(&cfg)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+	pod := "etcd-" + bsutil.KubeNodeName(cfg, cp)
+
+	list, err := k.etcdctl(cfg, pod, "member", "list")
+	if err != nil {
+		return err
+	}
+	id, ok := bsutil.EtcdMemberID(list, name)
+	if !ok {
+		glog.Infof("%s is not an etcd member, nothing to remove", name)
+		return nil
+	}
+
+	glog.Infof("removing etcd member %s (%s)", id, name)
+	_, err = k.etcdctl(cfg, pod, "member", "remove", id)
+	return err
+}
+
+// etcdctl runs etcdctl within an etcd pod of the cluster
+func (k *Bootstrapper) etcdctl(cfg config.ClusterConfig, pod string, args ...string) (string, error) {
+	certs := path.Join(vmpath.GuestKubernetesCertsDir, "etcd")
+	kargs := []string{"-n", "kube-system", "exec", pod, "--", "env", "ETCDCTL_API=3", "etcdctl",
+		"--endpoints=https://127.0.0.1:2379",
+		"--cacert=" + path.Join(certs, "ca.crt"),
+		"--cert=" + path.Join(certs, "healthcheck-client.crt"),
+		"--key=" + path.Join(certs, "healthcheck-client.key"),
+	}
+	return k.kubectl(cfg, time.Minute, append(kargs, args...)...)
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package kubeadm
 
 import (
-	"bytes"
 	"context"
 	"os/exec"
 	"path"
@@ -41,7 +40,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
-	"k8s.io/minikube/pkg/drivers/kic"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/assets"
@@ -49,6 +47,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -66,13 +65,14 @@ import (
 // Bootstrapper is a bootstrapper using kubeadm
 type Bootstrapper struct {
 	c           command.Runner
+	api         libmachine.API
 	k8sClient   *kubernetes.Clientset // Kubernetes client used to verify pods inside cluster
 	contextName string
 }
 
 // NewBootstrapper creates a new kubeadm.Bootstrapper
 func NewBootstrapper(api libmachine.API, cc config.ClusterConfig, r command.Runner) (*Bootstrapper, error) {
-	return &Bootstrapper{c: r, contextName: cc.Name, k8sClient: nil}, nil
+	return &Bootstrapper{c: r, api: api, contextName: cc.Name, k8sClient: nil}, nil
 }
 
 // GetAPIServerStatus returns the api-server status
@@ -91,16 +91,28 @@ func (k *Bootstrapper) LogCommands(cfg config.ClusterConfig, o bootstrapper.LogO
 	if o.Lines > 0 {
 		kubelet.WriteString(fmt.Sprintf(" -n %d", o.Lines))
 	}
+	if o.Since > 0 {
+		kubelet.WriteString(fmt.Sprintf(" --since=-%ds", int(o.Since.Seconds())))
+	}
+	if o.Timestamps {
+		kubelet.WriteString(" -o short-iso-precise")
+	}
 	if o.Follow {
 		kubelet.WriteString(" -f")
 	}
 
+	// dmesg can not exclude old messages, which are left to the caller
 	var dmesg strings.Builder
-	dmesg.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
+	if o.Timestamps {
+		dmesg.WriteString("sudo dmesg -P -x --time-format iso -L=never --level warn,err,crit,alert,emerg")
+	} else {
+		dmesg.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
+	}
 	if o.Follow {
 		dmesg.WriteString(" --follow")
 	}
-	if o.Lines > 0 {
+	// tail would wait for the end of a followed log
+	if o.Lines > 0 && !o.Follow {
 		dmesg.WriteString(fmt.Sprintf(" | tail -n %d", o.Lines))
 	}
 
@@ -231,27 +243,38 @@ func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "run")
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(3)
-
-	go func() {
-		// we need to have cluster role binding before applying overlay to avoid #7428
-		if err := k.elevateKubeSystemPrivileges(cfg); err != nil {
-			glog.Errorf("unable to create cluster role binding, some addons might not work: %v", err)
-		}
-		// the overlay is required for containerd and cri-o runtime: see #7428
-		if config.MultiNode(cfg) || (driver.IsKIC(cfg.Driver) && cfg.KubernetesConfig.ContainerRuntime != "docker") {
-			if err := k.applyKICOverlay(cfg); err != nil {
-				glog.Errorf("failed to apply kic overlay: %v", err)
-			}
+	// kube-vip needs the admin kubeconfig generated by kubeadm, so it can only be started now
+	if config.IsHA(cfg) {
+		cp, err := config.PrimaryControlPlane(&cfg)
+		if err != nil {
+			return errors.Wrap(err, "primary control plane")
 		}
-		wg.Done()
-	}()
+		if err := k.applyKubeVip(cfg, cp); err != nil {
+			return errors.Wrap(err, "kube-vip")
+		}
+	}
+
+	// we need to have cluster role binding before applying the CNI to avoid #7428
+	if err := k.elevateKubeSystemPrivileges(cfg); err != nil {
+		glog.Errorf("unable to create cluster role binding, some addons might not work: %v", err)
+	}
+
+	if err := k.applyCNI(cfg); err != nil {
+		return errors.Wrap(err, "apply cni")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
 
 	go func() {
 		if err := k.applyNodeLabels(cfg); err != nil {
 			glog.Warningf("unable to apply node labels: %v", err)
 		}
+		if cp, err := config.PrimaryControlPlane(&cfg); err != nil {
+			glog.Warningf("unable to get primary control plane: %v", err)
+		} else if err := k.LabelNode(cfg, cp); err != nil {
+			glog.Warningf("unable to label %s: %v", cp.Name, err)
+		}
 		wg.Done()
 	}()
 
@@ -523,6 +546,12 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "getting k8s client")
 	}
 
+	if config.IsHA(cfg) {
+		if err := k.applyKubeVip(cfg, cp); err != nil {
+			glog.Warningf("unable to apply kube-vip: %v", err)
+		}
+	}
+
 	// If the cluster is running, check if we have any work to do.
 	conf := bsutil.KubeadmYamlPath
 	if !k.needsReconfigure(conf, hostname, port, client, cfg.KubernetesConfig.KubernetesVersion) {
@@ -590,6 +619,10 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "addons")
 	}
 
+	if err := k.applyCNI(cfg); err != nil {
+		return errors.Wrap(err, "apply cni")
+	}
+
 	if err := bsutil.AdjustResourceLimits(k.c); err != nil {
 		glog.Warningf("unable to adjust resource limits: %v", err)
 	}
@@ -629,11 +662,15 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		return errors.Wrap(err, "starting kubelet")
 	}
 
+	if n.ControlPlane {
+		return k.joinControlPlane(cc, n)
+	}
 	return nil
 }
 
-// GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token
-func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
+// GenerateToken creates a token and returns the appropriate kubeadm join command for the node to run, or the already existing token.
+// For control planes, the shared certificates are uploaded for the node to download while joining.
+func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig, n config.Node) (string, error) {
 	// Take that generated token and use it to get a kubeadm join command
 	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create --print-join-command --ttl=0", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)))
 	r, err := k.c.RunCmd(tokenCmd)
@@ -648,6 +685,14 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 		joinCmd = fmt.Sprintf("%s --cri-socket %s", joinCmd, cc.KubernetesConfig.CRISocket)
 	}
 
+	if n.ControlPlane {
+		key, err := k.uploadCerts(cc)
+		if err != nil {
+			return "", errors.Wrap(err, "uploading certs")
+		}
+		joinCmd = fmt.Sprintf("%s --control-plane --certificate-key %s --apiserver-advertise-address %s --apiserver-bind-port %d", joinCmd, key, n.IP, n.Port)
+	}
+
 	return joinCmd, nil
 }
 
@@ -769,13 +814,6 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		files = append(files, assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath+".new", "0640"))
 	}
 
-	// Copy the default CNI config (k8s.conf), so that kubelet can successfully
-	// start a Pod in the case a user hasn't manually installed any CNI plugin
-	// and minikube was started with "--extra-config=kubelet.network-plugin=cni".
-	if cfg.KubernetesConfig.EnableDefaultCNI && !config.MultiNode(cfg) {
-		files = append(files, assets.NewMemoryAssetTarget([]byte(defaultCNIConfig), bsutil.DefaultCNIConfigPath, "0644"))
-	}
-
 	// Installs compatibility shims for non-systemd environments
 	kubeletPath := path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubelet")
 	shims, err := sm.GenerateInitShim("kubelet", kubeletPath, bsutil.KubeletSystemdConfFile)
@@ -793,7 +831,13 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		return errors.Wrap(err, "control plane")
 	}
 
-	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+	// In a highly-available cluster, only the primary control plane reaches the API server directly, as it has to
+	// before the virtual IP exists: the other nodes go through the virtual IP, so that they survive its loss.
+	endpoint := cp.IP
+	if config.IsHA(cfg) && !config.IsPrimaryControlPlane(cfg, n) {
+		endpoint = cfg.KubernetesConfig.APIServerHAVIP
+	}
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(endpoint)); err != nil {
 		return errors.Wrap(err, "host alias")
 	}
 
@@ -824,38 +868,24 @@ func kubectlPath(cfg config.ClusterConfig) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubectl")
 }
 
-// applyKICOverlay applies the CNI plugin needed to make kic work
-func (k *Bootstrapper) applyKICOverlay(cfg config.ClusterConfig) error {
-	b := bytes.Buffer{}
-	if err := kicCNIConfig.Execute(&b, struct{ ImageName string }{ImageName: kic.OverlayImage}); err != nil {
-		return err
+// applyCNI applies the CNI of the cluster, if it has one
+func (k *Bootstrapper) applyCNI(cfg config.ClusterConfig) error {
+	cnm, err := cni.New(cfg)
+	if err != nil {
+		return errors.Wrap(err, "cni config")
 	}
-
-	ko := path.Join(vmpath.GuestEphemeralDir, "kic_overlay.yaml")
-	f := assets.NewMemoryAssetTarget(b.Bytes(), ko, "0644")
-
-	if err := k.c.Copy(f); err != nil {
-		return errors.Wrapf(err, "copy")
+	if cni.IsDisabled(cnm) {
+		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "sudo", kubectlPath(cfg), "apply",
-		fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig")),
-		"-f", ko)
-
-	if rr, err := k.c.RunCmd(cmd); err != nil {
-		return errors.Wrapf(err, "cmd: %s output: %s", rr.Command(), rr.Output())
+	if cnm.SupportsNetworkPolicy() {
+		out.T(out.CNI, "Configuring {{.name}} (Container Networking Interface), which enforces NetworkPolicy ...", out.V{"name": cnm.String()})
+	} else {
+		out.T(out.CNI, "Configuring {{.name}} (Container Networking Interface) ...", out.V{"name": cnm.String()})
 	}
-
-	// Inform cri-o that the CNI has changed
-	if cfg.KubernetesConfig.ContainerRuntime == "crio" {
-		if err := sysinit.New(k.c).Restart("crio"); err != nil {
-			return errors.Wrap(err, "restart crio")
-		}
+	if err := cnm.Apply(k.c); err != nil {
+		return errors.Wrap(err, "cni apply")
 	}
-
 	return nil
 }
 
@@ -883,6 +913,24 @@ func (k *Bootstrapper) applyNodeLabels(cfg config.ClusterConfig) error {
 	return nil
 }
 
+// LabelNode applies the labels, topology and taints of a node. It must be called on the bootstrapper of a control plane.
+func (k *Bootstrapper) LabelNode(cfg config.ClusterConfig, n config.Node) error {
+	name := bsutil.KubeNodeName(cfg, n)
+	if labels := bsutil.NodeLabels(n); len(labels) > 0 {
+		args := append([]string{"label", "nodes", name, "--overwrite"}, labels...)
+		if _, err := k.kubectl(cfg, 30*time.Second, args...); err != nil {
+			return errors.Wrap(err, "labels")
+		}
+	}
+	if len(n.Taints) > 0 {
+		args := append([]string{"taint", "nodes", name, "--overwrite"}, n.Taints...)
+		if _, err := k.kubectl(cfg, 30*time.Second, args...); err != nil {
+			return errors.Wrap(err, "taints")
+		}
+	}
+	return nil
+}
+
 // elevateKubeSystemPrivileges gives the kube-system service account cluster admin privileges to work with RBAC.
 func (k *Bootstrapper) elevateKubeSystemPrivileges(cfg config.ClusterConfig) error {
 	start := time.Now()
@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+// drainTimeout is how long to wait for the pods of a node to be evicted
+const drainTimeout = 2 * time.Minute
+
+// drainSelector leaves the pods of minikube addons in place while draining: many of them are bare pods,
+// which drain would delete for good rather than reschedule.
+const drainSelector = "!addonmanager.kubernetes.io/mode"
+
+// UpgradeCluster upgrades the primary control plane, then the other control planes, then each worker node,
+// to a newer Kubernetes version.
+// The configuration passed in is left untouched, so that the caller may persist the new version on success.
+func (k *Bootstrapper) UpgradeCluster(cfg config.ClusterConfig, version string) error {
+	start := time.Now()
+	glog.Infof("UpgradeCluster: %s -> %s", cfg.KubernetesConfig.KubernetesVersion, version)
+	defer func() {
+		glog.Infof("UpgradeCluster complete in %s", time.Since(start))
+	}()
+
+	if err := bsutil.CheckUpgrade(cfg.KubernetesConfig.KubernetesVersion, version); err != nil {
+		return err
+	}
+
+	nc := cfg
+	nc.KubernetesConfig.KubernetesVersion = version
+	nc.Nodes = []config.Node{}
+	for _, n := range cfg.Nodes {
+		n.KubernetesVersion = version
+		nc.Nodes = append(nc.Nodes, n)
+	}
+
+	cp, err := config.PrimaryControlPlane(&nc)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+	if err := k.upgradeControlPlane(nc, cp); err != nil {
+		return errors.Wrap(err, "upgrading control plane")
+	}
+
+	for _, n := range upgradeOrder(nc, cp) {
+		if err := k.upgradeNode(nc, n); err != nil {
+			return errors.Wrapf(err, "upgrading node %s", n.Name)
+		}
+	}
+	return nil
+}
+
+// upgradeOrder returns the nodes to upgrade after the primary control plane: the other control planes first,
+// as a kubelet must not be newer than any apiserver it may talk to, then the workers.
+func upgradeOrder(cfg config.ClusterConfig, primary config.Node) []config.Node {
+	var cps, workers []config.Node
+	for _, n := range cfg.Nodes {
+		switch {
+		case n.Name == primary.Name:
+		case n.ControlPlane:
+			cps = append(cps, n)
+		default:
+			workers = append(workers, n)
+		}
+	}
+	return append(cps, workers...)
+}
+
+// upgradeControlPlane runs kubeadm upgrade plan/apply, then moves the kubelet of the control plane to the new version
+func (k *Bootstrapper) upgradeControlPlane(cfg config.ClusterConfig, n config.Node) error {
+	version := cfg.KubernetesConfig.KubernetesVersion
+	out.T(out.Pulling, "Upgrading control plane {{.name}} to Kubernetes {{.version}} ...", out.V{"name": n.Name, "version": version})
+
+	r, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c, Socket: cfg.KubernetesConfig.CRISocket})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	if err := r.Preload(cfg.KubernetesConfig); err != nil {
+		glog.Infof("preloading failed, kubeadm will pull the images it needs: %v", err)
+	}
+	if err := transferBinaries(cfg, k.c); err != nil {
+		return err
+	}
+
+	kubeadmCfg, err := bsutil.GenerateKubeadmYAML(cfg, n, r)
+	if err != nil {
+		return errors.Wrap(err, "generating kubeadm cfg")
+	}
+	conf := bsutil.KubeadmYamlPath
+	if err := copyFiles(k.c, []assets.CopyableFile{assets.NewMemoryAssetTarget(kubeadmCfg, conf+".new", "0640")}); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+
+	kubeadm := bsutil.InvokeKubeadm(version)
+	plan := fmt.Sprintf("%s upgrade plan %s --config %s --ignore-preflight-errors=all", kubeadm, version, conf+".new")
+	rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", plan))
+	if err != nil {
+		return errors.Wrap(err, "upgrade plan")
+	}
+	glog.Infof("upgrade plan:\n%s", rr.Stdout.String())
+
+	apply := fmt.Sprintf("%s upgrade apply %s --config %s --yes --ignore-preflight-errors=all", kubeadm, version, conf+".new")
+	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", apply)); err != nil {
+		return errors.Wrap(err, "upgrade apply")
+	}
+	if _, err := k.c.RunCmd(exec.Command("sudo", "cp", conf+".new", conf)); err != nil {
+		return errors.Wrap(err, "cp")
+	}
+
+	err = k.drained(cfg, bsutil.KubeNodeName(cfg, n), func() error {
+		return k.upgradeKubelet(cfg, n, r)
+	})
+	if err != nil {
+		return err
+	}
+
+	// the apiserver has been replaced, so any cached client is stale
+	k.k8sClient = nil
+	return k.WaitForNode(cfg, n, kconst.DefaultControlPlaneTimeout)
+}
+
+// upgradeNode runs kubeadm upgrade node on a secondary control plane or a worker, then moves its kubelet to the new version
+func (k *Bootstrapper) upgradeNode(cfg config.ClusterConfig, n config.Node) error {
+	version := cfg.KubernetesConfig.KubernetesVersion
+	if n.ControlPlane {
+		out.T(out.Pulling, "Upgrading control plane {{.name}} to Kubernetes {{.version}} ...", out.V{"name": n.Name, "version": version})
+	} else {
+		out.T(out.Pulling, "Upgrading node {{.name}} to Kubernetes {{.version}} ...", out.V{"name": n.Name, "version": version})
+	}
+
+	h, err := machine.LoadHost(k.api, driver.MachineName(cfg, n))
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	w := &Bootstrapper{c: runner, contextName: k.contextName, api: k.api}
+
+	r, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: runner, Socket: cfg.KubernetesConfig.CRISocket})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	if err := r.Preload(cfg.KubernetesConfig); err != nil {
+		glog.Infof("preloading failed, images will be pulled as needed: %v", err)
+	}
+	if err := transferBinaries(cfg, runner); err != nil {
+		return err
+	}
+
+	cmd, err := upgradeNodeCmd(version)
+	if err != nil {
+		return err
+	}
+
+	// on a control plane, kubeadm upgrade node also moves the static pods to the new version
+	err = k.drained(cfg, bsutil.KubeNodeName(cfg, n), func() error {
+		if rr, err := runner.RunCmd(exec.Command("/bin/bash", "-c", cmd)); err != nil {
+			return errors.Wrapf(err, "upgrade node: %s", rr.Output())
+		}
+		return w.upgradeKubelet(cfg, n, r)
+	})
+	if err != nil {
+		return err
+	}
+	return w.WaitForNode(cfg, n, kconst.DefaultControlPlaneTimeout)
+}
+
+// upgradeNodeCmd returns the kubeadm command which upgrades the local kubelet configuration of a node
+func upgradeNodeCmd(version string) (string, error) {
+	v, err := util.ParseKubernetesVersion(version)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing Kubernetes version")
+	}
+	kubeadm := bsutil.InvokeKubeadm(version)
+	// "upgrade node config" was folded into "upgrade node" in v1.15
+	if v.LT(semver.MustParse("1.15.0")) {
+		return fmt.Sprintf("%s upgrade node config --kubelet-version %s", kubeadm, version), nil
+	}
+	return fmt.Sprintf("%s upgrade node", kubeadm), nil
+}
+
+// transferBinaries copies the binaries of the new version, leaving the current kubelet running
+func transferBinaries(cfg config.ClusterConfig, runner command.Runner) error {
+	sm := sysinit.New(runner)
+	if err := bsutil.TransferBinaries(cfg.KubernetesConfig, runner, sm); err != nil {
+		return errors.Wrap(err, "downloading binaries")
+	}
+	// TransferBinaries may have stopped the kubelet, which is still configured for the old version
+	if err := sm.Start("kubelet"); err != nil {
+		return errors.Wrap(err, "starting kubelet")
+	}
+	return nil
+}
+
+// upgradeKubelet points the kubelet of a node at the new version, and restarts it
+func (k *Bootstrapper) upgradeKubelet(cfg config.ClusterConfig, n config.Node, r cruntime.Manager) error {
+	if err := k.UpdateNode(cfg, n, r); err != nil {
+		return errors.Wrap(err, "updating node")
+	}
+	if err := sysinit.New(k.c).Restart("kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+	return nil
+}
+
+// drained runs fn while a node is drained, and uncordons the node afterwards, even if fn failed
+func (k *Bootstrapper) drained(cfg config.ClusterConfig, name string, fn func() error) error {
+	glog.Infof("draining node %s", name)
+	_, derr := k.kubectl(cfg, drainTimeout+30*time.Second, "drain", name, "--ignore-daemonsets", "--delete-local-data",
+		"--force", fmt.Sprintf("--pod-selector=%s", drainSelector), fmt.Sprintf("--timeout=%s", drainTimeout))
+
+	var err error
+	if derr != nil {
+		err = errors.Wrap(derr, "drain")
+	} else {
+		err = fn()
+	}
+
+	glog.Infof("uncordoning node %s", name)
+	if _, uerr := k.kubectl(cfg, 30*time.Second, "uncordon", name); uerr != nil {
+		if err == nil {
+			return errors.Wrap(uerr, "uncordon")
+		}
+		glog.Warningf("unable to uncordon %s: %v", name, uerr)
+	}
+	return err
+}
+
+// kubectl runs kubectl against the cluster from a control plane, returning its output
+func (k *Bootstrapper) kubectl(cfg config.ClusterConfig, timeout time.Duration, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args = append([]string{kubectlPath(cfg), fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig"))}, args...)
+	rr, err := k.c.RunCmd(exec.CommandContext(ctx, "sudo", args...))
+	if err != nil {
+		return "", errors.Wrapf(err, "cmd: %s output: %s", rr.Command(), rr.Output())
+	}
+	return rr.Stdout.String(), nil
+}
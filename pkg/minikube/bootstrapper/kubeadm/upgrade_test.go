@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestUpgradeOrder(t *testing.T) {
+	cp := config.Node{Name: "", ControlPlane: true, Worker: true}
+	cp2 := config.Node{Name: "m02", ControlPlane: true, Worker: true}
+	cp3 := config.Node{Name: "m04", ControlPlane: true}
+	w1 := config.Node{Name: "m03", Worker: true}
+	w2 := config.Node{Name: "m05", Worker: true}
+
+	var tests = []struct {
+		description string
+		nodes       []config.Node
+		want        []config.Node
+	}{
+		{
+			description: "single node",
+			nodes:       []config.Node{cp},
+		},
+		{
+			description: "workers",
+			nodes:       []config.Node{cp, w1, w2},
+			want:        []config.Node{w1, w2},
+		},
+		{
+			description: "two control planes",
+			nodes:       []config.Node{cp, cp2, w1},
+			want:        []config.Node{cp2, w1},
+		},
+		{
+			description: "control planes added after workers",
+			nodes:       []config.Node{cp, cp2, w1, cp3, w2},
+			want:        []config.Node{cp2, cp3, w1, w2},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := upgradeOrder(config.ClusterConfig{Nodes: tc.nodes}, cp)
+			if len(got) != len(tc.want) || (len(got) > 0 && !reflect.DeepEqual(got, tc.want)) {
+				t.Errorf("upgradeOrder() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -27,11 +27,21 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+const (
+	// NineP mounts the host over 9p, served by the userspace 9p server on the host
+	NineP = "9p"
+	// SSHFS mounts the host with sshfs over the SSH connection to the node, served by the sftp-server of the host
+	SSHFS = "sshfs"
+	// NFS mounts the host over NFSv3, served by the in-process NFS server on the host
+	NFS = "nfs"
 )
 
 // MountConfig defines the options available to the Mount command
 type MountConfig struct {
-	// Type is the filesystem type (Typically 9p)
+	// Type is the filesystem type: 9p, sshfs or nfs
 	Type string
 	// UID is the User ID which this path will be mounted as
 	UID string
@@ -54,17 +64,32 @@ type mountRunner interface {
 	RunCmd(*exec.Cmd) (*command.RunResult, error)
 }
 
-// Mount runs the mount command from the 9p client on the VM to the 9p server on the host
-func Mount(r mountRunner, source string, target string, c *MountConfig) error {
-	if err := Unmount(r, target); err != nil {
-		return errors.Wrap(err, "umount")
+// DefaultMountType returns the mount type that works best with a driver: NFS where the VM can reach
+// the host over a host-only network, sshfs where it can't, and 9p otherwise.
+func DefaultMountType(name string) string {
+	switch name {
+	case driver.HyperV:
+		return SSHFS
+	case driver.KVM2, driver.VirtualBox, driver.HyperKit, driver.Parallels, driver.VMware, driver.VMwareFusion:
+		return NFS
 	}
+	return NineP
+}
 
-	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -m %o -p %s", c.Mode, target))); err != nil {
-		return errors.Wrap(err, "create folder pre-mount")
+// Mount runs the mount command from the 9p or NFS client on the VM to the server on the host
+func Mount(r mountRunner, source string, target string, c *MountConfig) error {
+	if c.Type == SSHFS {
+		return errors.New("sshfs mounts are served over SSH, use MountSSHFS")
+	}
+	if err := prepareTarget(r, target, c.Mode); err != nil {
+		return err
 	}
 
-	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", mntCmd(source, target, c)))
+	cmd := mntCmd(source, target, c)
+	if c.Type == NFS {
+		cmd = nfsMntCmd(source, target, c)
+	}
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", cmd))
 	if err != nil {
 		return errors.Wrapf(err, "mount with cmd %s ", rr.Command())
 	}
@@ -73,6 +98,39 @@ func Mount(r mountRunner, source string, target string, c *MountConfig) error {
 	return nil
 }
 
+// prepareTarget unmounts whatever is mounted on a target, and creates it
+func prepareTarget(r mountRunner, target string, mode os.FileMode) error {
+	if err := Unmount(r, target); err != nil {
+		return errors.Wrap(err, "umount")
+	}
+
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -m %o -p %s", mode, target))); err != nil {
+		return errors.Wrap(err, "create folder pre-mount")
+	}
+	return nil
+}
+
+// GuestIDs resolves the UID and GID of a mount to numbers within the VM, for servers that report file ownership
+func GuestIDs(r mountRunner, c *MountConfig) (uint32, uint32, error) {
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("echo %s %s", resolveUID(c.UID), resolveGID(c.GID))))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "resolve ids")
+	}
+	fields := strings.Fields(rr.Stdout.String())
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unable to resolve uid %q and gid %q: %q", c.UID, c.GID, rr.Stdout.String())
+	}
+	uid, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "parse uid %q", fields[0])
+	}
+	gid, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "parse gid %q", fields[1])
+	}
+	return uint32(uid), uint32(gid), nil
+}
+
 // returns either a raw UID number, or the subshell to resolve it.
 func resolveUID(id string) string {
 	_, err := strconv.ParseInt(id, 10, 64)
@@ -123,7 +181,32 @@ func mntCmd(source string, target string, c *MountConfig) string {
 		options[k] = v
 	}
 
-	// Convert everything into a sorted list for better test results
+	return fmt.Sprintf("sudo mount -t %s -o %s %s %s", c.Type, joinOptions(options), source, target)
+}
+
+// nfsMntCmd returns the mount command of the NFS client. The server has no portmapper, so both the
+// NFS and MOUNT ports are given, and locking is kept local to the VM.
+func nfsMntCmd(source string, target string, c *MountConfig) string {
+	options := map[string]string{
+		"vers":       "3",
+		"proto":      "tcp",
+		"mountproto": "tcp",
+		"nolock":     "",
+	}
+
+	if c.Port != 0 {
+		options["port"] = strconv.Itoa(c.Port)
+		options["mountport"] = strconv.Itoa(c.Port)
+	}
+
+	for k, v := range c.Options {
+		options[k] = v
+	}
+	return fmt.Sprintf("sudo mount -t nfs -o %s %s:/ %s", joinOptions(options), source, target)
+}
+
+// joinOptions converts mount options into a sorted list for better test results
+func joinOptions(options map[string]string) string {
 	opts := []string{}
 	for k, v := range options {
 		// Mount option with no value, such as "noextend"
@@ -134,7 +217,7 @@ func mntCmd(source string, target string, c *MountConfig) string {
 		opts = append(opts, fmt.Sprintf("%s=%s", k, v))
 	}
 	sort.Strings(opts)
-	return fmt.Sprintf("sudo mount -t %s -o %s %s %s", c.Type, strings.Join(opts, ","), source, target)
+	return strings.Join(opts, ",")
 }
 
 // Unmount unmounts a path
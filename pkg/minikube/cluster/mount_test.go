@@ -76,3 +76,89 @@ func TestMntCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestNFSMntCmd(t *testing.T) {
+	var tests = []struct {
+		name   string
+		source string
+		target string
+		cfg    *MountConfig
+		want   string
+	}{
+		{
+			name:   "simple",
+			source: "10.0.0.1",
+			target: "/target",
+			cfg:    &MountConfig{Type: "nfs", Mode: os.FileMode(0700), Port: 2049},
+			want:   "sudo mount -t nfs -o mountport=2049,mountproto=tcp,nolock,port=2049,proto=tcp,vers=3 10.0.0.1:/ /target",
+		},
+		{
+			name:   "options",
+			source: "10.0.0.1",
+			target: "/target",
+			cfg: &MountConfig{Type: "nfs", Mode: os.FileMode(0700), Port: 2049, Options: map[string]string{
+				"actimeo": "1",
+				"proto":   "udp",
+			}},
+			want: "sudo mount -t nfs -o actimeo=1,mountport=2049,mountproto=tcp,nolock,port=2049,proto=udp,vers=3 10.0.0.1:/ /target",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := nfsMntCmd(tc.source, tc.target, tc.cfg)
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("command diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSSHFSCmd(t *testing.T) {
+	var tests = []struct {
+		name   string
+		source string
+		target string
+		cfg    *MountConfig
+		want   string
+	}{
+		{
+			name:   "simple",
+			source: "/src",
+			target: "/target",
+			cfg:    &MountConfig{Type: "sshfs", Mode: os.FileMode(0700)},
+			want:   "sudo sshfs -f -o allow_other,gid=0,slave,uid=0 ':/src' /target",
+		},
+		{
+			name:   "named uid",
+			source: "/home/user/src",
+			target: "/target",
+			cfg:    &MountConfig{Type: "sshfs", Mode: os.FileMode(0700), UID: "docker", GID: "docker", Options: map[string]string{"cache": "no"}},
+			want:   "sudo sshfs -f -o allow_other,cache=no,gid=$(grep ^docker: /etc/group | cut -d: -f3),slave,uid=$(id -u docker) ':/home/user/src' /target",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sshfsCmd(tc.source, tc.target, tc.cfg)
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("command diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultMountType(t *testing.T) {
+	var tests = map[string]string{
+		"kvm2":       NFS,
+		"virtualbox": NFS,
+		"hyperv":     SSHFS,
+		"docker":     NineP,
+		"none":       NineP,
+	}
+	for name, want := range tests {
+		if got := DefaultMountType(name); got != want {
+			t.Errorf("DefaultMountType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -103,3 +103,13 @@ func unpause(cr cruntime.Manager, r command.Runner, namespaces []string) ([]stri
 
 	return ids, nil
 }
+
+// CheckIfPaused returns whether any containers in the given namespaces are paused
+func CheckIfPaused(cr cruntime.Manager, namespaces []string) (bool, error) {
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Paused, Namespaces: namespaces})
+	if err != nil {
+		return false, errors.Wrap(err, "list paused")
+	}
+	glog.Infof("%d paused containers: %s", len(ids), ids)
+	return len(ids) > 0, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshfsTimeout is how long sshfs is given to mount the host
+const sshfsTimeout = 30 * time.Second
+
+// sftpServers are where OpenSSH installs sftp-server on the supported hosts
+var sftpServers = []string{
+	"/usr/lib/openssh/sftp-server",
+	"/usr/libexec/openssh/sftp-server",
+	"/usr/libexec/sftp-server",
+	"/usr/lib/ssh/sftp-server",
+	`C:\Windows\System32\OpenSSH\sftp-server.exe`,
+}
+
+// SSHFSMount is a mount of the host by sshfs, whose SFTP requests are served by the sftp-server of the host
+type SSHFSMount struct {
+	session *ssh.Session
+	server  *exec.Cmd
+	done    chan error
+}
+
+// MountSSHFS mounts a directory of the host with sshfs. sshfs runs in slave mode, so that the VM needs no
+// access to the host: the host runs sftp-server, and connects it to sshfs over an SSH session to the VM.
+func MountSSHFS(r mountRunner, client *ssh.Client, source string, target string, c *MountConfig) (*SSHFSMount, error) {
+	server, err := sftpServer()
+	if err != nil {
+		return nil, err
+	}
+	source, err = filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareTarget(r, target, c.Mode); err != nil {
+		return nil, err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "new session")
+	}
+	requests, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, errors.Wrap(err, "stdout")
+	}
+	replies, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, errors.Wrap(err, "stdin")
+	}
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+
+	m := &SSHFSMount{session: session, server: exec.Command(server), done: make(chan error, 1)}
+	m.server.Stdin = requests
+	m.server.Stdout = replies
+	if err := m.server.Start(); err != nil {
+		session.Close()
+		return nil, errors.Wrapf(err, "start %s", server)
+	}
+
+	cmd := sshfsCmd(source, target, c)
+	glog.Infof("Running %s", cmd)
+	if err := session.Start(cmd); err != nil {
+		m.Close()
+		return nil, errors.Wrap(err, "start sshfs")
+	}
+	go func() {
+		err := session.Wait()
+		if err != nil && stderr.Len() > 0 {
+			err = errors.Wrap(err, strings.TrimSpace(stderr.String()))
+		}
+		m.done <- err
+	}()
+
+	if err := m.waitMounted(r, target); err != nil {
+		m.Close()
+		return nil, err
+	}
+	glog.Infof("sshfs mounted %s on %s", source, target)
+	return m, nil
+}
+
+// waitMounted waits for sshfs to show up as mounted on the target
+func (m *SSHFSMount) waitMounted(r mountRunner, target string) error {
+	deadline := time.Now().Add(sshfsTimeout)
+	for {
+		select {
+		case err := <-m.done:
+			if err == nil {
+				err = errors.New("exited")
+			}
+			return errors.Wrap(err, "sshfs")
+		default:
+		}
+
+		if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("findmnt -n -t fuse.sshfs -M %s", target))); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for sshfs to mount %s", target)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// Wait waits for sshfs to exit, which happens when the target is unmounted
+func (m *SSHFSMount) Wait() error {
+	return <-m.done
+}
+
+// Close ends the SSH session and stops the sftp-server of the host
+func (m *SSHFSMount) Close() error {
+	m.session.Close()
+	if m.server.Process != nil {
+		if err := m.server.Process.Kill(); err != nil {
+			glog.Warningf("unable to kill sftp-server: %v", err)
+		}
+	}
+	// sftp-server exits with an error when it is killed
+	_ = m.server.Wait()
+	return nil
+}
+
+// sftpServer returns the path of the sftp-server of OpenSSH on the host
+func sftpServer() (string, error) {
+	for _, p := range sftpServers {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	if p, err := exec.LookPath("sftp-server"); err == nil {
+		return p, nil
+	}
+	return "", errors.New("sftp-server of OpenSSH was not found on the host, it is required by sshfs mounts")
+}
+
+// sshfsCmd returns the command running sshfs in slave mode, speaking SFTP over its stdin and stdout
+func sshfsCmd(source string, target string, c *MountConfig) string {
+	options := map[string]string{
+		"slave":       "",
+		"allow_other": "",
+		"uid":         resolveUID(c.UID),
+		"gid":         resolveGID(c.GID),
+	}
+
+	for k, v := range c.Options {
+		options[k] = v
+	}
+	return fmt.Sprintf("sudo sshfs -f -o %s ':%s' %s", joinOptions(options), sftpPath(source), target)
+}
+
+// sftpPath returns the path of a host directory as seen over SFTP, which on Windows is "/C:/..."
+func sftpPath(p string) string {
+	p = filepath.ToSlash(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
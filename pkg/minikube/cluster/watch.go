@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// replayBatch is the number of paths replayed by a single command
+const replayBatch = 100
+
+// replayScript touches the paths given as arguments, relative to the first one, with their own timestamps.
+// This leaves them unchanged, but makes the kernel of the VM deliver inotify events for them.
+const replayScript = `cd "$0" && for f in "$@"; do [ -e "$f" ] && touch -c -r "$f" "$f"; done; true`
+
+// WatchConfig defines how changes to the source of a mount are propagated to the VM
+type WatchConfig struct {
+	// Ignore are patterns of paths whose changes aren't propagated. They are matched against the
+	// path relative to the source, and against each of its elements.
+	Ignore []string
+	// Debounce is how long changes are collected before they are propagated together
+	Debounce time.Duration
+}
+
+// Watcher propagates the changes made on the host to the source of a mount into the VM. Filesystems
+// such as 9p don't deliver inotify events for changes made on the server, so the watcher replays them
+// by touching the changed files within the VM.
+type Watcher struct {
+	r      mountRunner
+	source string
+	target string
+	cfg    WatchConfig
+	fsw    *fsnotify.Watcher
+}
+
+// NewWatcher starts watching the source of a mount
+func NewWatcher(r mountRunner, source string, target string, cfg WatchConfig) (*Watcher, error) {
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "new watcher")
+	}
+	w := &Watcher{r: r, source: source, target: target, cfg: cfg, fsw: fsw}
+	if err := w.watchTree(source); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Run propagates changes until stop is closed, or forever if it is nil
+func (w *Watcher) Run(stop <-chan struct{}) error {
+	defer w.fsw.Close()
+	return w.run(w.fsw.Events, w.fsw.Errors, stop)
+}
+
+func (w *Watcher) run(events <-chan fsnotify.Event, errs <-chan error, stop <-chan struct{}) error {
+	pending := map[string]bool{}
+	var flush <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			w.replay(pending)
+			return nil
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			glog.Warningf("watching %s: %v", w.source, err)
+		case ev, ok := <-events:
+			if !ok {
+				w.replay(pending)
+				return nil
+			}
+			changed := w.changed(ev)
+			if len(changed) == 0 {
+				continue
+			}
+			if ev.Op&fsnotify.Create != 0 {
+				if err := w.watchTree(ev.Name); err != nil {
+					glog.Warningf("unable to watch %s: %v", ev.Name, err)
+				}
+			}
+			for _, p := range changed {
+				pending[p] = true
+			}
+			if flush == nil {
+				flush = time.After(w.cfg.Debounce)
+			}
+		case <-flush:
+			w.replay(pending)
+			pending = map[string]bool{}
+			flush = nil
+		}
+	}
+}
+
+// changed returns the paths, relative to the source, that an event should be replayed on: the file for
+// new or written files, and its directory for files that were created, removed or renamed.
+func (w *Watcher) changed(ev fsnotify.Event) []string {
+	rel, err := filepath.Rel(w.source, ev.Name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || w.ignored(rel) {
+		return nil
+	}
+	dir := filepath.Dir(rel)
+
+	var paths []string
+	if ev.Op&(fsnotify.Create|fsnotify.Write) != 0 {
+		paths = append(paths, rel)
+	}
+	// attribute changes are ignored, as that is all replaying changes does
+	if ev.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 && rel != "." {
+		paths = append(paths, dir)
+	}
+	return paths
+}
+
+// ignored returns whether a path relative to the source matches an ignore pattern
+func (w *Watcher) ignored(rel string) bool {
+	for _, pattern := range w.cfg.Ignore {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+			if ok, _ := filepath.Match(pattern, elem); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// watchTree watches a directory and all of the directories below it, apart from ignored ones
+func (w *Watcher) watchTree(root string) error {
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files may disappear while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(w.source, p); err == nil && rel != "." && w.ignored(rel) {
+			return filepath.SkipDir
+		}
+		if err := w.fsw.Add(p); err != nil {
+			return errors.Wrapf(err, "watch %s", p)
+		}
+		return nil
+	})
+}
+
+// replay touches changed paths within the VM
+func (w *Watcher) replay(pending map[string]bool) {
+	if len(pending) == 0 {
+		return
+	}
+	paths := []string{}
+	for p := range pending {
+		paths = append(paths, filepath.ToSlash(p))
+	}
+	sort.Strings(paths)
+	glog.Infof("replaying changes to %d paths in %s", len(paths), w.target)
+
+	for len(paths) > 0 {
+		n := len(paths)
+		if n > replayBatch {
+			n = replayBatch
+		}
+		if _, err := w.r.RunCmd(replayCmd(w.target, paths[:n])); err != nil {
+			glog.Warningf("unable to replay changes in %s: %v", w.target, err)
+		}
+		paths = paths[n:]
+	}
+}
+
+// replayCmd returns the command touching paths relative to a directory of the VM
+func replayCmd(dir string, paths []string) *exec.Cmd {
+	args := []string{shellQuote(replayScript), shellQuote(dir)}
+	for _, p := range paths {
+		args = append(args, shellQuote(p))
+	}
+	return exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo bash -c %s", strings.Join(args, " ")))
+}
+
+// shellQuote quotes a string for the shell
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
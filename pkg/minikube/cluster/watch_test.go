@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// recordingRunner sends the commands it is asked to run to a channel
+type recordingRunner struct {
+	cmds chan string
+}
+
+func (r *recordingRunner) RunCmd(cmd *exec.Cmd) (*command.RunResult, error) {
+	r.cmds <- strings.Join(cmd.Args[2:], " ")
+	return &command.RunResult{}, nil
+}
+
+func TestWatcherIgnored(t *testing.T) {
+	w := &Watcher{cfg: WatchConfig{Ignore: []string{".git", "*.swp", filepath.Join("build", "*")}}}
+	var tests = map[string]bool{
+		"main.go":                                 false,
+		".git":                                    true,
+		filepath.Join(".git", "HEAD"):             true,
+		filepath.Join("src", ".main.go.swp"):      true,
+		filepath.Join("build", "out"):             true,
+		filepath.Join("src", "build", "out"):      false,
+		filepath.Join("node_modules", "x", "y"):   false,
+		filepath.Join("src", "gitignore", ".git"): true,
+	}
+	for rel, want := range tests {
+		if got := w.ignored(rel); got != want {
+			t.Errorf("ignored(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
+
+func TestWatcherChanged(t *testing.T) {
+	source := filepath.Join(string(filepath.Separator), "src")
+	w := &Watcher{source: source, cfg: WatchConfig{Ignore: []string{".git"}}}
+	var tests = []struct {
+		name string
+		ev   fsnotify.Event
+		want []string
+	}{
+		{"write", fsnotify.Event{Name: filepath.Join(source, "a", "b"), Op: fsnotify.Write}, []string{filepath.Join("a", "b")}},
+		{"create", fsnotify.Event{Name: filepath.Join(source, "a", "b"), Op: fsnotify.Create}, []string{filepath.Join("a", "b"), "a"}},
+		{"remove", fsnotify.Event{Name: filepath.Join(source, "b"), Op: fsnotify.Remove}, []string{"."}},
+		{"rename", fsnotify.Event{Name: filepath.Join(source, "a", "b"), Op: fsnotify.Rename}, []string{"a"}},
+		{"chmod", fsnotify.Event{Name: filepath.Join(source, "a"), Op: fsnotify.Chmod}, nil},
+		{"ignored", fsnotify.Event{Name: filepath.Join(source, ".git", "index"), Op: fsnotify.Write}, nil},
+		{"outside", fsnotify.Event{Name: filepath.Join(string(filepath.Separator), "other"), Op: fsnotify.Write}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, w.changed(tc.ev)); diff != "" {
+				t.Errorf("changed diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	source := filepath.Join(string(filepath.Separator), "src")
+	r := &recordingRunner{cmds: make(chan string, 10)}
+	w := &Watcher{r: r, source: source, target: "/mnt/src", cfg: WatchConfig{Debounce: 50 * time.Millisecond}}
+
+	events := make(chan fsnotify.Event)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- w.run(events, nil, stop)
+	}()
+
+	// changes within the debounce period are replayed together
+	events <- fsnotify.Event{Name: filepath.Join(source, "a"), Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: filepath.Join(source, "b", "it's"), Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: filepath.Join(source, "a"), Op: fsnotify.Write}
+
+	want := `sudo bash -c '` + strings.Replace(replayScript, "'", `'\''`, -1) + `' '/mnt/src' 'a' 'b/it'\''s'`
+	select {
+	case got := <-r.cmds:
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("command diff (-want +got): %s", diff)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("changes were not replayed")
+	}
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Errorf("run: %v", err)
+	}
+	select {
+	case got := <-r.cmds:
+		t.Errorf("unexpected command after the changes were replayed: %s", got)
+	default:
+	}
+}
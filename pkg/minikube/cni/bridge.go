@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// bridgeConf is the CNI configuration of the bridge plugin, which connects the pods of a single node
+var bridgeConf = template.Must(template.New("bridge").Parse(`
+{
+  "cniVersion": "0.3.1",
+  "name": "bridge",
+  "type": "bridge",
+  "bridge": "bridge",
+  "addIf": "true",
+  "isDefaultGateway": true,
+  "forceAddress": false,
+  "ipMasq": true,
+  "hairpinMode": true,
+  "ipam": {
+    "type": "host-local",
+    "subnet": "{{.PodCIDR}}"
+  }
+}
+`))
+
+// Bridge is a simple CNI for single-node clusters, written to the node rather than deployed into the cluster
+type Bridge struct {
+	cc config.ClusterConfig
+}
+
+// String returns a string representation of this CNI
+func (c Bridge) String() string {
+	return "bridge CNI"
+}
+
+// Apply writes the bridge configuration to the node
+func (c Bridge) Apply(r command.Runner) error {
+	if len(c.cc.Nodes) > 1 {
+		return fmt.Errorf("bridge CNI is incompatible with multi-node clusters")
+	}
+
+	b := bytes.Buffer{}
+	if err := bridgeConf.Execute(&b, tmplInput{PodCIDR: c.CIDR()}); err != nil {
+		return errors.Wrap(err, "bridge config")
+	}
+	f := assets.NewMemoryAssetTarget(b.Bytes(), path.Join(DefaultConfDir, "1-k8s.conf"), "0644")
+	if err := r.Copy(f); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+	return restartRuntime(c.cc, r)
+}
+
+// CIDR returns the pod CIDR of this CNI
+func (c Bridge) CIDR() string {
+	return podCIDR(c.cc)
+}
+
+// SupportsNetworkPolicy returns whether this CNI enforces NetworkPolicy
+func (c Bridge) SupportsNetworkPolicy() bool {
+	return false
+}
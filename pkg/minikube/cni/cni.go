@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cni configures the Container Networking Interface of a cluster
+package cni
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// DefaultConfDir is the directory CNI configurations are read from
+const DefaultConfDir = "/etc/cni/net.d"
+
+// builtins are the CNIs which can be selected by name, anything else being the path to a manifest
+var builtins = []string{"", "auto", "false", "bridge", "kindnet", "flannel", "calico"}
+
+// Manager is a common interface for CNIs
+type Manager interface {
+	// Apply applies the CNI to the cluster, using the command runner of a control plane
+	Apply(command.Runner) error
+
+	// CIDR returns the pod CIDR the CNI assigns addresses from, or "" if the CNI is disabled
+	CIDR() string
+
+	// SupportsNetworkPolicy returns whether the CNI enforces NetworkPolicy resources
+	SupportsNetworkPolicy() bool
+
+	// String returns a human-readable name of the CNI
+	String() string
+}
+
+// tmplInput is the input of the manifest templates
+type tmplInput struct {
+	ImageName string
+	PodCIDR   string
+}
+
+// New returns the CNI manager selected for a cluster with --cni
+func New(cc config.ClusterConfig) (Manager, error) {
+	glog.Infof("Creating CNI manager for %q", cc.KubernetesConfig.CNI)
+
+	switch strings.ToLower(cc.KubernetesConfig.CNI) {
+	case "", "auto":
+		return chooseDefault(cc), nil
+	case "false":
+		return Disabled{cc: cc}, nil
+	case "bridge":
+		return Bridge{cc: cc}, nil
+	case "kindnet":
+		return KindNet{cc: cc}, nil
+	case "flannel":
+		return Flannel{cc: cc}, nil
+	case "calico":
+		return Calico{cc: cc}, nil
+	default:
+		return NewCustom(cc, cc.KubernetesConfig.CNI)
+	}
+}
+
+// IsCustom returns whether a --cni value is the path to a manifest rather than the name of a built-in CNI
+func IsCustom(name string) bool {
+	for _, b := range builtins {
+		if strings.EqualFold(name, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisabled returns whether a CNI manager leaves pod networking to the container runtime
+func IsDisabled(cnm Manager) bool {
+	_, ok := cnm.(Disabled)
+	return ok
+}
+
+// chooseDefault returns the CNI a cluster needs, if the user did not choose one
+func chooseDefault(cc config.ClusterConfig) Manager {
+	if config.MultiNode(cc) {
+		return KindNet{cc: cc}
+	}
+
+	// the overlay is required for containerd and cri-o runtime: see #7428
+	if driver.IsKIC(cc.Driver) && cc.KubernetesConfig.ContainerRuntime != "docker" {
+		return KindNet{cc: cc}
+	}
+
+	// for backwards compatibility with profiles created with --enable-default-cni
+	if cc.KubernetesConfig.EnableDefaultCNI {
+		return Bridge{cc: cc}
+	}
+
+	return Disabled{cc: cc}
+}
+
+// podCIDR returns the pod CIDR of a cluster, which may be set with --extra-config=kubeadm.pod-network-cidr
+func podCIDR(cc config.ClusterConfig) string {
+	if cidr := cc.KubernetesConfig.ExtraOptions.Get("pod-network-cidr", "kubeadm"); cidr != "" {
+		return cidr
+	}
+	return config.DefaultPodCIDR
+}
+
+// manifestPath returns where CNI manifests are copied to on the control plane
+func manifestPath() string {
+	return path.Join(vmpath.GuestEphemeralDir, "cni.yaml")
+}
+
+// manifestAsset renders a manifest template for a cluster
+func manifestAsset(tmpl *template.Template, input interface{}) (assets.CopyableFile, error) {
+	b := bytes.Buffer{}
+	if err := tmpl.Execute(&b, input); err != nil {
+		return nil, errors.Wrap(err, "manifest")
+	}
+	return assets.NewMemoryAssetTarget(b.Bytes(), manifestPath(), "0644"), nil
+}
+
+// applyManifest applies a CNI manifest with the kubectl of the control plane
+func applyManifest(cc config.ClusterConfig, r command.Runner, f assets.CopyableFile) error {
+	if err := r.Copy(f); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	kubectl := path.Join(vmpath.GuestPersistentDir, "binaries", cc.KubernetesConfig.KubernetesVersion, "kubectl")
+	cmd := exec.CommandContext(ctx, "sudo", kubectl, "apply",
+		fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig")),
+		"-f", manifestPath())
+	if rr, err := r.RunCmd(cmd); err != nil {
+		return errors.Wrapf(err, "cmd: %s output: %s", rr.Command(), rr.Output())
+	}
+
+	return restartRuntime(cc, r)
+}
+
+// restartRuntime informs cri-o that the CNI has changed
+func restartRuntime(cc config.ClusterConfig, r command.Runner) error {
+	if cc.KubernetesConfig.ContainerRuntime != "crio" {
+		return nil
+	}
+	if err := sysinit.New(r).Restart("crio"); err != nil {
+		return errors.Wrap(err, "restart crio")
+	}
+	return nil
+}
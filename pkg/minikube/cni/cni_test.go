@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNew(t *testing.T) {
+	single := []config.Node{{Name: "m01", ControlPlane: true, Worker: true}}
+	multi := []config.Node{{Name: "m01", ControlPlane: true, Worker: true}, {Name: "m02", Worker: true}}
+
+	tests := []struct {
+		name string
+		cc   config.ClusterConfig
+		want string
+	}{
+		{"default", config.ClusterConfig{Driver: "kvm2", Nodes: single, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}, "Disabled"},
+		{"multi-node", config.ClusterConfig{Driver: "kvm2", Nodes: multi, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}, "kindnet"},
+		{"kic containerd", config.ClusterConfig{Driver: "docker", Nodes: single, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "containerd"}}, "kindnet"},
+		{"enable-default-cni", config.ClusterConfig{Driver: "kvm2", Nodes: single, KubernetesConfig: config.KubernetesConfig{EnableDefaultCNI: true}}, "bridge CNI"},
+		{"disabled", config.ClusterConfig{Driver: "kvm2", Nodes: multi, KubernetesConfig: config.KubernetesConfig{CNI: "false"}}, "Disabled"},
+		{"flannel", config.ClusterConfig{Nodes: single, KubernetesConfig: config.KubernetesConfig{CNI: "flannel"}}, "Flannel"},
+		{"calico", config.ClusterConfig{Nodes: single, KubernetesConfig: config.KubernetesConfig{CNI: "Calico"}}, "Calico"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cnm, err := New(tc.cc)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := cnm.String(); got != tc.want {
+				t.Errorf("New = %s, expected %s", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := New(config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{CNI: "/nonexistent/cni.yaml"}}); err == nil {
+		t.Errorf("expected an error for a missing manifest")
+	}
+}
+
+func TestIsCustom(t *testing.T) {
+	for _, name := range []string{"", "auto", "false", "bridge", "KindNet", "flannel", "calico"} {
+		if IsCustom(name) {
+			t.Errorf("IsCustom(%q) = true, expected a built-in CNI", name)
+		}
+	}
+	if !IsCustom("./cilium.yaml") {
+		t.Errorf("IsCustom(./cilium.yaml) = false, expected a manifest")
+	}
+}
+
+func TestCIDR(t *testing.T) {
+	cc := config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{CNI: "calico"}}
+	cnm, err := New(cc)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := cnm.CIDR(); got != config.DefaultPodCIDR {
+		t.Errorf("CIDR = %s, expected %s", got, config.DefaultPodCIDR)
+	}
+	if !cnm.SupportsNetworkPolicy() {
+		t.Errorf("calico should support NetworkPolicy")
+	}
+
+	if err := cc.KubernetesConfig.ExtraOptions.Set("kubeadm.pod-network-cidr=192.168.0.0/16"); err != nil {
+		t.Fatalf("set extra option: %v", err)
+	}
+	cnm, err = New(cc)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := cnm.CIDR(); got != "192.168.0.0/16" {
+		t.Errorf("CIDR = %s, expected the pod-network-cidr extra option", got)
+	}
+
+	cnm, err = New(config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{CNI: "false"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := cnm.CIDR(); got != "" {
+		t.Errorf("CIDR of a disabled CNI = %s, expected none", got)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Custom applies a CNI manifest provided by the user
+type Custom struct {
+	cc       config.ClusterConfig
+	manifest string
+}
+
+// NewCustom returns a CNI manager for a manifest on the host
+func NewCustom(cc config.ClusterConfig, manifest string) (Custom, error) {
+	if _, err := os.Stat(manifest); err != nil {
+		return Custom{}, errors.Wrap(err, "CNI manifest")
+	}
+	return Custom{cc: cc, manifest: manifest}, nil
+}
+
+// String returns a string representation of this CNI
+func (c Custom) String() string {
+	return c.manifest
+}
+
+// Apply applies the manifest to the cluster
+func (c Custom) Apply(r command.Runner) error {
+	m, err := assets.NewFileAsset(c.manifest, path.Dir(manifestPath()), path.Base(manifestPath()), "0644")
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	return applyManifest(c.cc, r, m)
+}
+
+// CIDR returns the pod CIDR of the cluster, which the manifest is expected to use
+func (c Custom) CIDR() string {
+	return podCIDR(c.cc)
+}
+
+// SupportsNetworkPolicy returns false, as it cannot be known from the manifest
+func (c Custom) SupportsNetworkPolicy() bool {
+	return false
+}
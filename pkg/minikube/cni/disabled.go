@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Disabled leaves pod networking to the container runtime, which only works for single-node clusters
+type Disabled struct {
+	cc config.ClusterConfig
+}
+
+// String returns a string representation of this CNI
+func (c Disabled) String() string {
+	return "Disabled"
+}
+
+// Apply does nothing, as the CNI is disabled
+func (c Disabled) Apply(r command.Runner) error {
+	return nil
+}
+
+// CIDR returns no CIDR, leaving the pod CIDR unset
+func (c Disabled) CIDR() string {
+	return ""
+}
+
+// SupportsNetworkPolicy returns false, as nothing enforces NetworkPolicy
+func (c Disabled) SupportsNetworkPolicy() bool {
+	return false
+}
@@ -1,5 +1,5 @@
 /*
-Copyright 2018 The Kubernetes Authors All rights reserved.
+Copyright 2020 The Kubernetes Authors All rights reserved.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,39 +14,19 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package kubeadm
+package cni
 
-import "html/template"
+import (
+	"text/template"
 
-// defaultCNIConfig is the CNI config which is provisioned when --enable-default-cni
-// has been passed to `minikube start`.
-//
-// The config is being written to /etc/cni/net.d/k8s.conf.
-const defaultCNIConfig = `
-{
-  "cniVersion": "0.3.0",
-  "name": "rkt.kubernetes.io",
-  "type": "bridge",
-  "bridge": "mybridge",
-  "mtu": 1460,
-  "addIf": "true",
-  "isGateway": true,
-  "ipMasq": true,
-  "ipam": {
-    "type": "host-local",
-    "subnet": "10.1.0.0/16",
-    "gateway": "10.1.0.1",
-    "routes": [
-      {
-        "dst": "0.0.0.0/0"
-      }
-    ]
-  }
-}
-`
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
 
-// kicCNIConfig is the cni plugin needed for kic uses cni plugin created by kind https://github.com/kubernetes-sigs/kind/blob/03a4b519067dc308308cce735065c47a6fda1583/pkg/build/node/cni.go
-var kicCNIConfig = template.Must(template.New("kubeletServiceTemplate").Parse(`---
+// kindNetManifest is the manifest of kindnet, the CNI created by kind: https://github.com/kubernetes-sigs/kind/blob/03a4b519067dc308308cce735065c47a6fda1583/pkg/build/node/cni.go
+var kindNetManifest = template.Must(template.New("kindnet").Parse(`---
 kind: ClusterRole
 apiVersion: rbac.authorization.k8s.io/v1
 metadata:
@@ -58,7 +38,7 @@ rules:
     - podsecuritypolicies
     verbs:
     - use
-    resourceNames: 
+    resourceNames:
     - kindnet
   - apiGroups:
       - ""
@@ -125,7 +105,7 @@ spec:
             fieldRef:
               fieldPath: status.podIP
         - name: POD_SUBNET
-          value: 10.244.0.0/16
+          value: {{.PodCIDR}}
         volumeMounts:
         - name: cni-cfg
           mountPath: /etc/cni/net.d
@@ -160,3 +140,32 @@ spec:
 
 ---
 `))
+
+// KindNet is a simple CNI for multi-node clusters, which routes pods between nodes on the same network
+type KindNet struct {
+	cc config.ClusterConfig
+}
+
+// String returns a string representation of this CNI
+func (c KindNet) String() string {
+	return "kindnet"
+}
+
+// Apply applies the kindnet manifest to the cluster
+func (c KindNet) Apply(r command.Runner) error {
+	m, err := manifestAsset(kindNetManifest, tmplInput{ImageName: kic.OverlayImage, PodCIDR: c.CIDR()})
+	if err != nil {
+		return errors.Wrap(err, "kindnet")
+	}
+	return applyManifest(c.cc, r, m)
+}
+
+// CIDR returns the pod CIDR of this CNI
+func (c KindNet) CIDR() string {
+	return podCIDR(c.cc)
+}
+
+// SupportsNetworkPolicy returns whether this CNI enforces NetworkPolicy
+func (c KindNet) SupportsNetworkPolicy() bool {
+	return false
+}
@@ -46,6 +46,8 @@ var (
 type SSHRunner struct {
 	d drivers.Driver
 	c *ssh.Client
+	// mu guards c, as commands may be run concurrently
+	mu sync.Mutex
 }
 
 // NewSSHRunner returns a new SSHRunner that will run commands
@@ -56,6 +58,8 @@ func NewSSHRunner(d drivers.Driver) *SSHRunner {
 
 // client returns an ssh client (uses retry underneath)
 func (s *SSHRunner) client() (*ssh.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.c != nil {
 		return s.c, nil
 	}
@@ -80,7 +84,9 @@ func (s *SSHRunner) session() (*ssh.Session, error) {
 		sess, err = client.NewSession()
 		if err != nil {
 			glog.Warningf("session error, resetting client: %v", err)
+			s.mu.Lock()
 			s.c = nil
+			s.mu.Unlock()
 			return err
 		}
 		return nil
@@ -49,6 +49,8 @@ const (
 	ShowDriverDeprecationNotification = "ShowDriverDeprecationNotification"
 	// ShowBootstrapperDeprecationNotification is the key for ShowBootstrapperDeprecationNotification
 	ShowBootstrapperDeprecationNotification = "ShowBootstrapperDeprecationNotification"
+	// ProblemsURL is the key for the URL of a YAML file of known problems, added to the built-in ones
+	ProblemsURL = "problems-url"
 )
 
 var (
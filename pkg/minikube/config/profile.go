@@ -52,6 +52,43 @@ func (p *Profile) IsValid() bool {
 	return true
 }
 
+// ControlPlanes returns the control plane nodes of a cluster, the primary one first
+func ControlPlanes(cc ClusterConfig) []Node {
+	cps := []Node{}
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			cps = append(cps, n)
+		}
+	}
+	return cps
+}
+
+// IsPrimaryControlPlane returns whether a node is the first created control plane of a cluster
+func IsPrimaryControlPlane(cc ClusterConfig, n Node) bool {
+	cps := ControlPlanes(cc)
+	return len(cps) > 0 && cps[0].Name == n.Name
+}
+
+// IsHA returns whether the control planes of a cluster are fronted by a virtual IP, which is the case
+// for clusters created with more than one control plane
+func IsHA(cc ClusterConfig) bool {
+	return cc.KubernetesConfig.APIServerHAVIP != ""
+}
+
+// NodeResources returns the cluster config with the resources a node overrides, to create the machine of the node
+func NodeResources(cc ClusterConfig, n Node) ClusterConfig {
+	if n.CPUs != 0 {
+		cc.CPUs = n.CPUs
+	}
+	if n.Memory != 0 {
+		cc.Memory = n.Memory
+	}
+	if n.DiskSize != 0 {
+		cc.DiskSize = n.DiskSize
+	}
+	return cc
+}
+
 // PrimaryControlPlane gets the node specific config for the first created control plane
 func PrimaryControlPlane(cc *ClusterConfig) (Node, error) {
 	for _, n := range cc.Nodes {
@@ -137,12 +174,6 @@ func SaveNode(cfg *ClusterConfig, node *Node) error {
 		cfg.Nodes = append(cfg.Nodes, *node)
 	}
 
-	if MultiNode(*cfg) {
-		if err := MultiNodeCNIConfig(cfg); err != nil {
-			return err
-		}
-	}
-
 	return SaveProfile(viper.GetString(ProfileName), cfg)
 }
 
@@ -283,3 +283,50 @@ func TestGetPrimaryControlPlane(t *testing.T) {
 	}
 
 }
+
+func TestControlPlanes(t *testing.T) {
+	cc := ClusterConfig{
+		Nodes: []Node{
+			{Name: "m01", ControlPlane: true, Worker: true},
+			{Name: "m02", Worker: true},
+			{Name: "m03", ControlPlane: true, Worker: true},
+		},
+	}
+
+	cps := ControlPlanes(cc)
+	if len(cps) != 2 || cps[0].Name != "m01" || cps[1].Name != "m03" {
+		t.Errorf("ControlPlanes returned %+v, expected m01 and m03", cps)
+	}
+
+	for _, n := range cc.Nodes {
+		want := n.Name == "m01"
+		if got := IsPrimaryControlPlane(cc, n); got != want {
+			t.Errorf("IsPrimaryControlPlane(%s) = %t, expected %t", n.Name, got, want)
+		}
+	}
+
+	if IsHA(cc) {
+		t.Errorf("IsHA returned true for a cluster without a virtual IP")
+	}
+	cc.KubernetesConfig.APIServerHAVIP = "192.168.39.254"
+	if !IsHA(cc) {
+		t.Errorf("IsHA returned false for a cluster with a virtual IP")
+	}
+}
+
+func TestNodeResources(t *testing.T) {
+	cc := ClusterConfig{CPUs: 2, Memory: 2200, DiskSize: 20000}
+
+	got := NodeResources(cc, Node{Name: "m02"})
+	if got.CPUs != 2 || got.Memory != 2200 || got.DiskSize != 20000 {
+		t.Errorf("NodeResources without overrides = %d/%d/%d, expected the cluster resources", got.CPUs, got.Memory, got.DiskSize)
+	}
+
+	got = NodeResources(cc, Node{Name: "m03", CPUs: 4, Memory: 8192})
+	if got.CPUs != 4 || got.Memory != 8192 || got.DiskSize != 20000 {
+		t.Errorf("NodeResources with overrides = %d/%d/%d, expected 4/8192/20000", got.CPUs, got.Memory, got.DiskSize)
+	}
+	if cc.CPUs != 2 {
+		t.Errorf("NodeResources modified the cluster config")
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SpecAPIVersion is the current version of the cluster spec format
+	SpecAPIVersion = "minikube.sigs.k8s.io/v1alpha1"
+	// SpecKind is the kind of a cluster spec document
+	SpecKind = "ClusterSpec"
+)
+
+// ClusterSpec is a declarative, versioned description of a cluster used by `minikube start --config-file`.
+// Apart from the header, its fields are those of ClusterConfig, so that it can be written by hand or
+// exported from an existing profile.
+type ClusterSpec struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	ClusterConfig
+}
+
+// LoadSpec reads a YAML or JSON cluster spec from a file
+func LoadSpec(path string) (*ClusterSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read spec")
+	}
+	return ParseSpec(data)
+}
+
+// ParseSpec parses a YAML or JSON cluster spec, rejecting unknown fields and versions
+func ParseSpec(data []byte) (*ClusterSpec, error) {
+	s := &ClusterSpec{}
+	if err := yaml.UnmarshalStrict(data, s); err != nil {
+		return nil, errors.Wrap(err, "parse spec")
+	}
+	if s.APIVersion != SpecAPIVersion {
+		return nil, fmt.Errorf("unsupported spec apiVersion %q, expected %q", s.APIVersion, SpecAPIVersion)
+	}
+	if s.Kind != SpecKind {
+		return nil, fmt.Errorf("unsupported spec kind %q, expected %q", s.Kind, SpecKind)
+	}
+	if err := s.validateNodes(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validateNodes checks the nodes of a spec, which are not described by start flags
+func (s *ClusterSpec) validateNodes() error {
+	names := map[string]bool{}
+	for i, n := range s.Nodes {
+		if i == 0 && !n.ControlPlane {
+			return fmt.Errorf("the first node of a spec must be a control plane")
+		}
+		if !n.ControlPlane && !n.Worker {
+			return fmt.Errorf("node %q is neither a control plane nor a worker", n.Name)
+		}
+		// the name of the primary control plane is picked by the driver
+		if i > 0 && !ProfileNameValid(n.Name) {
+			return fmt.Errorf("invalid node name %q", n.Name)
+		}
+		if names[n.Name] {
+			return fmt.Errorf("duplicate node name %q", n.Name)
+		}
+		names[n.Name] = true
+		if n.CPUs < 0 || n.Memory < 0 || n.DiskSize < 0 {
+			return fmt.Errorf("node %q has negative resources", n.Name)
+		}
+	}
+	return nil
+}
+
+// NewSpec returns a spec describing an existing cluster, without the details that are specific to one machine
+func NewSpec(cc ClusterConfig) *ClusterSpec {
+	s := &ClusterSpec{
+		APIVersion:    SpecAPIVersion,
+		Kind:          SpecKind,
+		ClusterConfig: cc,
+	}
+	s.Name = ""
+	s.UUID = ""
+	s.KubernetesConfig.ClusterName = ""
+	s.KubernetesConfig.NodeIP = ""
+	s.KubernetesConfig.NodeName = ""
+	s.KubernetesConfig.APIServerHAVIP = ""
+	s.ScheduledStop = nil
+
+	s.Nodes = nil
+	for _, n := range cc.Nodes {
+		n.IP = ""
+		s.Nodes = append(s.Nodes, n)
+	}
+	return s
+}
+
+// Marshal returns the spec encoded as "yaml" or "json"
+func (s *ClusterSpec) Marshal(format string) ([]byte, error) {
+	switch format {
+	case "yaml":
+		return yaml.Marshal(s)
+	case "json":
+		return json.MarshalIndent(s, "", "    ")
+	default:
+		return nil, fmt.Errorf("unsupported spec format %q", format)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSpec(t *testing.T) {
+	var tests = []struct {
+		description string
+		data        string
+		want        *ClusterSpec
+		wantErr     bool
+	}{
+		{
+			description: "yaml",
+			data: `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: ClusterSpec
+CPUs: 4
+Driver: docker
+KubernetesConfig:
+  ContainerRuntime: containerd
+Addons:
+  ingress: true
+`,
+			want: &ClusterSpec{
+				APIVersion: SpecAPIVersion,
+				Kind:       SpecKind,
+				ClusterConfig: ClusterConfig{
+					CPUs:             4,
+					Driver:           "docker",
+					KubernetesConfig: KubernetesConfig{ContainerRuntime: "containerd"},
+					Addons:           map[string]bool{"ingress": true},
+				},
+			},
+		},
+		{
+			description: "json",
+			data:        `{"apiVersion": "minikube.sigs.k8s.io/v1alpha1", "kind": "ClusterSpec", "Memory": 4096}`,
+			want: &ClusterSpec{
+				APIVersion:    SpecAPIVersion,
+				Kind:          SpecKind,
+				ClusterConfig: ClusterConfig{Memory: 4096},
+			},
+		},
+		{
+			description: "unknown version",
+			data:        "apiVersion: minikube.sigs.k8s.io/v2\nkind: ClusterSpec\n",
+			wantErr:     true,
+		},
+		{
+			description: "nodes",
+			data: `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: ClusterSpec
+Nodes:
+- ControlPlane: true
+  Worker: true
+- Name: m02
+  Worker: true
+  CPUs: 4
+  Labels:
+    tier: db
+`,
+			want: &ClusterSpec{
+				APIVersion: SpecAPIVersion,
+				Kind:       SpecKind,
+				ClusterConfig: ClusterConfig{
+					Nodes: []Node{
+						{ControlPlane: true, Worker: true},
+						{Name: "m02", Worker: true, CPUs: 4, Labels: map[string]string{"tier": "db"}},
+					},
+				},
+			},
+		},
+		{
+			description: "first node is not a control plane",
+			data:        "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: ClusterSpec\nNodes:\n- Name: m01\n  Worker: true\n",
+			wantErr:     true,
+		},
+		{
+			description: "duplicate node names",
+			data:        "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: ClusterSpec\nNodes:\n- ControlPlane: true\n- Name: m02\n  Worker: true\n- Name: m02\n  Worker: true\n",
+			wantErr:     true,
+		},
+		{
+			description: "unknown field",
+			data:        "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: ClusterSpec\nCPU: 4\n",
+			wantErr:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := ParseSpec([]byte(test.data))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("spec mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewSpecRoundTrip(t *testing.T) {
+	cc := ClusterConfig{
+		Name:   "p1",
+		UUID:   "1234",
+		CPUs:   2,
+		Driver: "kvm2",
+		KubernetesConfig: KubernetesConfig{
+			ClusterName:       "p1",
+			KubernetesVersion: "v1.18.3",
+		},
+		Nodes: []Node{{Name: "m01", IP: "192.168.39.2", ControlPlane: true, Worker: true}},
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		data, err := NewSpec(cc).Marshal(format)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", format, err)
+		}
+		got, err := ParseSpec(data)
+		if err != nil {
+			t.Fatalf("%s: parse: %v", format, err)
+		}
+		if got.Name != "" || got.UUID != "" || got.KubernetesConfig.ClusterName != "" || got.Nodes[0].IP != "" {
+			t.Errorf("%s: machine specific fields were exported: %+v", format, got)
+		}
+		if got.CPUs != cc.CPUs || got.Driver != cc.Driver || got.KubernetesConfig.KubernetesVersion != cc.KubernetesConfig.KubernetesVersion {
+			t.Errorf("%s: spec does not match config: %+v", format, got)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package config
 
 import (
 	"net"
+	"time"
 
 	"github.com/blang/semver"
 )
@@ -74,7 +75,10 @@ type ClusterConfig struct {
 	KubernetesConfig        KubernetesConfig
 	Nodes                   []Node
 	Addons                  map[string]bool
-	VerifyComponents        map[string]bool // map of components to verify and wait for after start.
+	AddonValues             map[string]map[string]string // per-addon settings, set with `addons enable --set`
+	VerifyComponents        map[string]bool              // map of components to verify and wait for after start.
+	AutoStop                time.Duration                // stop once the API servers have seen no new connections for this long
+	ScheduledStop           *time.Time                   `json:",omitempty"` // when the cluster is due to stop, set by 'minikube stop --schedule'
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -93,10 +97,12 @@ type KubernetesConfig struct {
 	ImageRepository     string
 	LoadBalancerStartIP string // currently only used by MetalLB addon
 	LoadBalancerEndIP   string // currently only used by MetalLB addon
+	APIServerHAVIP      string // the virtual IP in front of the control planes of a highly-available cluster
 	ExtraOptions        ExtraOptionSlice
 
 	ShouldLoadCachedImages bool
-	EnableDefaultCNI       bool
+	EnableDefaultCNI       bool   // deprecated in favor of CNI
+	CNI                    string // CNI to use: auto, false, bridge, kindnet, flannel, calico or the path to a manifest
 
 	// We need to keep these in the short term for backwards compatibility
 	NodeIP   string
@@ -112,6 +118,13 @@ type Node struct {
 	KubernetesVersion string
 	ControlPlane      bool
 	Worker            bool
+	CPUs              int               // overrides the CPUs of the cluster, if set
+	Memory            int               // overrides the memory of the cluster in MB, if set
+	DiskSize          int               // overrides the disk size of the cluster in MB, if set
+	Labels            map[string]string // Kubernetes labels of the node
+	Taints            []string          // Kubernetes taints of the node, formatted as key[=value]:effect
+	Zone              string            // the topology.kubernetes.io/zone label of the node
+	Region            string            // the topology.kubernetes.io/region label of the node
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
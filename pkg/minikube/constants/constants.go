@@ -68,6 +68,16 @@ const (
 	// MinikubeActivePodmanEnv holds the podman service that the user's shell is pointing at
 	// value would be profile or empty if pointing to the user's host.
 	MinikubeActivePodmanEnv = "MINIKUBE_ACTIVE_PODMAN"
+	// ContainerdAddressEnv is the containerd socket that nerdctl uses
+	ContainerdAddressEnv = "CONTAINERD_ADDRESS"
+	// ContainerdNamespaceEnv is the containerd namespace that nerdctl uses
+	ContainerdNamespaceEnv = "CONTAINERD_NAMESPACE"
+	// ContainerRuntimeEndpointEnv is the CRI runtime endpoint that crictl uses
+	ContainerRuntimeEndpointEnv = "CONTAINER_RUNTIME_ENDPOINT"
+	// ImageServiceEndpointEnv is the CRI image endpoint that crictl uses
+	ImageServiceEndpointEnv = "IMAGE_SERVICE_ENDPOINT"
+	// MinikubeActiveContainerRuntimeEnv holds the profile whose container runtime socket the user's shell is pointing at
+	MinikubeActiveContainerRuntimeEnv = "MINIKUBE_ACTIVE_CONTAINER_RUNTIME"
 	// MinikubeForceSystemdEnv is used to force systemd as cgroup manager for the container runtime
 	MinikubeForceSystemdEnv = "MINIKUBE_FORCE_SYSTEMD"
 )
@@ -77,8 +87,12 @@ var (
 	IsMinikubeChildProcess = "IS_MINIKUBE_CHILD_PROCESS"
 	// GvisorConfigTomlTargetName is the go-bindata target name for the gvisor config.toml
 	GvisorConfigTomlTargetName = "gvisor-config.toml"
-	// MountProcessFileName is the filename of the mount process
+	// MountProcessFileName is the filename of the mount process of a profile
 	MountProcessFileName = ".mount-process"
+	// ScheduledStopProcessFileName is the filename of the scheduled stop watcher of a profile
+	ScheduledStopProcessFileName = ".scheduled-stop-process"
+	// SocketForwardProcessFileName is the filename of the container runtime socket forwarder of a profile
+	SocketForwardProcessFileName = ".socket-forward-process"
 
 	// SHASuffix is the suffix of a SHA-256 checksum file
 	SHASuffix = ".sha256"
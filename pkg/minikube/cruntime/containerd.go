@@ -29,6 +29,7 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/golang/glog"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
@@ -41,6 +42,8 @@ import (
 
 const (
 	containerdNamespaceRoot = "/run/containerd/runc/k8s.io"
+	// buildkitService is the buildkitd service of the ISO, which builds images into the k8s.io namespace
+	buildkitService = "buildkit"
 	// ContainerdConfFile is the path to the containerd configuration
 	containerdConfigFile     = "/etc/containerd/config.toml"
 	containerdConfigTemplate = `root = "/var/lib/containerd"
@@ -241,6 +244,61 @@ func (r *Containerd) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns the names of all images in the runtime
+func (r *Containerd) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image by name
+func (r *Containerd) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// PullImage pulls an image from its registry
+func (r *Containerd) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// BuildImage builds an image from a build context directory with buildkit, tagging it with the given name
+func (r *Containerd) BuildImage(src string, tag string) error {
+	glog.Infof("Building image from: %s", src)
+	if _, err := r.Runner.RunCmd(exec.Command("which", "buildctl")); err != nil {
+		return errors.Wrap(ErrBuildNotSupported, "buildctl not found")
+	}
+	ref, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	// buildkitd is only started when needed, as most clusters never build an image
+	if err := r.Init.Start(buildkitService); err != nil {
+		return errors.Wrap(err, "starting buildkit")
+	}
+	c := exec.Command("sudo", "buildctl", "build",
+		"--frontend", "dockerfile.v0",
+		"--local", fmt.Sprintf("context=%s", src),
+		"--local", fmt.Sprintf("dockerfile=%s", src),
+		"--output", fmt.Sprintf("type=image,name=%s,unpack=true", ref))
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "buildctl build")
+	}
+	return nil
+}
+
+// normalizeTag returns the fully qualified name the CRI plugin of containerd looks images up by,
+// such as docker.io/library/my-app:latest for my-app
+func normalizeTag(tag string) (string, error) {
+	t, err := name.NewTag(tag, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing tag %s", tag)
+	}
+	registry := t.RegistryStr()
+	if registry == name.DefaultRegistry {
+		registry = "docker.io"
+	}
+	return fmt.Sprintf("%s/%s:%s", registry, t.RepositoryStr(), t.TagStr()), nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Containerd) CGroupDriver() (string, error) {
 	info, err := getCRIInfo(r.Runner)
@@ -300,13 +358,13 @@ func (r *Containerd) StopContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *Containerd) ContainerLogCmd(id string, len int, follow bool) string {
-	return criContainerLogCmd(r.Runner, id, len, follow)
+func (r *Containerd) ContainerLogCmd(id string, o LogOptions) string {
+	return criContainerLogCmd(r.Runner, id, o)
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Containerd) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u containerd -n %d", len)
+func (r *Containerd) SystemLogCmd(o LogOptions) string {
+	return journalLogCmd("containerd", o)
 }
 
 // Preload preloads the container runtime with k8s images
@@ -23,6 +23,7 @@ import (
 	"html/template"
 	"os/exec"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/golang/glog"
@@ -47,6 +48,10 @@ func crictlList(cr CommandRunner, root string, o ListOptions) (*command.RunResul
 		baseCmd = append(baseCmd, fmt.Sprintf("--name=%s", o.Name))
 	}
 
+	if o.PodName != "" {
+		baseCmd = append(baseCmd, fmt.Sprintf("--label=io.kubernetes.pod.name=%s", o.PodName))
+	}
+
 	// shortcut for all namespaces
 	if len(o.Namespaces) == 0 {
 		return cr.RunCmd(exec.Command("sudo", baseCmd...))
@@ -203,6 +208,55 @@ func stopCRIContainers(cr CommandRunner, ids []string) error {
 	return nil
 }
 
+// listCRIImages returns the names of all images, using crictl
+func listCRIImages(cr CommandRunner) ([]string, error) {
+	crictl := getCrictlPath(cr)
+	rr, err := cr.RunCmd(exec.Command("sudo", crictl, "images", "--output", "json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl images")
+	}
+
+	var jsonImages struct {
+		Images []struct {
+			RepoTags []string `json:"repoTags"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &jsonImages); err != nil {
+		return nil, errors.Wrap(err, "unmarshal images")
+	}
+
+	images := []string{}
+	for _, i := range jsonImages.Images {
+		images = append(images, i.RepoTags...)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// removeCRIImage removes an image using crictl
+func removeCRIImage(cr CommandRunner, name string) error {
+	glog.Infof("Removing image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "rmi", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
+
+// pullCRIImage pulls an image using crictl
+func pullCRIImage(cr CommandRunner, name string) error {
+	glog.Infof("Pulling image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "pull", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
+
 // populateCRIConfig sets up /etc/crictl.yaml
 func populateCRIConfig(cr CommandRunner, socket string) error {
 	cPath := "/etc/crictl.yaml"
@@ -243,19 +297,6 @@ func getCRIInfo(cr CommandRunner) (map[string]interface{}, error) {
 }
 
 // criContainerLogCmd returns the command to retrieve the log for a container based on ID
-func criContainerLogCmd(cr CommandRunner, id string, len int, follow bool) string {
-	crictl := getCrictlPath(cr)
-	var cmd strings.Builder
-	cmd.WriteString("sudo ")
-	cmd.WriteString(crictl)
-	cmd.WriteString(" logs ")
-	if len > 0 {
-		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
-	}
-	if follow {
-		cmd.WriteString("--follow ")
-	}
-
-	cmd.WriteString(id)
-	return cmd.String()
+func criContainerLogCmd(cr CommandRunner, id string, o LogOptions) string {
+	return "sudo " + getCrictlPath(cr) + " logs " + containerLogFlags(o) + id
 }
@@ -157,6 +157,31 @@ func (r *CRIO) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns the names of all images in the runtime
+func (r *CRIO) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image by name
+func (r *CRIO) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// PullImage pulls an image from its registry
+func (r *CRIO) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// BuildImage builds an image from a build context directory, tagging it with the given name
+func (r *CRIO) BuildImage(src string, tag string) error {
+	glog.Infof("Building image from: %s", src)
+	c := exec.Command("sudo", "podman", "build", "-t", tag, src)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio build image")
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *CRIO) CGroupDriver() (string, error) {
 	c := exec.Command("crio", "config")
@@ -213,13 +238,13 @@ func (r *CRIO) StopContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *CRIO) ContainerLogCmd(id string, len int, follow bool) string {
-	return criContainerLogCmd(r.Runner, id, len, follow)
+func (r *CRIO) ContainerLogCmd(id string, o LogOptions) string {
+	return criContainerLogCmd(r.Runner, id, o)
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *CRIO) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u crio -n %d", len)
+func (r *CRIO) SystemLogCmd(o LogOptions) string {
+	return journalLogCmd("crio", o)
 }
 
 // Preload preloads the container runtime with k8s images
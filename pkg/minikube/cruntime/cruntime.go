@@ -20,6 +20,8 @@ package cruntime
 import (
 	"fmt"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/golang/glog"
@@ -31,6 +33,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/sysinit"
 )
 
+// ErrBuildNotSupported is returned by runtimes which lack the tools to build images within the node
+var ErrBuildNotSupported = errors.New("building images is not supported by this node")
+
 // ContainerState is the run state of a container
 type ContainerState int
 
@@ -87,6 +92,14 @@ type Manager interface {
 
 	// ImageExists takes image name and image sha checks if an it exists
 	ImageExists(string, string) bool
+	// ListImages returns the names of all images in the runtime
+	ListImages() ([]string, error)
+	// RemoveImage removes an image by name
+	RemoveImage(string) error
+	// PullImage pulls an image from its registry
+	PullImage(string) error
+	// BuildImage builds an image from a build context directory, tagging it with the given name
+	BuildImage(string, string) error
 
 	// ListContainers returns a list of managed by this container runtime
 	ListContainers(ListOptions) ([]string, error)
@@ -99,9 +112,9 @@ type Manager interface {
 	// UnpauseContainers unpauses containers based on ID
 	UnpauseContainers([]string) error
 	// ContainerLogCmd returns the command to retrieve the log for a container based on ID
-	ContainerLogCmd(string, int, bool) string
+	ContainerLogCmd(string, LogOptions) string
 	// SystemLogCmd returns the command to return the system logs
-	SystemLogCmd(int) string
+	SystemLogCmd(LogOptions) string
 	// Preload preloads the container runtime with k8s images
 	Preload(config.KubernetesConfig) error
 }
@@ -128,6 +141,20 @@ type ListOptions struct {
 	Name string
 	// Namespaces is the namespaces to look into
 	Namespaces []string
+	// PodName is the name of the pod the containers belong to
+	PodName string
+}
+
+// LogOptions are the options to use for retrieving logs
+type LogOptions struct {
+	// Lines is the number of recent log lines to include, as in tail -n.
+	Lines int
+	// Follow is whether or not to actively follow the logs, as in tail -f.
+	Follow bool
+	// Since excludes the lines older than this, if set.
+	Since time.Duration
+	// Timestamps prefixes every line with its time, in RFC3339 format.
+	Timestamps bool
 }
 
 // New returns an appropriately configured runtime
@@ -235,3 +262,41 @@ func ImagesPreloaded(containerRuntime string, runner command.Runner, images []st
 	}
 	return false
 }
+
+// journalLogCmd returns the command to retrieve the system log of a unit
+func journalLogCmd(unit string, o LogOptions) string {
+	var cmd strings.Builder
+	cmd.WriteString(fmt.Sprintf("sudo journalctl -u %s", unit))
+	if o.Lines > 0 {
+		cmd.WriteString(fmt.Sprintf(" -n %d", o.Lines))
+	}
+	if o.Since > 0 {
+		cmd.WriteString(fmt.Sprintf(" --since=-%ds", int(o.Since.Seconds())))
+	}
+	if o.Timestamps {
+		cmd.WriteString(" -o short-iso-precise")
+	}
+	if o.Follow {
+		cmd.WriteString(" -f")
+	}
+	return cmd.String()
+}
+
+// containerLogFlags returns the flags of the command that retrieves the log of a container,
+// which docker and crictl have in common
+func containerLogFlags(o LogOptions) string {
+	var flags strings.Builder
+	if o.Lines > 0 {
+		flags.WriteString(fmt.Sprintf("--tail %d ", o.Lines))
+	}
+	if o.Since > 0 {
+		flags.WriteString(fmt.Sprintf("--since %ds ", int(o.Since.Seconds())))
+	}
+	if o.Timestamps {
+		flags.WriteString("--timestamps ")
+	}
+	if o.Follow {
+		flags.WriteString("--follow ")
+	}
+	return flags.String()
+}
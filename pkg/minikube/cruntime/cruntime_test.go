@@ -18,10 +18,13 @@ package cruntime
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/google/go-cmp/cmp"
@@ -157,6 +160,7 @@ type FakeRunner struct {
 	cmds       []string
 	services   map[string]serviceState
 	containers map[string]string
+	images     map[string]bool
 	t          *testing.T
 }
 
@@ -167,6 +171,7 @@ func NewFakeRunner(t *testing.T) *FakeRunner {
 		cmds:       []string{},
 		t:          t,
 		containers: map[string]string{},
+		images:     map[string]bool{},
 	}
 }
 
@@ -267,6 +272,25 @@ func (f *FakeRunner) dockerRm(args []string) (string, error) {
 	return "", nil
 }
 
+// imageNames returns the sorted names of all fake images
+func (f *FakeRunner) imageNames() []string {
+	names := []string{}
+	for name := range f.images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// removeImage removes a fake image
+func (f *FakeRunner) removeImage(name string) (string, error) {
+	if !f.images[name] {
+		return "", fmt.Errorf("no such image: %s", name)
+	}
+	delete(f.images, name)
+	return "", nil
+}
+
 func (f *FakeRunner) dockerInspect(args []string) (string, error) {
 	if args[1] == "--format" && args[2] == "{{.Id}}" {
 		if args[3] == "missing" {
@@ -303,6 +327,15 @@ func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 	case "inspect":
 		return f.dockerInspect(args)
 
+	case "images":
+		return strings.Join(append(f.imageNames(), "<none>:<none>"), "\n"), nil
+
+	case "rmi":
+		return f.removeImage(args[1])
+
+	case "pull":
+		f.images[args[1]] = true
+
 	case "info":
 
 		if args[1] == "--format" && args[2] == "{{.CgroupDriver}}" {
@@ -400,6 +433,22 @@ func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 			}
 			delete(f.containers, id)
 		}
+	case "images":
+		type image struct {
+			RepoTags []string `json:"repoTags"`
+		}
+		images := struct {
+			Images []image `json:"images"`
+		}{Images: []image{}}
+		for _, name := range f.imageNames() {
+			images.Images = append(images.Images, image{RepoTags: []string{name}})
+		}
+		b, err := json.Marshal(images)
+		return string(b), err
+	case "rmi":
+		return f.removeImage(args[1])
+	case "pull":
+		f.images[args[1]] = true
 	case "rm":
 		for _, id := range args[1:] {
 			f.t.Logf("fake crictl: Removing id %q", id)
@@ -492,6 +541,34 @@ func (f *FakeRunner) which(args []string, root bool) (string, error) { // nolint
 	return path, nil
 }
 
+func TestLogCmd(t *testing.T) {
+	var tests = []struct {
+		name      string
+		o         LogOptions
+		container string
+		system    string
+	}{
+		{"default", LogOptions{}, "docker logs 1a2b", "sudo journalctl -u docker"},
+		{"lines", LogOptions{Lines: 60}, "docker logs --tail 60 1a2b", "sudo journalctl -u docker -n 60"},
+		{"follow", LogOptions{Follow: true}, "docker logs --follow 1a2b", "sudo journalctl -u docker -f"},
+		{"query", LogOptions{Since: 10 * time.Minute, Timestamps: true}, "docker logs --since 600s --timestamps 1a2b", "sudo journalctl -u docker --since=-600s -o short-iso-precise"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := New(Config{Type: "docker"})
+			if err != nil {
+				t.Fatalf("New(docker): %v", err)
+			}
+			if got := r.ContainerLogCmd("1a2b", tc.o); got != tc.container {
+				t.Errorf("ContainerLogCmd(%+v) = %q, want: %q", tc.o, got, tc.container)
+			}
+			if got := r.SystemLogCmd(tc.o); got != tc.system {
+				t.Errorf("SystemLogCmd(%+v) = %q, want: %q", tc.o, got, tc.system)
+			}
+		})
+	}
+}
+
 func TestVersion(t *testing.T) {
 	var tests = []struct {
 		runtime string
@@ -675,3 +752,93 @@ func TestContainerFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildImage(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		want    string
+	}{
+		{"docker", "my-app:latest"},
+		{"crio", "my-app:latest"},
+		{"containerd", "type=image,name=docker.io/library/my-app:latest,unpack=true"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			runner.services = map[string]serviceState{buildkitService: SvcExited}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if err := cr.BuildImage("/var/lib/minikube/build/ctx", "my-app:latest"); err != nil {
+				t.Fatalf("BuildImage: %v", err)
+			}
+
+			found := false
+			for _, arg := range runner.cmds {
+				if arg == tc.want {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("BuildImage ran %v, want an argument %q", runner.cmds, tc.want)
+			}
+			if tc.runtime == "containerd" && runner.services[buildkitService] != SvcRunning {
+				t.Errorf("buildkit is %v, want it running", runner.services[buildkitService])
+			}
+		})
+	}
+}
+
+func TestImageFunctions(t *testing.T) {
+	var tests = []struct {
+		runtime string
+	}{
+		{"docker"},
+		{"crio"},
+		{"containerd"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			runner.images = map[string]bool{
+				"k8s.gcr.io/pause:3.2": true,
+				"busybox:latest":       true,
+			}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+
+			got, err := cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			want := []string{"busybox:latest", "k8s.gcr.io/pause:3.2"}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ListImages() unexpected results, diff (-want +got): %s", diff)
+			}
+
+			if err := cr.PullImage("nginx:alpine"); err != nil {
+				t.Fatalf("PullImage: %v", err)
+			}
+			if err := cr.RemoveImage("busybox:latest"); err != nil {
+				t.Fatalf("RemoveImage: %v", err)
+			}
+			if err := cr.RemoveImage("missing:latest"); err == nil {
+				t.Errorf("RemoveImage(missing) succeeded, expected an error")
+			}
+
+			got, err = cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			want = []string{"k8s.gcr.io/pause:3.2", "nginx:alpine"}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ListImages() unexpected results, diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
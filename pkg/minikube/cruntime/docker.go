@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os/exec"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -155,6 +156,55 @@ func (r *Docker) LoadImage(path string) error {
 
 }
 
+// ListImages returns the names of all images in the runtime
+func (r *Docker) ListImages() ([]string, error) {
+	c := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "docker images")
+	}
+	names := []string{}
+	for _, img := range strings.Split(rr.Stdout.String(), "\n") {
+		img = strings.TrimSpace(img)
+		if img == "" || strings.Contains(img, "<none>") {
+			continue
+		}
+		names = append(names, img)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RemoveImage removes an image by name
+func (r *Docker) RemoveImage(name string) error {
+	glog.Infof("Removing image: %s", name)
+	c := exec.Command("docker", "rmi", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "remove image docker.")
+	}
+	return nil
+}
+
+// PullImage pulls an image from its registry
+func (r *Docker) PullImage(name string) error {
+	glog.Infof("Pulling image: %s", name)
+	c := exec.Command("docker", "pull", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "pull image docker.")
+	}
+	return nil
+}
+
+// BuildImage builds an image from a build context directory, tagging it with the given name
+func (r *Docker) BuildImage(src string, tag string) error {
+	glog.Infof("Building image from: %s", src)
+	c := exec.Command("docker", "build", "-t", tag, src)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "build image docker.")
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Docker) CGroupDriver() (string, error) {
 	// Note: the server daemon has to be running, for this call to return successfully
@@ -191,7 +241,12 @@ func (r *Docker) ListContainers(o ListOptions) ([]string, error) {
 		nameFilter = fmt.Sprintf("%s.*_(%s)_", nameFilter, strings.Join(o.Namespaces, "|"))
 	}
 
-	args = append(args, fmt.Sprintf("--filter=name=%s", nameFilter), "--format={{.ID}}")
+	args = append(args, fmt.Sprintf("--filter=name=%s", nameFilter))
+	if o.PodName != "" {
+		args = append(args, fmt.Sprintf("--filter=label=io.kubernetes.pod.name=%s", o.PodName))
+	}
+
+	args = append(args, "--format={{.ID}}")
 	rr, err := r.Runner.RunCmd(exec.Command("docker", args...))
 	if err != nil {
 		return nil, errors.Wrapf(err, "docker")
@@ -262,23 +317,13 @@ func (r *Docker) UnpauseContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
-	var cmd strings.Builder
-	cmd.WriteString("docker logs ")
-	if len > 0 {
-		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
-	}
-	if follow {
-		cmd.WriteString("--follow ")
-	}
-
-	cmd.WriteString(id)
-	return cmd.String()
+func (r *Docker) ContainerLogCmd(id string, o LogOptions) string {
+	return "docker logs " + containerLogFlags(o) + id
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Docker) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u docker -n %d", len)
+func (r *Docker) SystemLogCmd(o LogOptions) string {
+	return journalLogCmd("docker", o)
 }
 
 // ForceSystemd forces the docker daemon to use systemd as cgroup manager
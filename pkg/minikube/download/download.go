@@ -26,6 +26,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/hashicorp/go-getter"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/out"
 )
 
 var (
@@ -40,12 +41,16 @@ func EnableMock(b bool) {
 // download is a well-configured atomic download function
 func download(src string, dst string) error {
 	tmpDst := dst + ".download"
+	var progress getter.ProgressTracker = DefaultProgressBar
+	if out.JSON {
+		progress = DefaultJSONOutput
+	}
 	client := &getter.Client{
 		Src:     src,
 		Dst:     tmpDst,
 		Dir:     false,
 		Mode:    getter.ClientModeFile,
-		Options: []getter.ClientOption{getter.WithProgress(DefaultProgressBar)},
+		Options: []getter.ClientOption{getter.WithProgress(progress)},
 		Getters: map[string]getter.Getter{
 			"file":  &getter.FileGetter{Copy: false},
 			"http":  &getter.HttpGetter{Netrc: false},
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"io"
+	"path/filepath"
+
+	"k8s.io/minikube/pkg/minikube/out/register"
+)
+
+// DefaultJSONOutput is the progress tracker used when output is JSON
+var DefaultJSONOutput = &jsonOutput{}
+
+type jsonOutput struct{}
+
+// TrackProgress prints a download event, followed by a download progress event each time another
+// percent of the artifact has been read, until closed.
+func (*jsonOutput) TrackProgress(src string, currentSize, totalSize int64, stream io.ReadCloser) io.ReadCloser {
+	artifact := filepath.Base(src)
+	register.PrintDownload(artifact)
+	return &jsonReader{
+		ReadCloser: stream,
+		artifact:   artifact,
+		current:    currentSize,
+		total:      totalSize,
+		last:       -1,
+	}
+}
+
+type jsonReader struct {
+	io.ReadCloser
+	artifact string
+	current  int64
+	total    int64
+	// last is the last percentage printed
+	last int64
+}
+
+func (r *jsonReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.current += int64(n)
+	if r.total > 0 {
+		if pct := r.current * 100 / r.total; pct > r.last {
+			r.last = pct
+			register.PrintDownloadProgress(r.artifact, r.current, r.total)
+		}
+	}
+	return n, err
+}
+
+func (r *jsonReader) Close() error {
+	if r.total <= 0 {
+		register.PrintDownloadProgress(r.artifact, r.current, r.total)
+	}
+	return r.ReadCloser.Close()
+}
@@ -27,7 +27,7 @@ import (
 // ControlPlaneEndpoint returns the location where callers can reach this cluster
 func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
 	if NeedsPortForward(driverName) {
-		port, err := oci.ForwardedPort(cc.Driver, cc.Name, cp.Port)
+		port, err := oci.ForwardedPort(cc.Driver, MachineName(*cc, *cp), cp.Port)
 		hostname := oci.DefaultBindIPV4
 		ip := net.ParseIP(hostname)
 
@@ -45,3 +45,18 @@ func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName
 	}
 	return hostname, net.ParseIP(cp.IP), cp.Port, nil
 }
+
+// ClusterEndpoint returns the location where clients outside of the cluster should reach its API server: the virtual IP
+// in front of the control planes of a highly-available cluster, or else the primary control plane.
+func ClusterEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
+	// the virtual IP only exists within the network of the nodes, which is not reachable when ports are forwarded
+	if !config.IsHA(*cc) || NeedsPortForward(driverName) {
+		return ControlPlaneEndpoint(cc, cp, driverName)
+	}
+
+	hostname := cc.KubernetesConfig.APIServerHAVIP
+	if cc.KubernetesConfig.APIServerName != constants.APIServerName {
+		hostname = cc.KubernetesConfig.APIServerName
+	}
+	return hostname, net.ParseIP(cc.KubernetesConfig.APIServerHAVIP), cp.Port, nil
+}
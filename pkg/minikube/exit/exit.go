@@ -18,12 +18,15 @@ limitations under the License.
 package exit
 
 import (
+	"fmt"
 	"os"
 	"runtime"
 	"runtime/debug"
+	"strings"
 
 	"github.com/golang/glog"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/problem"
 )
 
@@ -41,14 +44,25 @@ const (
 	Permissions = 77 // Permissions represents a permissions error
 )
 
+// Triage, if set, diagnoses the known problems that minikube exits with, once their advice is displayed
+var Triage func(*problem.Problem)
+
 // UsageT outputs a templated usage error and exits with error code 64
 func UsageT(format string, a ...out.V) {
+	if out.JSON {
+		register.PrintErrorExitCode(out.Message(format, a...), BadUsage)
+		os.Exit(BadUsage)
+	}
 	out.ErrT(out.Usage, format, a...)
 	os.Exit(BadUsage)
 }
 
 // WithCodeT outputs a templated fatal error message and exits with the supplied error code.
 func WithCodeT(code int, format string, a ...out.V) {
+	if out.JSON {
+		register.PrintErrorExitCode(out.Message(format, a...), code)
+		os.Exit(code)
+	}
 	out.FatalT(format, a...)
 	os.Exit(code)
 }
@@ -60,15 +74,35 @@ func WithError(msg string, err error) {
 	if p != nil {
 		WithProblem(msg, err, p)
 	}
+	if out.JSON {
+		register.PrintErrorExitCode(fmt.Sprintf("%s: %v", out.Message(msg), err), Software)
+		os.Exit(Software)
+	}
 	out.DisplayError(msg, err)
 	os.Exit(Software)
 }
 
 // WithProblem outputs info related to a known problem and exits.
 func WithProblem(msg string, err error, p *problem.Problem) {
+	if out.JSON {
+		issues := []string{}
+		for _, i := range p.Issues {
+			issues = append(issues, p.IssueURL(i))
+		}
+		register.PrintErrorExitCode(fmt.Sprintf("%s %v", out.Message(msg), p.Err), Config, map[string]string{
+			"name":   p.ID,
+			"advice": p.Advice,
+			"url":    p.URL,
+			"issues": strings.Join(issues, ","),
+		})
+		os.Exit(Config)
+	}
 	out.ErrT(out.Empty, "")
 	out.FailureT("[{{.id}}] {{.msg}} {{.error}}", out.V{"msg": msg, "id": p.ID, "error": p.Err})
 	p.Display()
+	if Triage != nil {
+		Triage(p)
+	}
 	if p.ShowIssueLink {
 		out.ErrT(out.Empty, "")
 		out.ErrT(out.Sad, "If the above advice does not help, please let us know: ")
@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package forward exposes the socket of the container runtime of a node on the host, over SSH
+package forward
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/mitchellh/go-ps"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+// Command is the hidden minikube command that runs the forwarder of a cluster
+const Command = "socket-forward"
+
+// startTimeout is how long a forwarder has to listen, once started
+const startTimeout = 10 * time.Second
+
+// SocketPath returns the path of the socket on the host that is forwarded to the container runtime of a cluster
+func SocketPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "runtime.sock")
+}
+
+// pidPath returns the path of the file holding the pid of the forwarder of a cluster
+func pidPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), constants.SocketForwardProcessFileName)
+}
+
+// Start starts the forwarder of a cluster to a socket within its node in the background, and waits for it to listen.
+// A forwarder that is already listening is kept, so that the shells using it are not interrupted.
+func Start(profile string, remote string) error {
+	path := SocketPath(profile)
+	if _, ok := Running(profile); ok {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+	}
+
+	if err := Stop(profile); err != nil {
+		return errors.Wrap(err, "stop")
+	}
+
+	c := exec.Command(os.Args[0], Command, fmt.Sprintf("--profile=%s", profile), fmt.Sprintf("--socket=%s", remote))
+	c.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
+	if err := c.Start(); err != nil {
+		return errors.Wrap(err, "start forwarder")
+	}
+	glog.Infof("started socket forwarder for %q: pid %d", profile, c.Process.Pid)
+	if err := lock.WriteFile(pidPath(profile), []byte(strconv.Itoa(c.Process.Pid)), 0644); err != nil {
+		return errors.Wrap(err, "write pid file")
+	}
+
+	for start := time.Now(); time.Since(start) < startTimeout; time.Sleep(100 * time.Millisecond) {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("the forwarder did not listen on %s within %s", path, startTimeout)
+}
+
+// Running returns the pid of the forwarder of a cluster, if it is running
+func Running(profile string) (int, bool) {
+	b, err := ioutil.ReadFile(pidPath(profile))
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, false
+	}
+	p, err := ps.FindProcess(pid)
+	if err != nil || p == nil {
+		return 0, false
+	}
+	return pid, true
+}
+
+// Stop kills the forwarder of a cluster, if it is running
+func Stop(profile string) error {
+	if pid, ok := Running(profile); ok && pid != os.Getpid() {
+		glog.Infof("killing socket forwarder for %q: pid %d", profile, pid)
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			return errors.Wrap(err, "find process")
+		}
+		if err := p.Kill(); err != nil {
+			return errors.Wrapf(err, "kill %d", pid)
+		}
+	}
+	return Done(profile)
+}
+
+// Done forgets the forwarder of a cluster, and removes its socket
+func Done(profile string) error {
+	for _, p := range []string{pidPath(profile), SocketPath(profile)} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "remove %s", p)
+		}
+	}
+	return nil
+}
+
+// Serve relays the connections accepted by a listener, until it is closed
+func Serve(l net.Listener, relay func(net.Conn) error) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return errors.Wrap(err, "accept")
+		}
+		go func() {
+			defer conn.Close()
+			if err := relay(conn); err != nil {
+				glog.Warningf("relay: %v", err)
+			}
+		}()
+	}
+}
+
+// SSHRelay returns a relay of connections to a Unix socket within a node, through socat over an SSH connection to it,
+// as the sockets of container runtimes are only accessible to root
+func SSHRelay(client *ssh.Client, remote string) func(net.Conn) error {
+	return func(conn net.Conn) error {
+		s, err := client.NewSession()
+		if err != nil {
+			return errors.Wrap(err, "new session")
+		}
+		defer s.Close()
+
+		in, err := s.StdinPipe()
+		if err != nil {
+			return errors.Wrap(err, "stdin")
+		}
+		var stderr bytes.Buffer
+		s.Stdout = conn
+		s.Stderr = &stderr
+		if err := s.Start(fmt.Sprintf("sudo socat STDIO UNIX-CONNECT:%s", remote)); err != nil {
+			return errors.Wrap(err, "start socat")
+		}
+
+		// stdin is not waited for, as the connection is only closed once socat exits
+		go func() {
+			if _, err := io.Copy(in, conn); err != nil {
+				glog.Infof("relay to %s: %v", remote, err)
+			}
+			in.Close()
+		}()
+		if err := s.Wait(); err != nil {
+			return errors.Wrapf(err, "socat: %s", stderr.String())
+		}
+		return nil
+	}
+}
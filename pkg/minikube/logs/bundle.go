@@ -0,0 +1,359 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/version"
+)
+
+// bundleLines is how many lines of each log are bundled
+const bundleLines = 10000
+
+// maxGlogFiles is how many of the most recent minikube log files of the host are bundled
+const maxGlogFiles = 10
+
+// redacted replaces secrets in the bundled configuration
+const redacted = "<redacted>"
+
+// secretKeyRe matches the names of settings whose values are secrets
+var secretKeyRe = regexp.MustCompile(`(?i)secret|token|passw|key|cred|auth`)
+
+// fileNameRe matches the characters of a log source name that are replaced in its file name
+var fileNameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// urlPasswordRe matches the password in the user info of a URL
+var urlPasswordRe = regexp.MustCompile(`(://[^:/@\s]+):[^@/\s]+@`)
+
+// BundleConfig describes the cluster to collect a support bundle for
+type BundleConfig struct {
+	API     libmachine.API
+	Cluster config.ClusterConfig
+	// Bootstrapper returns the bootstrapper of a running node
+	Bootstrapper func(command.Runner) (bootstrapper.Bootstrapper, error)
+	// Dir is the directory the bundle is written to
+	Dir string
+}
+
+// WriteBundle collects the logs and state of every node of a cluster, its configuration, and the state of the host
+// into a timestamped tar.gz, and returns its path. Sources that fail are recorded in the index of the bundle rather
+// than failing it, so that a bundle can be collected from a broken cluster.
+func WriteBundle(bc BundleConfig) (string, error) {
+	now := time.Now()
+	name := fmt.Sprintf("minikube-logs-%s-%s", bc.Cluster.Name, now.Format("20060102-150405"))
+	p := filepath.Join(bc.Dir, name+".tar.gz")
+	b, err := newBundle(p, name, fmt.Sprintf("minikube %s support bundle of %q, collected at %s", version.GetVersion(), bc.Cluster.Name, now.Format(time.RFC3339)))
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := json.MarshalIndent(redactConfig(bc.Cluster), "", "    ")
+	b.add("config.json", "profile configuration, with secrets redacted", cfg, err)
+
+	hi, err := machine.HostInfo()
+	b.add("host/info.txt", "host operating system and resources", []byte(hi), err)
+	if _, err := os.Stat("/etc/os-release"); err == nil {
+		b.addFile("host/os-release", "/etc/os-release")
+	}
+	if driver.IsKIC(bc.Cluster.Driver) {
+		var info []byte
+		si, err := oci.DaemonInfo(bc.Cluster.Driver)
+		if err == nil {
+			info, err = json.MarshalIndent(si, "", "    ")
+		}
+		b.add("host/oci-info.json", fmt.Sprintf("%s daemon info", bc.Cluster.Driver), info, err)
+	}
+	for _, f := range glogFiles() {
+		b.addFile(path.Join("host", "logs", filepath.Base(f)), f)
+	}
+
+	for _, n := range bc.Cluster.Nodes {
+		bundleNode(b, bc, n)
+	}
+
+	if err := b.close(); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// bundleNode adds the logs and state of a node, and of the cluster if it is the primary control plane
+func bundleNode(b *bundle, bc BundleConfig, n config.Node) {
+	cc := bc.Cluster
+	machineName := driver.MachineName(cc, n)
+	dir := path.Join("nodes", machineName)
+
+	st, err := machine.Status(bc.API, machineName)
+	b.add(path.Join(dir, "status.txt"), "host state", []byte(st+"\n"), err)
+	if err != nil || st != state.Running.String() {
+		return
+	}
+
+	h, err := machine.LoadHost(bc.API, machineName)
+	if err != nil {
+		b.add(path.Join(dir, "logs"), "load host", nil, err)
+		return
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		b.add(path.Join(dir, "logs"), "command runner", nil, err)
+		return
+	}
+	r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		b.add(path.Join(dir, "logs"), "container runtime", nil, err)
+		return
+	}
+	bs, err := bc.Bootstrapper(runner)
+	if err != nil {
+		b.add(path.Join(dir, "logs"), "bootstrapper", nil, err)
+		return
+	}
+
+	cmds := logCommands(r, bs, cc, bundleLines)
+	cmds["kernel"] = "uptime && uname -a && grep PRETTY /etc/os-release"
+	cmds["runtime info"] = runtimeInfoCmd(r)
+	if config.IsPrimaryControlPlane(cc, n) {
+		kubectl := fmt.Sprintf("sudo %s --kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "binaries", cc.KubernetesConfig.KubernetesVersion, "kubectl"), path.Join(vmpath.GuestPersistentDir, "kubeconfig"))
+		cmds["get all"] = kubectl + " get all --all-namespaces -o wide"
+		cmds["events"] = kubectl + " get events --all-namespaces --sort-by=.lastTimestamp"
+	}
+
+	names := []string{}
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.addCommand(path.Join(dir, fileName(name)+".log"), runner, cmds[name])
+	}
+}
+
+// runtimeInfoCmd returns the command that describes the configuration of a container runtime
+func runtimeInfoCmd(r cruntime.Manager) string {
+	if r.Name() == "Docker" {
+		return "docker info"
+	}
+	return "sudo `which crictl || echo crictl` info"
+}
+
+// glogFiles returns the most recent minikube log files of the host
+func glogFiles() []string {
+	dir := os.TempDir()
+	if f := flag.Lookup("log_dir"); f != nil && f.Value.String() != "" {
+		dir = f.Value.String()
+	}
+
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		glog.Warningf("read log dir: %v", err)
+		return nil
+	}
+	logs := []os.FileInfo{}
+	for _, fi := range fis {
+		if fi.Mode().IsRegular() && strings.HasPrefix(fi.Name(), "minikube.") && strings.Contains(fi.Name(), ".log.") {
+			logs = append(logs, fi)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].ModTime().After(logs[j].ModTime()) })
+	if len(logs) > maxGlogFiles {
+		logs = logs[:maxGlogFiles]
+	}
+
+	files := []string{}
+	for _, fi := range logs {
+		files = append(files, filepath.Join(dir, fi.Name()))
+	}
+	return files
+}
+
+// fileName turns the name of a log source, such as "kube-apiserver [0a1b2c]", into a file name
+func fileName(name string) string {
+	f := strings.Trim(fileNameRe.ReplaceAllString(name, "_"), "_")
+	if f == "" {
+		return "log"
+	}
+	return f
+}
+
+// redactConfig returns a copy of a cluster configuration without the secrets it may hold:
+// credentials in URLs, and the values of settings named like secrets
+func redactConfig(cc config.ClusterConfig) config.ClusterConfig {
+	redactURL := func(s string) string {
+		return urlPasswordRe.ReplaceAllString(s, "$1:"+redacted+"@")
+	}
+	redactKV := func(kvs []string) []string {
+		out := []string{}
+		for _, kv := range kvs {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) == 2 && secretKeyRe.MatchString(parts[0]) {
+				kv = parts[0] + "=" + redacted
+			}
+			out = append(out, redactURL(kv))
+		}
+		return out
+	}
+
+	cc.DockerEnv = redactKV(cc.DockerEnv)
+	cc.DockerOpt = redactKV(cc.DockerOpt)
+	cc.RegistryMirror = redactKV(cc.RegistryMirror)
+	cc.InsecureRegistry = redactKV(cc.InsecureRegistry)
+
+	if cc.AddonValues != nil {
+		values := map[string]map[string]string{}
+		for addon, settings := range cc.AddonValues {
+			values[addon] = map[string]string{}
+			for k, v := range settings {
+				if secretKeyRe.MatchString(k) {
+					v = redacted
+				}
+				values[addon][k] = redactURL(v)
+			}
+		}
+		cc.AddonValues = values
+	}
+
+	opts := config.ExtraOptionSlice{}
+	for _, o := range cc.KubernetesConfig.ExtraOptions {
+		if secretKeyRe.MatchString(o.Key) {
+			o.Value = redacted
+		}
+		o.Value = redactURL(o.Value)
+		opts = append(opts, o)
+	}
+	cc.KubernetesConfig.ExtraOptions = opts
+	return cc
+}
+
+// bundle is a tar.gz of files, with an index describing where each came from
+type bundle struct {
+	f      *os.File
+	gz     *gzip.Writer
+	tw     *tar.Writer
+	prefix string
+	header string
+	index  [][3]string
+}
+
+func newBundle(p string, prefix string, header string) (*bundle, error) {
+	f, err := os.Create(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "create bundle")
+	}
+	gz := gzip.NewWriter(f)
+	return &bundle{f: f, gz: gz, tw: tar.NewWriter(gz), prefix: prefix, header: header}, nil
+}
+
+// add adds a file to the bundle. If collecting it failed, the error is recorded in the index,
+// along with whatever output there was.
+func (b *bundle) add(name string, source string, data []byte, err error) {
+	status := "ok"
+	if err != nil {
+		glog.Warningf("bundle %s: %v", name, err)
+		status = fmt.Sprintf("failed: %v", strings.Replace(err.Error(), "\n", " ", -1))
+	}
+	b.index = append(b.index, [3]string{name, source, status})
+	if len(data) == 0 {
+		return
+	}
+	if err := b.write(name, data); err != nil {
+		glog.Warningf("bundle %s: %v", name, err)
+	}
+}
+
+// addFile adds a file of the host to the bundle
+func (b *bundle) addFile(name string, src string) {
+	data, err := ioutil.ReadFile(src)
+	b.add(name, src, data, err)
+}
+
+// addCommand adds the output of a command run within a node to the bundle
+func (b *bundle) addCommand(name string, r logRunner, cmd string) {
+	var out bytes.Buffer
+	c := exec.Command("/bin/bash", "-c", cmd)
+	c.Stdout = &out
+	c.Stderr = &out
+	_, err := r.RunCmd(c)
+	b.add(name, cmd, out.Bytes(), err)
+}
+
+func (b *bundle) write(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    path.Join(b.prefix, name),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := b.tw.Write(data)
+	return err
+}
+
+// close writes the index, and closes the bundle
+func (b *bundle) close() error {
+	var index bytes.Buffer
+	index.WriteString(b.header + "\n\n")
+	w := tabwriter.NewWriter(&index, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tSOURCE\tSTATUS")
+	for _, e := range b.index {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e[0], e[1], e[2])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := b.write("index.txt", index.Bytes()); err != nil {
+		return errors.Wrap(err, "write index")
+	}
+
+	if err := b.tw.Close(); err != nil {
+		return errors.Wrap(err, "close tar")
+	}
+	if err := b.gz.Close(); err != nil {
+		return errors.Wrap(err, "close gzip")
+	}
+	return b.f.Close()
+}
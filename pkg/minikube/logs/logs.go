@@ -21,14 +21,13 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
-	"os"
 	"os/exec"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
-	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -87,19 +86,43 @@ type logRunner interface {
 // include usage messages from a failed binary, but small enough to not include irrelevant problems.
 const lookBackwardsCount = 400
 
-// Follow follows logs from multiple files in tail(1) format
-func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner) error {
-	cs := []string{}
-	for _, v := range logCommands(r, bs, cfg, 0, true) {
-		cs = append(cs, v+" &")
+// Follow follows logs from multiple sources at once, prefixing every line with the name of its log
+func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner, q Query) error {
+	p := &printer{q: q, now: time.Now(), prefix: true}
+	srcs := []source{}
+	for _, s := range logSources(r, bs, cfg, q, true) {
+		// the other logs are snapshots of the state of the node, which do not grow
+		if s.format != plainFormat {
+			srcs = append(srcs, s)
+		}
 	}
-	cs = append(cs, "wait")
 
-	cmd := exec.Command("/bin/bash", "-c", strings.Join(cs, " "))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stdout
-	if _, err := cr.RunCmd(cmd); err != nil {
-		return errors.Wrapf(err, "log follow")
+	failed := make(chan string, len(srcs))
+	for _, s := range srcs {
+		go func(s source) {
+			w := newLogWriter(p, s)
+			c := exec.Command("/bin/bash", "-c", s.cmd)
+			c.Stdout = w
+			c.Stderr = w
+			rr, err := cr.RunCmd(c)
+			w.flush()
+			if err != nil {
+				glog.Errorf("command %s failed with error: %v", rr.Command(), err)
+				failed <- s.name
+				return
+			}
+			failed <- ""
+		}(s)
+	}
+
+	names := []string{}
+	for range srcs {
+		if name := <-failed; name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("unable to follow logs for: %s", strings.Join(names, ", "))
 	}
 	return nil
 }
@@ -112,7 +135,7 @@ func IsProblem(line string) bool {
 // FindProblems finds possible root causes among the logs
 func FindProblems(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner) map[string][]string {
 	pMap := map[string][]string{}
-	cmds := logCommands(r, bs, cfg, lookBackwardsCount, false)
+	cmds := logCommands(r, bs, cfg, lookBackwardsCount)
 	for name := range cmds {
 		glog.Infof("Gathering logs for %s ...", name)
 		var b bytes.Buffer
@@ -153,36 +176,37 @@ func OutputProblems(problems map[string][]string, maxLines int) {
 	}
 }
 
-// Output displays logs from multiple sources in tail(1) format
-func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, runner command.Runner, lines int) error {
-	cmds := logCommands(r, bs, cfg, lines, false)
-	cmds["kernel"] = "uptime && uname -a && grep PRETTY /etc/os-release"
-
-	names := []string{}
-	for k := range cmds {
-		names = append(names, k)
+// Output displays logs from multiple sources in tail(1) format, or as JSON
+func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, runner command.Runner, q Query) error {
+	srcs := logSources(r, bs, cfg, q, false)
+	kernel := source{name: "kernel", component: "kernel", cmd: "uptime && uname -a && grep PRETTY /etc/os-release"}
+	if q.selects(kernel) {
+		srcs = append(srcs, kernel)
+		sort.Slice(srcs, func(i, j int) bool { return srcs[i].name < srcs[j].name })
 	}
 
-	sort.Strings(names)
+	p := &printer{q: q, now: time.Now()}
 	failed := []string{}
-	for i, name := range names {
-		if i > 0 {
-			out.T(out.Empty, "")
-		}
-		out.T(out.Empty, "==> {{.name}} <==", out.V{"name": name})
+	for _, s := range srcs {
 		var b bytes.Buffer
-		c := exec.Command("/bin/bash", "-c", cmds[name])
+		c := exec.Command("/bin/bash", "-c", s.cmd)
 		c.Stdout = &b
 		c.Stderr = &b
 		if rr, err := runner.RunCmd(c); err != nil {
 			glog.Errorf("command %s failed with error: %v output: %q", rr.Command(), err, rr.Output())
-			failed = append(failed, name)
+			failed = append(failed, s.name)
 			continue
 		}
-		scanner := bufio.NewScanner(&b)
-		for scanner.Scan() {
-			out.T(out.Empty, scanner.Text())
+
+		// Without a query, every log is headed, even if it is empty
+		if !q.structured() {
+			p.head(s.name)
 		}
+		w := newLogWriter(p, s)
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+		w.flush()
 	}
 
 	if len(failed) > 0 {
@@ -191,27 +215,81 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.Cluster
 	return nil
 }
 
-// logCommands returns a list of commands that would be run to receive the anticipated logs
-func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, length int, follow bool) map[string]string {
-	cmds := bs.LogCommands(cfg, bootstrapper.LogOptions{Lines: length, Follow: follow})
+// source is a log, and the command that retrieves it
+type source struct {
+	// name is the name of the log, such as "kube-apiserver [1a2b3c]"
+	name string
+	// component is what the log is of, such as kubelet or kube-apiserver
+	component string
+	format    logFormat
+	cmd       string
+}
+
+// bootstrapperFormats are the formats of the bootstrapper logs that have timestamps
+var bootstrapperFormats = map[string]logFormat{
+	"kubelet": journalFormat,
+	"dmesg":   dmesgFormat,
+}
+
+// logSources returns the logs selected by a query, sorted by name
+func logSources(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, q Query, follow bool) []source {
+	srcs := []source{}
+	add := func(s source) {
+		if q.selects(s) {
+			srcs = append(srcs, s)
+		}
+	}
+
+	bo := bootstrapper.LogOptions{Lines: q.Lines, Follow: follow, Since: q.Since, Timestamps: q.structured()}
+	for name, cmd := range bs.LogCommands(cfg, bo) {
+		add(source{name: name, component: name, format: bootstrapperFormats[name], cmd: cmd})
+	}
+
+	co := cruntime.LogOptions{Lines: q.Lines, Follow: follow, Since: q.Since, Timestamps: q.structured()}
 	for _, pod := range importantPods {
-		ids, err := r.ListContainers(cruntime.ListOptions{Name: pod})
-		if err != nil {
-			glog.Errorf("Failed to list containers for %q: %v", pod, err)
+		if !q.selects(source{name: pod, component: pod}) {
 			continue
 		}
-		glog.Infof("%d containers: %s", len(ids), ids)
+		for _, id := range containerIDs(r, cruntime.ListOptions{Name: pod}) {
+			srcs = append(srcs, source{name: fmt.Sprintf("%s [%s]", pod, id), component: pod, format: containerFormat, cmd: r.ContainerLogCmd(id, co)})
+		}
+	}
+	for _, pod := range q.Pods {
+		ids := containerIDs(r, cruntime.ListOptions{PodName: pod})
 		if len(ids) == 0 {
-			glog.Warningf("No container was found matching %q", pod)
-			continue
+			out.WarningT("No container was found in pod {{.pod}}", out.V{"pod": pod})
 		}
-		for _, i := range ids {
-			key := fmt.Sprintf("%s [%s]", pod, i)
-			cmds[key] = r.ContainerLogCmd(i, length, follow)
+		for _, id := range ids {
+			srcs = append(srcs, source{name: fmt.Sprintf("%s [%s]", pod, id), component: pod, format: containerFormat, cmd: r.ContainerLogCmd(id, co)})
 		}
 	}
-	cmds[r.Name()] = r.SystemLogCmd(length)
-	cmds["container status"] = cruntime.ContainerStatusCommand()
 
+	add(source{name: r.Name(), component: "runtime", format: journalFormat, cmd: r.SystemLogCmd(co)})
+	add(source{name: "container status", component: "container status", cmd: cruntime.ContainerStatusCommand()})
+
+	sort.Slice(srcs, func(i, j int) bool { return srcs[i].name < srcs[j].name })
+	return srcs
+}
+
+// containerIDs returns the IDs of the containers matching the options
+func containerIDs(r cruntime.Manager, o cruntime.ListOptions) []string {
+	ids, err := r.ListContainers(o)
+	if err != nil {
+		glog.Errorf("Failed to list containers for %+v: %v", o, err)
+		return nil
+	}
+	glog.Infof("%d containers: %s", len(ids), ids)
+	if len(ids) == 0 {
+		glog.Warningf("No container was found matching %+v", o)
+	}
+	return ids
+}
+
+// logCommands returns the commands that would be run to receive the anticipated logs, by name
+func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, length int) map[string]string {
+	cmds := map[string]string{}
+	for _, s := range logSources(r, bs, cfg, Query{Lines: length}, false) {
+		cmds[s.name] = s.cmd
+	}
 	return cmds
 }
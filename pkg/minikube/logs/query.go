@@ -0,0 +1,330 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// levels are the severities of log entries, from the lowest
+var levels = []string{"debug", "info", "warning", "error", "fatal"}
+
+// levelNames maps the severities of klog, logrus, zap, coredns and the kernel to levels
+var levelNames = map[string]string{
+	"i":       "info",
+	"w":       "warning",
+	"e":       "error",
+	"f":       "fatal",
+	"trace":   "debug",
+	"debug":   "debug",
+	"info":    "info",
+	"notice":  "info",
+	"warn":    "warning",
+	"warning": "warning",
+	"err":     "error",
+	"error":   "error",
+	"crit":    "fatal",
+	"alert":   "fatal",
+	"emerg":   "fatal",
+	"fatal":   "fatal",
+	"panic":   "fatal",
+	"dpanic":  "fatal",
+}
+
+var (
+	// 2020-06-01T10:00:00.123456+0000 minikube kubelet[1234]: message
+	journalRe = regexp.MustCompile(`^(\d{4}-\d\d-\d\dT\S+) \S+ [^:]+: (.*)$`)
+	// 2020-06-01T10:00:00.123456789Z message
+	containerRe = regexp.MustCompile(`^(\d{4}-\d\d-\d\dT\S+) (.*)$`)
+	// kern  :warn  : 2020-06-01T10:00:00,123456+00:00 message
+	dmesgRe = regexp.MustCompile(`^\w+\s*:(\w+)\s*: (\d{4}-\d\d-\d\dT\S+) (.*)$`)
+	// I0601 10:00:00.123456    1234 server.go:416] message
+	klogRe = regexp.MustCompile(`^([IWEF])\d{4} \d\d:\d\d:\d\d`)
+	// level=info msg="message", {"level":"warn","msg":"message"} or [INFO] message
+	levelRe = regexp.MustCompile(`(?:\blevel"?[=:]"?|^\[)([A-Za-z]+)`)
+)
+
+// timeLayouts are the layouts of the timestamps of logs
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999-0700"}
+
+// logFormat is how the timestamps of the lines of a log are written
+type logFormat int
+
+const (
+	// plainFormat lines have no timestamp
+	plainFormat logFormat = iota
+	// journalFormat lines are written by journalctl -o short-iso-precise
+	journalFormat
+	// containerFormat lines are written by docker or crictl logs --timestamps
+	containerFormat
+	// dmesgFormat lines are written by dmesg -x --time-format iso
+	dmesgFormat
+)
+
+// Query selects the log entries to output, and how
+type Query struct {
+	// Lines is the number of recent lines of each log to include, or 0 for all of them.
+	Lines int
+	// Since excludes the entries older than this, if set.
+	Since time.Duration
+	// Components are the logs to include, such as kubelet, apiserver or runtime.
+	// If neither Components nor Pods are set, every log is included.
+	Components []string
+	// Pods are the pods whose container logs are included
+	Pods []string
+	// Level is the lowest severity of the entries to include, such as warning.
+	// Entries without a severity are excluded if it is set.
+	Level string
+	// Node is the name of the node the logs are of
+	Node string
+	// JSON outputs every entry as a JSON object, rather than as text
+	JSON bool
+}
+
+// Entry is a line of a log
+type Entry struct {
+	Source    string     `json:"source"`
+	Node      string     `json:"node"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Level     string     `json:"level,omitempty"`
+	Message   string     `json:"message"`
+}
+
+// ParseLevel returns the severity named by a level of klog, logrus or the kernel, such as W or warn
+func ParseLevel(name string) (string, error) {
+	if l, ok := levelNames[strings.ToLower(name)]; ok {
+		return l, nil
+	}
+	return "", fmt.Errorf("unknown level %q, valid levels are: %s", name, strings.Join(levels, ", "))
+}
+
+// severity returns the rank of a level, or -1 if it is unknown
+func severity(level string) int {
+	for i, l := range levels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// structured returns whether the lines of logs are parsed into entries
+func (q Query) structured() bool {
+	return q.JSON || q.Level != "" || q.Since > 0
+}
+
+// selects returns whether the query includes a log of a component
+func (q Query) selects(s source) bool {
+	if len(q.Components) == 0 && len(q.Pods) == 0 {
+		return true
+	}
+	for _, c := range q.Components {
+		c = strings.ToLower(c)
+		if c == s.component || "kube-"+c == s.component || c == strings.ToLower(s.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns whether the query includes an entry, which is of a log read at a time
+func (q Query) matches(e Entry, now time.Time) bool {
+	if q.Level != "" && severity(e.Level) < severity(q.Level) {
+		return false
+	}
+	if q.Since > 0 && e.Timestamp != nil && e.Timestamp.Before(now.Add(-q.Since)) {
+		return false
+	}
+	return true
+}
+
+// parser parses the lines of a log into entries. Lines without a timestamp,
+// such as those of stack traces, continue the entry of the line before them.
+type parser struct {
+	source string
+	node   string
+	format logFormat
+	last   Entry
+}
+
+// parse returns the entry of a line
+func (p *parser) parse(line string) Entry {
+	e := Entry{Source: p.source, Node: p.node, Message: line}
+	if p.format == plainFormat {
+		return e
+	}
+
+	ts := ""
+	switch p.format {
+	case journalFormat:
+		if m := journalRe.FindStringSubmatch(line); m != nil {
+			ts, e.Message = m[1], m[2]
+		}
+	case containerFormat:
+		if m := containerRe.FindStringSubmatch(line); m != nil {
+			ts, e.Message = m[1], m[2]
+		}
+	case dmesgFormat:
+		if m := dmesgRe.FindStringSubmatch(line); m != nil {
+			ts, e.Message, e.Level = m[2], m[3], levelNames[m[1]]
+		}
+	}
+
+	if ts == "" {
+		e.Timestamp, e.Level = p.last.Timestamp, p.last.Level
+		return e
+	}
+
+	e.Timestamp = parseTime(ts)
+	if e.Level == "" {
+		e.Level = messageLevel(e.Message)
+	}
+	p.last = e
+	return e
+}
+
+// parseTime returns the time of a timestamp of a log, or nil if it can not be parsed
+func parseTime(ts string) *time.Time {
+	// dmesg separates the fraction of a second with a comma
+	ts = strings.Replace(ts, ",", ".", 1)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, ts); err == nil {
+			return &t
+		}
+	}
+	glog.Warningf("unable to parse log timestamp %q", ts)
+	return nil
+}
+
+// messageLevel returns the level of a message, from the prefix of klog or the fields of logrus and zap
+func messageLevel(msg string) string {
+	if m := klogRe.FindStringSubmatch(msg); m != nil {
+		return levelNames[strings.ToLower(m[1])]
+	}
+	if m := levelRe.FindStringSubmatch(msg); m != nil {
+		return levelNames[strings.ToLower(m[1])]
+	}
+	return ""
+}
+
+// printer outputs the lines of logs, which may be written from several goroutines
+type printer struct {
+	sync.Mutex
+	q Query
+	// now is when the logs are read
+	now time.Time
+	// prefix prefixes every line of text with the name of its log, rather than heading the log with it
+	prefix bool
+	// headed is the log that was last headed
+	headed string
+}
+
+// head writes the heading of a log, unless it was the last one headed
+func (p *printer) head(name string) {
+	if p.headed == name {
+		return
+	}
+	if p.headed != "" {
+		out.T(out.Empty, "")
+	}
+	out.T(out.Empty, "==> {{.name}} <==", out.V{"name": name})
+	p.headed = name
+}
+
+// print outputs a line of a log, and its entry if the logs are structured
+func (p *printer) print(name string, line string, e Entry) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.q.JSON {
+		b, err := json.Marshal(e)
+		if err != nil {
+			glog.Errorf("marshal %+v: %v", e, err)
+			return
+		}
+		out.String("%s\n", b)
+		return
+	}
+
+	if p.prefix {
+		out.String("[%s] %s\n", name, line)
+		return
+	}
+	p.head(name)
+	out.T(out.Empty, line)
+}
+
+// logWriter is an io.Writer that outputs the lines of a log as they are written
+type logWriter struct {
+	sync.Mutex
+	p      *printer
+	src    source
+	parser parser
+	buf    []byte
+}
+
+func newLogWriter(p *printer, s source) *logWriter {
+	return &logWriter{p: p, src: s, parser: parser{source: s.name, node: p.q.Node, format: s.format}}
+}
+
+// Write outputs the complete lines written, and buffers the rest
+func (w *logWriter) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// flush outputs the last line written, if it is incomplete
+func (w *logWriter) flush() {
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.buf) > 0 {
+		w.line(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *logWriter) line(l string) {
+	var e Entry
+	if w.p.q.structured() {
+		e = w.parser.parse(l)
+		if !w.p.q.matches(e, w.p.now) {
+			return
+		}
+	}
+	w.p.print(w.src.name, l, e)
+}
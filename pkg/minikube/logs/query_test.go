@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ts := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		tm, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm.UTC().Format(time.RFC3339Nano)
+	}
+
+	var tests = []struct {
+		name    string
+		format  logFormat
+		lines   []string
+		time    string
+		level   string
+		message string
+	}{
+		{"plain", plainFormat, []string{"I0601 10:00:00.000000 1 a.go:1] ok"}, "", "", "I0601 10:00:00.000000 1 a.go:1] ok"},
+		{"journal klog", journalFormat, []string{"2020-06-01T10:00:00.123456+0000 minikube kubelet[1234]: E0601 10:00:00.123456    1234 kubelet.go:2263] node not found"},
+			"2020-06-01T10:00:00.123456Z", "error", "E0601 10:00:00.123456    1234 kubelet.go:2263] node not found"},
+		{"journal logrus", journalFormat, []string{`2020-06-01T12:00:00.000001+0200 minikube dockerd[99]: time="2020-06-01T10:00:00Z" level=warning msg="disk"`},
+			"2020-06-01T10:00:00.000001Z", "warning", `time="2020-06-01T10:00:00Z" level=warning msg="disk"`},
+		{"journal header", journalFormat, []string{"-- Logs begin at Mon 2020-06-01 09:00:00 UTC. --"}, "", "", "-- Logs begin at Mon 2020-06-01 09:00:00 UTC. --"},
+		{"container zap", containerFormat, []string{`2020-06-01T10:00:00.5Z {"level":"warn","msg":"slow"}`}, "2020-06-01T10:00:00.5Z", "warning", `{"level":"warn","msg":"slow"}`},
+		{"container coredns", containerFormat, []string{"2020-06-01T10:00:00Z [INFO] plugin/reload: Running configuration"}, "2020-06-01T10:00:00Z", "info", "[INFO] plugin/reload: Running configuration"},
+		{"container unknown", containerFormat, []string{"2020-06-01T10:00:00Z hello"}, "2020-06-01T10:00:00Z", "", "hello"},
+		{"dmesg", dmesgFormat, []string{"kern  :warn  : 2020-06-01T10:00:00,123456+00:00 overlayfs: upper fs does not support tmpfile."},
+			"2020-06-01T10:00:00.123456Z", "warning", "overlayfs: upper fs does not support tmpfile."},
+		{"continuation", containerFormat, []string{"2020-06-01T10:00:00Z F0601 10:00:00.000000 1 main.go:1] panic", "goroutine 1 [running]:"},
+			"2020-06-01T10:00:00Z", "fatal", "goroutine 1 [running]:"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := parser{source: "src", node: "minikube", format: tc.format}
+			var e Entry
+			for _, l := range tc.lines {
+				e = p.parse(l)
+			}
+
+			got := ""
+			if e.Timestamp != nil {
+				got = e.Timestamp.UTC().Format(time.RFC3339Nano)
+			}
+			if got != ts(tc.time) || e.Level != tc.level || e.Message != tc.message || e.Source != "src" || e.Node != "minikube" {
+				t.Errorf("parse(%q) = %+v at %s, want level %q, message %q at %s", tc.lines, e, got, tc.level, tc.message, ts(tc.time))
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	now := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	old := now.Add(-time.Hour)
+	recent := now.Add(-time.Minute)
+
+	var matches = []struct {
+		q     Query
+		e     Entry
+		match bool
+	}{
+		{Query{}, Entry{}, true},
+		{Query{Level: "warning"}, Entry{Level: "error"}, true},
+		{Query{Level: "warning"}, Entry{Level: "warning"}, true},
+		{Query{Level: "warning"}, Entry{Level: "info"}, false},
+		{Query{Level: "warning"}, Entry{}, false},
+		{Query{Since: 10 * time.Minute}, Entry{Timestamp: &recent}, true},
+		{Query{Since: 10 * time.Minute}, Entry{Timestamp: &old}, false},
+		{Query{Since: 10 * time.Minute}, Entry{}, true},
+	}
+	for _, tc := range matches {
+		if got := tc.q.matches(tc.e, now); got != tc.match {
+			t.Errorf("%+v matches %+v = %v, want %v", tc.q, tc.e, got, tc.match)
+		}
+	}
+
+	var selects = []struct {
+		q    Query
+		s    source
+		want bool
+	}{
+		{Query{}, source{name: "kubelet", component: "kubelet"}, true},
+		{Query{Components: []string{"kubelet"}}, source{name: "kubelet", component: "kubelet"}, true},
+		{Query{Components: []string{"apiserver"}}, source{name: "kube-apiserver [1a2b]", component: "kube-apiserver"}, true},
+		{Query{Components: []string{"runtime"}}, source{name: "Docker", component: "runtime"}, true},
+		{Query{Components: []string{"docker"}}, source{name: "Docker", component: "runtime"}, true},
+		{Query{Components: []string{"apiserver"}}, source{name: "kubelet", component: "kubelet"}, false},
+		{Query{Pods: []string{"nginx"}}, source{name: "kubelet", component: "kubelet"}, false},
+	}
+	for _, tc := range selects {
+		if got := tc.q.selects(tc.s); got != tc.want {
+			t.Errorf("%+v selects %+v = %v, want %v", tc.q, tc.s, got, tc.want)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]string{"W": "warning", "warn": "warning", "Error": "error", "crit": "fatal"} {
+		if got, err := ParseLevel(name); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Errorf("ParseLevel(\"loud\") did not fail")
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// buildRoot is where build contexts should be copied to within the guest VM
+var buildRoot = path.Join(vmpath.GuestPersistentDir, "build")
+
+// BuildImage transfers a local build context directory to a node, and builds an image from it
+// using the container runtime of the node.
+func BuildImage(cr command.Runner, k8s config.KubernetesConfig, src string, tag string) error {
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	f, err := ioutil.TempFile("", "build.*.tar")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(f.Name())
+
+	err = tarDir(src, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "archiving %s", src)
+	}
+
+	filename := filepath.Base(f.Name())
+	a, err := assets.NewFileAsset(f.Name(), buildRoot, filename, "0644")
+	if err != nil {
+		return errors.Wrapf(err, "creating copyable file asset: %s", filename)
+	}
+	if err := cr.Copy(a); err != nil {
+		return errors.Wrap(err, "transferring build context")
+	}
+
+	archive := path.Join(buildRoot, filename)
+	dir := path.Join(buildRoot, strings.TrimSuffix(filename, ".tar"))
+	defer func() {
+		if _, err := cr.RunCmd(exec.Command("sudo", "rm", "-rf", dir, archive)); err != nil {
+			glog.Warningf("unable to clean up build context %s: %v", dir, err)
+		}
+	}()
+
+	if _, err := cr.RunCmd(exec.Command("sudo", "mkdir", "-p", dir)); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if _, err := cr.RunCmd(exec.Command("sudo", "tar", "-C", dir, "-xf", archive)); err != nil {
+		return errors.Wrap(err, "extract build context")
+	}
+
+	if err := r.BuildImage(dir, tag); err != nil {
+		return errors.Wrapf(err, "%s build %s", r.Name(), dir)
+	}
+	glog.Infof("Built %s from %s", tag, src)
+	return nil
+}
+
+// tarDir writes the regular files and directories within dir to w as a tar archive
+func tarDir(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." || !(info.IsDir() || info.Mode().IsRegular()) {
+			return nil
+		}
+
+		h, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		h.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTarDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "build")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "app"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	files := map[string]string{
+		"Dockerfile":  "FROM busybox\nCOPY app /app\n",
+		"app/main.sh": "echo hello\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tarDir(dir, &buf); err != nil {
+		t.Fatalf("tarDir: %v", err)
+	}
+
+	got := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if h.Typeflag == tar.TypeDir {
+			got[h.Name] = "<dir>"
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got[h.Name] = string(b)
+	}
+
+	want := map[string]string{
+		"Dockerfile":  files["Dockerfile"],
+		"app":         "<dir>",
+		"app/main.sh": files["app/main.sh"],
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("tarDir mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -18,7 +18,9 @@ package machine
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
@@ -237,6 +239,16 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgNam
 	src := filepath.Join(cacheDir, imgName)
 	src = localpath.SanitizeCacheDir(src)
 	glog.Infof("Loading image from cache: %s", src)
+	if err := loadImageFile(cr, r, src); err != nil {
+		return err
+	}
+
+	glog.Infof("Transferred and loaded %s from cache", src)
+	return nil
+}
+
+// loadImageFile transfers an image tarball to the guest and loads it into the container runtime
+func loadImageFile(cr command.Runner, r cruntime.Manager, src string) error {
 	filename := filepath.Base(src)
 	if _, err := os.Stat(src); err != nil {
 		return err
@@ -249,6 +261,11 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgNam
 	if err := cr.Copy(f); err != nil {
 		return errors.Wrap(err, "transferring cached image")
 	}
+	defer func() {
+		if _, err := cr.RunCmd(exec.Command("sudo", "rm", "-f", dst)); err != nil {
+			glog.Warningf("unable to remove image tarball %s: %v", dst, err)
+		}
+	}()
 
 	loadImageLock.Lock()
 	defer loadImageLock.Unlock()
@@ -257,7 +274,30 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgNam
 	if err != nil {
 		return errors.Wrapf(err, "%s load %s", r.Name(), dst)
 	}
-
-	glog.Infof("Transferred and loaded %s from cache", src)
 	return nil
 }
+
+// LoadImageFile loads a local image tarball into the container runtime of a node, without caching it
+func LoadImageFile(cr command.Runner, k8s config.KubernetesConfig, src string) error {
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	glog.Infof("Loading image from file: %s", src)
+	return loadImageFile(cr, r, src)
+}
+
+// LoadDaemonImage loads an image from the local docker daemon, or failing that its registry, into the
+// container runtime of a node. Unlike CacheAndLoadImages, the image is not added to the image cache.
+func LoadDaemonImage(cr command.Runner, k8s config.KubernetesConfig, img string) error {
+	tmp, err := ioutil.TempDir("", "minikube-image")
+	if err != nil {
+		return errors.Wrap(err, "tempdir")
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := image.SaveToDir([]string{img}, tmp); err != nil {
+		return errors.Wrapf(err, "save %s", img)
+	}
+	return LoadImageFile(cr, k8s, localpath.SanitizeCacheDir(filepath.Join(tmp, img)))
+}
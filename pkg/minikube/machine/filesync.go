@@ -122,6 +122,13 @@ func assetsFromDir(localRoot string, destRoot string, flatten bool) ([]assets.Co
 			return err
 		}
 		if fi.IsDir() {
+			// User-defined addons are copied when they are enabled, rather than on every start
+			if localPath != localRoot {
+				if _, err := os.Stat(filepath.Join(localPath, assets.AddonManifestFile)); err == nil {
+					glog.Infof("skipping addon directory %s", localPath)
+					return filepath.SkipDir
+				}
+			}
 			return nil
 		}
 
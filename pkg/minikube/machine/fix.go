@@ -151,7 +151,7 @@ func recreateIfNeeded(api libmachine.API, cc *config.ClusterConfig, n *config.No
 	return h, nil
 }
 
-// maybeWarnAboutEvalEnv wil warn user if they need to re-eval their docker-env, podman-env
+// maybeWarnAboutEvalEnv wil warn user if they need to re-eval their docker-env, podman-env, container-env
 // because docker changes the allocated bind ports after restart https://github.com/kubernetes/minikube/issues/6824
 func maybeWarnAboutEvalEnv(drver string, name string) {
 	if !driver.IsKIC(drver) {
@@ -175,6 +175,14 @@ func maybeWarnAboutEvalEnv(drver string, name string) {
 
 	`, out.V{"profile_name": name})
 	}
+	if os.Getenv(constants.MinikubeActiveContainerRuntimeEnv) != "" {
+		out.T(out.Notice, "Noticed you have an activated container-env on {{.driver_name}} driver in this terminal:", out.V{"driver_name": drver})
+		out.WarningT(`Please re-eval your container-env, To forward the socket of the container runtime again:
+
+	'minikube -p {{.profile_name}} container-env'
+
+	`, out.V{"profile_name": name})
+	}
 
 }
 
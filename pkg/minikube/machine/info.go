@@ -17,13 +17,19 @@ limitations under the License.
 package machine
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/docker/machine/libmachine/provision"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/disk"
+	gopshost "github.com/shirou/gopsutil/host"
 	"github.com/shirou/gopsutil/mem"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -63,6 +69,26 @@ func getHostInfo() (*hostInfo, error) {
 	return &info, nil
 }
 
+// HostInfo describes the operating system and resources of the host minikube runs on
+func HostInfo() (string, error) {
+	info, err := getHostInfo()
+	if err != nil {
+		return "", err
+	}
+
+	var s strings.Builder
+	if hi, err := gopshost.Info(); err != nil {
+		glog.Warningf("gopshost.Info returned error: %v", err)
+	} else {
+		s.WriteString(fmt.Sprintf("OS: %s %s %s (kernel %s)\n", hi.OS, hi.Platform, hi.PlatformVersion, hi.KernelVersion))
+	}
+	s.WriteString(fmt.Sprintf("Architecture: %s\n", runtime.GOARCH))
+	s.WriteString(fmt.Sprintf("CPUs: %d\n", info.CPUs))
+	s.WriteString(fmt.Sprintf("Memory: %d MB\n", info.Memory))
+	s.WriteString(fmt.Sprintf("Disk: %d MB\n", info.DiskSize))
+	return s.String(), nil
+}
+
 // showLocalOsRelease shows systemd information about the current linux distribution, on the local host
 func showLocalOsRelease() {
 	osReleaseOut, err := ioutil.ReadFile("/etc/os-release")
@@ -95,3 +121,46 @@ func logRemoteOsRelease(r command.Runner) {
 
 	glog.Infof("Remote host: %s", osReleaseInfo.PrettyName)
 }
+
+// DiskUsed returns the percentage of the filesystem containing path which is in use, within the node
+func DiskUsed(r command.Runner, path string) (int, error) {
+	rr, err := r.RunCmd(exec.Command("sh", "-c", fmt.Sprintf("df -h %s | awk 'NR==2{print $5}'", path)))
+	if err != nil {
+		return 0, errors.Wrap(err, "df")
+	}
+	return parseDiskUsed(rr.Stdout.String())
+}
+
+// parseDiskUsed parses the use percentage reported by df, such as "42%"
+func parseDiskUsed(s string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+}
+
+// MemoryUsed returns the percentage of memory which is in use, within the node
+func MemoryUsed(r command.Runner) (int, error) {
+	rr, err := r.RunCmd(exec.Command("sh", "-c", "free -m | awk '/^Mem:/{print $2, $3}'"))
+	if err != nil {
+		return 0, errors.Wrap(err, "free")
+	}
+	return parseMemoryUsed(rr.Stdout.String())
+}
+
+// parseMemoryUsed parses the total and used megabytes reported by free, such as "1994 612"
+func parseMemoryUsed(s string) (int, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("unexpected output: %q", s)
+	}
+	total, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, errors.Wrap(err, "total")
+	}
+	used, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, errors.Wrap(err, "used")
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("no memory reported: %q", s)
+	}
+	return used * 100 / total, nil
+}
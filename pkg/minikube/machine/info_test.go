@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import "testing"
+
+func TestParseDiskUsed(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"42%\n", 42, false},
+		{"100%", 100, false},
+		{"", 0, true},
+		{"Use%", 0, true},
+	}
+	for _, tc := range tests {
+		got, err := parseDiskUsed(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseDiskUsed(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("parseDiskUsed(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseMemoryUsed(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"2000 500\n", 25, false},
+		{"1994 1994", 100, false},
+		{"0 0", 0, true},
+		{"2000", 0, true},
+		{"total used", 0, true},
+	}
+	for _, tc := range tests {
+		got, err := parseMemoryUsed(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseMemoryUsed(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("parseMemoryUsed(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
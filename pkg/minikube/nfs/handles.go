@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// rootID is the file handle of the shared directory
+const rootID = 1
+
+// handles maps the file handles given to the client to paths. Handles are only valid for the life
+// of the server, which is also the life of the mount.
+type handles struct {
+	mu    sync.Mutex
+	next  uint64
+	paths map[uint64]string
+	ids   map[string]uint64
+}
+
+func newHandles(root string) *handles {
+	return &handles{
+		next:  rootID + 1,
+		paths: map[uint64]string{rootID: root},
+		ids:   map[string]uint64{root: rootID},
+	}
+}
+
+// id returns the handle of a path, allocating one if needed
+func (h *handles) id(path string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if id, ok := h.ids[path]; ok {
+		return id
+	}
+	id := h.next
+	h.next++
+	h.ids[path] = id
+	h.paths[id] = path
+	return id
+}
+
+// path returns the path of a handle
+func (h *handles) path(id uint64) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p, ok := h.paths[id]
+	return p, ok
+}
+
+// rename moves the handles of a path and everything below it, so that they stay valid
+func (h *handles) rename(from string, to string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.forget(to)
+	for p, id := range h.ids {
+		rel, ok := below(from, p)
+		if !ok {
+			continue
+		}
+		np := filepath.Join(to, rel)
+		delete(h.ids, p)
+		h.ids[np] = id
+		h.paths[id] = np
+	}
+}
+
+// remove forgets the handles of a path and everything below it
+func (h *handles) remove(path string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.forget(path)
+}
+
+func (h *handles) forget(path string) {
+	for p, id := range h.ids {
+		if _, ok := below(path, p); ok && id != rootID {
+			delete(h.ids, p)
+			delete(h.paths, id)
+		}
+	}
+}
+
+// below returns the path of p relative to dir, if p is dir or inside of it
+func below(dir string, p string) (string, bool) {
+	if p == dir {
+		return ".", true
+	}
+	if strings.HasPrefix(p, dir+string(filepath.Separator)) {
+		return p[len(dir)+1:], true
+	}
+	return "", false
+}
+
+// encodeHandle returns the wire form of a handle
+func encodeHandle(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+// decodeHandle parses the wire form of a handle
+func decodeHandle(b []byte) (uint64, bool) {
+	if len(b) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(b), true
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"github.com/golang/glog"
+)
+
+const (
+	// exportPath is the only export, the shared directory
+	exportPath = "/"
+	// maxDirPath is the size limit of dirpath
+	maxDirPath = 1024
+
+	mnt3OK       = 0
+	mnt3ErrNoEnt = 2
+)
+
+// mountProcedures are the MOUNTv3 procedures, indexed by number
+var mountProcedures = []procedure{
+	0: func(s *Server, d *decoder, e *encoder) error { return nil },
+	1: (*Server).mnt,
+	2: (*Server).dump,
+	3: (*Server).umnt,
+	4: func(s *Server, d *decoder, e *encoder) error { return nil },
+	5: (*Server).export,
+}
+
+func (s *Server) mnt(d *decoder, e *encoder) error {
+	dir := d.string(maxDirPath)
+	if d.err != nil {
+		return errGarbage
+	}
+	if dir != exportPath {
+		glog.Warningf("nfs: refusing to mount %q, only %q is exported", dir, exportPath)
+		e.uint32(mnt3ErrNoEnt)
+		return nil
+	}
+	glog.Infof("nfs: mounting %s", s.root)
+	e.uint32(mnt3OK)
+	e.opaque(encodeHandle(rootID))
+	// auth_flavors
+	e.uint32(1)
+	e.uint32(authUnix)
+	return nil
+}
+
+func (s *Server) umnt(d *decoder, e *encoder) error {
+	d.string(maxDirPath)
+	if d.err != nil {
+		return errGarbage
+	}
+	glog.Infof("nfs: unmounting %s", s.root)
+	return nil
+}
+
+// dump lists no mounts, as they aren't tracked
+func (s *Server) dump(d *decoder, e *encoder) error {
+	e.bool(false)
+	return nil
+}
+
+func (s *Server) export(d *decoder, e *encoder) error {
+	e.bool(true)
+	e.string(exportPath)
+	// no groups, so that any client may mount it
+	e.bool(false)
+	e.bool(false)
+	return nil
+}
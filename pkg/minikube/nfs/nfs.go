@@ -0,0 +1,965 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// nfsstat3 values
+const (
+	nfs3OK             = 0
+	nfs3ErrPerm        = 1
+	nfs3ErrNoEnt       = 2
+	nfs3ErrIO          = 5
+	nfs3ErrAcces       = 13
+	nfs3ErrExist       = 17
+	nfs3ErrXDev        = 18
+	nfs3ErrNotDir      = 20
+	nfs3ErrIsDir       = 21
+	nfs3ErrInval       = 22
+	nfs3ErrFBig        = 27
+	nfs3ErrNoSpc       = 28
+	nfs3ErrROFS        = 30
+	nfs3ErrNameTooLong = 63
+	nfs3ErrNotEmpty    = 66
+	nfs3ErrStale       = 70
+	nfs3ErrBadHandle   = 10001
+	nfs3ErrBadCookie   = 10003
+	nfs3ErrNotSupp     = 10004
+	nfs3ErrTooSmall    = 10005
+)
+
+// ftype3 values
+const (
+	nf3Reg  = 1
+	nf3Dir  = 2
+	nf3Blk  = 3
+	nf3Chr  = 4
+	nf3Lnk  = 5
+	nf3Sock = 6
+	nf3FIFO = 7
+)
+
+// createmode3 values
+const (
+	createUnchecked = 0
+	createGuarded   = 1
+	createExclusive = 2
+)
+
+// time_how values of sattr3
+const (
+	setToServerTime = 1
+	setToClientTime = 2
+)
+
+const (
+	// maxData is the largest read or write the server accepts
+	maxData = 1 << 20
+	// maxName is the longest file name the server accepts
+	maxName = 255
+	// maxPath is the longest symbolic link target the server accepts
+	maxPath = 4096
+	// maxHandle is the size limit of nfs_fh3
+	maxHandle = 64
+	// fsid identifies the shared directory as a single filesystem
+	fsid = 1
+
+	// stableUnstable is the stable_how of writes that haven't been synced yet
+	stableUnstable = 0
+	// stableFileSync is the stable_how of writes that were synced with their metadata
+	stableFileSync = 2
+
+	// the sizes of the encoded fields of directory entries, used to fit listings within the size asked for
+	entrySize     = 4 + 8 + 4 + 8
+	entryPlusSize = entrySize + 4 + 84 + 4 + 4 + 8
+	listingSize   = 4 + 4 + 84 + 8 + 4 + 4
+)
+
+// nfsProcedures are the NFSv3 procedures, indexed by number
+var nfsProcedures = []procedure{
+	0:  func(s *Server, d *decoder, e *encoder) error { return nil },
+	1:  (*Server).getattr,
+	2:  (*Server).setattr,
+	3:  (*Server).lookup,
+	4:  (*Server).access,
+	5:  (*Server).readlink,
+	6:  (*Server).read,
+	7:  (*Server).write,
+	8:  (*Server).create,
+	9:  (*Server).mkdir,
+	10: (*Server).symlink,
+	11: (*Server).mknod,
+	12: (*Server).remove,
+	13: (*Server).rmdir,
+	14: (*Server).rename,
+	15: (*Server).link,
+	16: (*Server).readdir,
+	17: (*Server).readdirplus,
+	18: (*Server).fsstat,
+	19: (*Server).fsinfo,
+	20: (*Server).pathconf,
+	21: (*Server).commit,
+}
+
+// nfsStatus is an error carrying the nfsstat3 to return
+type nfsStatus uint32
+
+func (st nfsStatus) Error() string {
+	return fmt.Sprintf("nfs status %d", uint32(st))
+}
+
+// statusOf returns the nfsstat3 describing an error
+func statusOf(err error) uint32 {
+	if err == nil {
+		return nfs3OK
+	}
+	if st, ok := err.(nfsStatus); ok {
+		return uint32(st)
+	}
+	// os.IsExist also matches ENOTEMPTY, so the errors with their own status are checked first
+	cause := err
+	switch e := err.(type) {
+	case *os.PathError:
+		cause = e.Err
+	case *os.LinkError:
+		cause = e.Err
+	case *os.SyscallError:
+		cause = e.Err
+	}
+	errno, _ := cause.(syscall.Errno)
+	switch errno {
+	case syscall.EPERM:
+		return nfs3ErrPerm
+	case syscall.EXDEV:
+		return nfs3ErrXDev
+	case syscall.ENOTDIR:
+		return nfs3ErrNotDir
+	case syscall.EISDIR:
+		return nfs3ErrIsDir
+	case syscall.EINVAL:
+		return nfs3ErrInval
+	case syscall.EFBIG:
+		return nfs3ErrFBig
+	case syscall.ENOSPC:
+		return nfs3ErrNoSpc
+	case syscall.EROFS:
+		return nfs3ErrROFS
+	case syscall.ENAMETOOLONG:
+		return nfs3ErrNameTooLong
+	case syscall.ENOTEMPTY:
+		return nfs3ErrNotEmpty
+	}
+
+	switch {
+	case os.IsNotExist(err):
+		return nfs3ErrNoEnt
+	case os.IsPermission(err):
+		return nfs3ErrAcces
+	case os.IsExist(err):
+		return nfs3ErrExist
+	}
+	return nfs3ErrIO
+}
+
+// resolve returns the path of a file handle
+func (s *Server) resolve(fh []byte) (string, error) {
+	id, ok := decodeHandle(fh)
+	if !ok {
+		return "", nfsStatus(nfs3ErrBadHandle)
+	}
+	p, ok := s.handles.path(id)
+	if !ok {
+		return "", nfsStatus(nfs3ErrStale)
+	}
+	return p, nil
+}
+
+// child returns the path of an entry of a directory, keeping lookups within the shared directory
+func (s *Server) child(dir string, name string) (string, error) {
+	switch {
+	case name == "" || strings.ContainsAny(name, "/\x00") || (filepath.Separator != '/' && strings.ContainsRune(name, filepath.Separator)):
+		return "", nfsStatus(nfs3ErrInval)
+	case len(name) > maxName:
+		return "", nfsStatus(nfs3ErrNameTooLong)
+	case name == ".":
+		return dir, nil
+	case name == "..":
+		return s.parent(dir), nil
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// parent returns the parent of a directory, which for the shared directory is itself
+func (s *Server) parent(dir string) string {
+	if dir == s.root {
+		return dir
+	}
+	return filepath.Dir(dir)
+}
+
+// dirop resolves the diropargs3 naming a new entry, which may not be "." or ".."
+func (s *Server) dirop(fh []byte, name string) (string, string, error) {
+	dir, err := s.resolve(fh)
+	if err != nil {
+		return "", "", err
+	}
+	if name == "." || name == ".." {
+		return dir, "", nfsStatus(nfs3ErrExist)
+	}
+	p, err := s.child(dir, name)
+	return dir, p, err
+}
+
+// fattr encodes the attributes of a file
+func (s *Server) fattr(e *encoder, p string, fi os.FileInfo) {
+	m := fi.Mode()
+	typ := uint32(nf3Reg)
+	nlink := uint32(1)
+	switch {
+	case m.IsDir():
+		typ = nf3Dir
+		nlink = 2
+	case m&os.ModeSymlink != 0:
+		typ = nf3Lnk
+	case m&os.ModeNamedPipe != 0:
+		typ = nf3FIFO
+	case m&os.ModeSocket != 0:
+		typ = nf3Sock
+	case m&os.ModeCharDevice != 0:
+		typ = nf3Chr
+	case m&os.ModeDevice != 0:
+		typ = nf3Blk
+	}
+	mode := uint32(m.Perm())
+	if m&os.ModeSetuid != 0 {
+		mode |= 04000
+	}
+	if m&os.ModeSetgid != 0 {
+		mode |= 02000
+	}
+	if m&os.ModeSticky != 0 {
+		mode |= 01000
+	}
+
+	e.uint32(typ)
+	e.uint32(mode)
+	e.uint32(nlink)
+	e.uint32(s.uid)
+	e.uint32(s.gid)
+	e.uint64(uint64(fi.Size()))
+	e.uint64(uint64(fi.Size()))
+	// rdev
+	e.uint32(0)
+	e.uint32(0)
+	e.uint64(fsid)
+	e.uint64(s.handles.id(p))
+	// the modification time stands in for the access and change times, which aren't portable
+	for i := 0; i < 3; i++ {
+		encodeTime(e, fi.ModTime())
+	}
+}
+
+// postOpAttr encodes the attributes of a file, if they are available
+func (s *Server) postOpAttr(e *encoder, p string) {
+	if p == "" {
+		e.bool(false)
+		return
+	}
+	fi, err := os.Lstat(p)
+	if err != nil {
+		e.bool(false)
+		return
+	}
+	e.bool(true)
+	s.fattr(e, p, fi)
+}
+
+// wcc encodes the weak cache consistency data of a file, with its attributes after the operation only
+func (s *Server) wcc(e *encoder, p string) {
+	e.bool(false)
+	s.postOpAttr(e, p)
+}
+
+// postOpHandle encodes the handle of a file
+func (s *Server) postOpHandle(e *encoder, p string) {
+	e.bool(true)
+	e.opaque(encodeHandle(s.handles.id(p)))
+}
+
+func encodeTime(e *encoder, t time.Time) {
+	e.uint32(uint32(t.Unix()))
+	e.uint32(uint32(t.Nanosecond()))
+}
+
+func decodeTime(d *decoder) time.Time {
+	sec := d.uint32()
+	nsec := d.uint32()
+	return time.Unix(int64(sec), int64(nsec))
+}
+
+// sattr are the attributes a client sets, as decoded from sattr3
+type sattr struct {
+	mode  *uint32
+	size  *uint64
+	atime *time.Time
+	mtime *time.Time
+}
+
+func decodeSattr(d *decoder) sattr {
+	var a sattr
+	if d.bool() {
+		mode := d.uint32()
+		a.mode = &mode
+	}
+	// files are always owned by the configured user, so ownership changes are ignored
+	if d.bool() {
+		d.uint32()
+	}
+	if d.bool() {
+		d.uint32()
+	}
+	if d.bool() {
+		size := d.uint64()
+		a.size = &size
+	}
+	a.atime = decodeSetTime(d)
+	a.mtime = decodeSetTime(d)
+	return a
+}
+
+func decodeSetTime(d *decoder) *time.Time {
+	var t time.Time
+	switch d.uint32() {
+	case setToServerTime:
+		t = time.Now()
+	case setToClientTime:
+		t = decodeTime(d)
+	default:
+		return nil
+	}
+	return &t
+}
+
+// apply sets the attributes of a file
+func (a sattr) apply(p string) error {
+	if a.mode != nil {
+		if err := os.Chmod(p, os.FileMode(*a.mode&0777)); err != nil {
+			return err
+		}
+	}
+	if a.size != nil {
+		if err := os.Truncate(p, int64(*a.size)); err != nil {
+			return err
+		}
+	}
+	if a.atime == nil && a.mtime == nil {
+		return nil
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	atime, mtime := fi.ModTime(), fi.ModTime()
+	if a.atime != nil {
+		atime = *a.atime
+	}
+	if a.mtime != nil {
+		mtime = *a.mtime
+	}
+	return os.Chtimes(p, atime, mtime)
+}
+
+func (s *Server) getattr(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	if d.err != nil {
+		return errGarbage
+	}
+	p, err := s.resolve(fh)
+	if err != nil {
+		e.uint32(statusOf(err))
+		return nil
+	}
+	fi, err := os.Lstat(p)
+	if err != nil {
+		e.uint32(statusOf(err))
+		return nil
+	}
+	e.uint32(nfs3OK)
+	s.fattr(e, p, fi)
+	return nil
+}
+
+func (s *Server) setattr(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	a := decodeSattr(d)
+	// the guard is a ctime, which isn't tracked
+	if d.bool() {
+		decodeTime(d)
+	}
+	if d.err != nil {
+		return errGarbage
+	}
+	p, err := s.resolve(fh)
+	if err == nil {
+		err = a.apply(p)
+	}
+	e.uint32(statusOf(err))
+	s.wcc(e, p)
+	return nil
+}
+
+func (s *Server) lookup(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	name := d.string(maxName + 1)
+	if d.err != nil {
+		return errGarbage
+	}
+	dir, err := s.resolve(fh)
+	if err != nil {
+		e.uint32(statusOf(err))
+		e.bool(false)
+		return nil
+	}
+	p, err := s.child(dir, name)
+	if err == nil {
+		_, err = os.Lstat(p)
+	}
+	e.uint32(statusOf(err))
+	if err == nil {
+		e.opaque(encodeHandle(s.handles.id(p)))
+		s.postOpAttr(e, p)
+	}
+	s.postOpAttr(e, dir)
+	return nil
+}
+
+func (s *Server) access(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	want := d.uint32()
+	if d.err != nil {
+		return errGarbage
+	}
+	p, err := s.resolve(fh)
+	if err == nil {
+		_, err = os.Lstat(p)
+	}
+	e.uint32(statusOf(err))
+	s.postOpAttr(e, p)
+	if err == nil {
+		// the host enforces permissions on the files themselves, as the user running the server
+		e.uint32(want)
+	}
+	return nil
+}
+
+func (s *Server) readlink(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	if d.err != nil {
+		return errGarbage
+	}
+	p, err := s.resolve(fh)
+	var target string
+	if err == nil {
+		target, err = os.Readlink(p)
+	}
+	e.uint32(statusOf(err))
+	s.postOpAttr(e, p)
+	if err == nil {
+		e.string(filepath.ToSlash(target))
+	}
+	return nil
+}
+
+func (s *Server) read(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	offset := d.uint64()
+	count := d.uint32()
+	if d.err != nil {
+		return errGarbage
+	}
+	if count > maxData {
+		count = maxData
+	}
+	p, err := s.resolve(fh)
+	var data []byte
+	eof := false
+	if err == nil {
+		data, eof, err = readAt(p, int64(offset), int(count))
+	}
+	e.uint32(statusOf(err))
+	s.postOpAttr(e, p)
+	if err == nil {
+		e.uint32(uint32(len(data)))
+		e.bool(eof)
+		e.opaque(data)
+	}
+	return nil
+}
+
+// readAt reads up to count bytes of a file, and whether the end of the file was reached
+func readAt(p string, offset int64, count int) ([]byte, bool, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	data := make([]byte, count)
+	n, err := f.ReadAt(data, offset)
+	if err == io.EOF {
+		return data[:n], true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	return data[:n], offset+int64(n) >= fi.Size(), nil
+}
+
+func (s *Server) write(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	offset := d.uint64()
+	d.uint32()
+	stable := d.uint32()
+	data := d.opaque(maxData)
+	if d.err != nil {
+		return errGarbage
+	}
+	p, err := s.resolve(fh)
+	if err == nil {
+		err = writeAt(p, int64(offset), data, stable != stableUnstable)
+	}
+	e.uint32(statusOf(err))
+	s.wcc(e, p)
+	if err == nil {
+		e.uint32(uint32(len(data)))
+		if stable == stableUnstable {
+			e.uint32(stableUnstable)
+		} else {
+			e.uint32(stableFileSync)
+		}
+		e.fixed(s.verifier[:])
+	}
+	return nil
+}
+
+// writeAt writes to a file, syncing it if the client asked for stable storage
+func writeAt(p string, offset int64, data []byte, sync bool) error {
+	f, err := os.OpenFile(p, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(data, offset); err != nil {
+		f.Close()
+		return err
+	}
+	if sync {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+func (s *Server) create(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	name := d.string(maxName + 1)
+	how := d.uint32()
+	var a sattr
+	if how == createExclusive {
+		d.fixed(8)
+	} else {
+		a = decodeSattr(d)
+	}
+	if d.err != nil {
+		return errGarbage
+	}
+
+	dir, p, err := s.dirop(fh, name)
+	if err == nil {
+		flags := os.O_WRONLY | os.O_CREATE
+		if how != createUnchecked {
+			flags |= os.O_EXCL
+		}
+		mode := os.FileMode(0644)
+		if a.mode != nil {
+			mode = os.FileMode(*a.mode & 0777)
+		}
+		var f *os.File
+		if f, err = os.OpenFile(p, flags, mode); err == nil {
+			f.Close()
+			err = a.apply(p)
+		}
+	}
+	s.created(e, dir, p, err)
+	return nil
+}
+
+// created encodes the result of a procedure creating a file
+func (s *Server) created(e *encoder, dir string, p string, err error) {
+	e.uint32(statusOf(err))
+	if err == nil {
+		s.postOpHandle(e, p)
+		s.postOpAttr(e, p)
+	}
+	s.wcc(e, dir)
+}
+
+func (s *Server) mkdir(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	name := d.string(maxName + 1)
+	a := decodeSattr(d)
+	if d.err != nil {
+		return errGarbage
+	}
+	dir, p, err := s.dirop(fh, name)
+	if err == nil {
+		mode := os.FileMode(0755)
+		if a.mode != nil {
+			mode = os.FileMode(*a.mode & 0777)
+		}
+		if err = os.Mkdir(p, mode); err == nil {
+			err = a.apply(p)
+		}
+	}
+	s.created(e, dir, p, err)
+	return nil
+}
+
+func (s *Server) symlink(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	name := d.string(maxName + 1)
+	decodeSattr(d)
+	target := d.string(maxPath)
+	if d.err != nil {
+		return errGarbage
+	}
+	dir, p, err := s.dirop(fh, name)
+	if err == nil {
+		err = os.Symlink(filepath.FromSlash(target), p)
+	}
+	s.created(e, dir, p, err)
+	return nil
+}
+
+func (s *Server) mknod(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	d.string(maxName + 1)
+	if d.err != nil {
+		return errGarbage
+	}
+	// special files can't be created portably on the host
+	dir, _ := s.resolve(fh)
+	e.uint32(nfs3ErrNotSupp)
+	s.wcc(e, dir)
+	return nil
+}
+
+func (s *Server) remove(d *decoder, e *encoder) error {
+	return s.unlink(d, e, false)
+}
+
+func (s *Server) rmdir(d *decoder, e *encoder) error {
+	return s.unlink(d, e, true)
+}
+
+// unlink removes a file, or an empty directory
+func (s *Server) unlink(d *decoder, e *encoder, isDir bool) error {
+	fh := d.opaque(maxHandle)
+	name := d.string(maxName + 1)
+	if d.err != nil {
+		return errGarbage
+	}
+	dir, p, err := s.dirop(fh, name)
+	if err == nil {
+		err = unlink(p, isDir)
+	}
+	if err == nil {
+		s.handles.remove(p)
+	}
+	e.uint32(statusOf(err))
+	s.wcc(e, dir)
+	return nil
+}
+
+func unlink(p string, isDir bool) error {
+	fi, err := os.Lstat(p)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() != isDir {
+		if isDir {
+			return nfsStatus(nfs3ErrNotDir)
+		}
+		return nfsStatus(nfs3ErrIsDir)
+	}
+	return os.Remove(p)
+}
+
+func (s *Server) rename(d *decoder, e *encoder) error {
+	fromFH := d.opaque(maxHandle)
+	fromName := d.string(maxName + 1)
+	toFH := d.opaque(maxHandle)
+	toName := d.string(maxName + 1)
+	if d.err != nil {
+		return errGarbage
+	}
+	fromDir, from, err := s.dirop(fromFH, fromName)
+	toDir, to, terr := s.dirop(toFH, toName)
+	if err == nil {
+		err = terr
+	}
+	if err == nil {
+		err = os.Rename(from, to)
+	}
+	if err == nil {
+		s.handles.rename(from, to)
+	}
+	e.uint32(statusOf(err))
+	s.wcc(e, fromDir)
+	s.wcc(e, toDir)
+	return nil
+}
+
+func (s *Server) link(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	dirFH := d.opaque(maxHandle)
+	name := d.string(maxName + 1)
+	if d.err != nil {
+		return errGarbage
+	}
+	p, err := s.resolve(fh)
+	dir, np, derr := s.dirop(dirFH, name)
+	if err == nil {
+		err = derr
+	}
+	if err == nil {
+		err = os.Link(p, np)
+	}
+	e.uint32(statusOf(err))
+	s.postOpAttr(e, p)
+	s.wcc(e, dir)
+	return nil
+}
+
+// entry is a directory entry returned by READDIR and READDIRPLUS
+type entry struct {
+	name string
+	path string
+}
+
+// entries lists a directory, starting with "." and "..", so that an entry's cookie is its index plus one
+func (s *Server) entries(dir string) ([]entry, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	es := []entry{{".", dir}, {"..", s.parent(dir)}}
+	for _, fi := range fis {
+		es = append(es, entry{fi.Name(), filepath.Join(dir, fi.Name())})
+	}
+	return es, nil
+}
+
+func (s *Server) readdir(d *decoder, e *encoder) error {
+	return s.list(d, e, false)
+}
+
+func (s *Server) readdirplus(d *decoder, e *encoder) error {
+	return s.list(d, e, true)
+}
+
+// list encodes the entries of a directory following a cookie, as many as fit within the size asked for
+func (s *Server) list(d *decoder, e *encoder, plus bool) error {
+	fh := d.opaque(maxHandle)
+	cookie := d.uint64()
+	d.fixed(8)
+	count := d.uint32()
+	if plus {
+		// dircount only limits the names and cookies, maxcount the whole reply
+		count = d.uint32()
+	}
+	if d.err != nil {
+		return errGarbage
+	}
+	dir, err := s.resolve(fh)
+	var es []entry
+	if err == nil {
+		es, err = s.entries(dir)
+	}
+	if err == nil && cookie > uint64(len(es)) {
+		err = nfsStatus(nfs3ErrBadCookie)
+	}
+	mark := e.Len()
+	e.uint32(statusOf(err))
+	s.postOpAttr(e, dir)
+	if err != nil {
+		return nil
+	}
+
+	// the listing isn't versioned, so the cookie verifier is always zero
+	e.fixed(make([]byte, 8))
+	size := listingSize
+	i := int(cookie)
+	for ; i < len(es); i++ {
+		n := entrySize + len(es[i].name) + pad(len(es[i].name))
+		if plus {
+			n = entryPlusSize + len(es[i].name) + pad(len(es[i].name))
+		}
+		if size+n > int(count) {
+			break
+		}
+		size += n
+		e.bool(true)
+		e.uint64(s.handles.id(es[i].path))
+		e.string(es[i].name)
+		e.uint64(uint64(i + 1))
+		if plus {
+			s.postOpAttr(e, es[i].path)
+			s.postOpHandle(e, es[i].path)
+		}
+	}
+	if i == int(cookie) && i < len(es) {
+		// not even one entry fits
+		e.Truncate(mark)
+		e.uint32(nfs3ErrTooSmall)
+		s.postOpAttr(e, dir)
+		return nil
+	}
+	e.bool(false)
+	e.bool(i == len(es))
+	return nil
+}
+
+func (s *Server) fsstat(d *decoder, e *encoder) error {
+	p, err := s.fsop(d, e)
+	if err != nil || p == "" {
+		return err
+	}
+	// free space isn't portable to query, so the filesystem is reported as mostly free
+	const total = 1 << 40
+	for _, v := range []uint64{total, total / 2, total / 2, 1 << 20, 1 << 19, 1 << 19} {
+		e.uint64(v)
+	}
+	// invarsec
+	e.uint32(0)
+	return nil
+}
+
+func (s *Server) fsinfo(d *decoder, e *encoder) error {
+	p, err := s.fsop(d, e)
+	if err != nil || p == "" {
+		return err
+	}
+	// rtmax, rtpref, rtmult
+	e.uint32(maxData)
+	e.uint32(maxData)
+	e.uint32(4096)
+	// wtmax, wtpref, wtmult
+	e.uint32(maxData)
+	e.uint32(maxData)
+	e.uint32(4096)
+	// dtpref
+	e.uint32(64 * 1024)
+	// maxfilesize
+	e.uint64(1<<63 - 1)
+	// time_delta
+	e.uint32(0)
+	e.uint32(1)
+	// FSF3_LINK | FSF3_SYMLINK | FSF3_HOMOGENEOUS | FSF3_CANSETTIME
+	e.uint32(0x1 | 0x2 | 0x8 | 0x10)
+	return nil
+}
+
+func (s *Server) pathconf(d *decoder, e *encoder) error {
+	p, err := s.fsop(d, e)
+	if err != nil || p == "" {
+		return err
+	}
+	// linkmax, name_max
+	e.uint32(1024)
+	e.uint32(maxName)
+	// no_trunc, chown_restricted, case_insensitive, case_preserving
+	e.bool(true)
+	e.bool(true)
+	e.bool(false)
+	e.bool(true)
+	return nil
+}
+
+// fsop decodes the handle of a procedure about the filesystem, and encodes the start of its result.
+// It returns the path of the handle, or "" if the procedure failed.
+func (s *Server) fsop(d *decoder, e *encoder) (string, error) {
+	fh := d.opaque(maxHandle)
+	if d.err != nil {
+		return "", errGarbage
+	}
+	p, err := s.resolve(fh)
+	if err == nil {
+		_, err = os.Lstat(p)
+	}
+	e.uint32(statusOf(err))
+	s.postOpAttr(e, p)
+	if err != nil {
+		return "", nil
+	}
+	return p, nil
+}
+
+func (s *Server) commit(d *decoder, e *encoder) error {
+	fh := d.opaque(maxHandle)
+	d.uint64()
+	d.uint32()
+	if d.err != nil {
+		return errGarbage
+	}
+	p, err := s.resolve(fh)
+	if err == nil {
+		err = syncFile(p)
+	}
+	e.uint32(statusOf(err))
+	s.wcc(e, p)
+	if err == nil {
+		e.fixed(s.verifier[:])
+	}
+	return nil
+}
+
+// syncFile flushes the writes to a file to stable storage
+func syncFile(p string) error {
+	f, err := os.OpenFile(p, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// client makes RPC calls to a server in tests
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	xid  uint32
+}
+
+// newClient starts a server sharing root, and connects to it
+func newClient(t *testing.T, root string) (*client, func()) {
+	s, err := NewServer(root, 1000, 1000)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() {
+		_ = s.Serve(l)
+	}()
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	return &client{t: t, conn: conn}, func() {
+		conn.Close()
+		l.Close()
+	}
+}
+
+// call makes a call, and returns the decoder of its results after checking that it was accepted
+func (c *client) call(prog uint32, proc uint32, args func(e *encoder)) *decoder {
+	c.xid++
+	e := &encoder{}
+	e.uint32(c.xid)
+	e.uint32(msgCall)
+	e.uint32(rpcVersion)
+	e.uint32(prog)
+	e.uint32(3)
+	e.uint32(proc)
+	e.uint32(authUnix)
+	e.opaque(make([]byte, 20))
+	e.uint32(authNone)
+	e.opaque(nil)
+	if args != nil {
+		args(e)
+	}
+	if err := writeRecord(c.conn, e.Bytes()); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+	reply, err := readRecord(c.conn)
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+
+	d := &decoder{buf: reply}
+	xid, typ, stat := d.uint32(), d.uint32(), d.uint32()
+	d.uint32()
+	d.opaque(maxAuth)
+	accept := d.uint32()
+	if d.err != nil || xid != c.xid || typ != msgReply || stat != replyAccepted || accept != acceptSuccess {
+		c.t.Fatalf("call %d/%d: unexpected reply %v %d %d %d %d", prog, proc, d.err, xid, typ, stat, accept)
+	}
+	return d
+}
+
+// status checks the status of a result
+func (c *client) status(d *decoder, want uint32) {
+	c.t.Helper()
+	if got := d.uint32(); got != want {
+		c.t.Fatalf("status = %d, want %d", got, want)
+	}
+}
+
+// skipAttr skips a post_op_attr
+func skipAttr(d *decoder) {
+	if d.bool() {
+		d.next(84)
+	}
+}
+
+// skipWcc skips a wcc_data
+func skipWcc(d *decoder) {
+	if d.bool() {
+		d.next(24)
+	}
+	skipAttr(d)
+}
+
+func (c *client) mount() []byte {
+	d := c.call(progMount, 1, func(e *encoder) { e.string("/") })
+	c.status(d, mnt3OK)
+	return d.opaque(maxHandle)
+}
+
+func (c *client) lookup(dir []byte, name string, want uint32) []byte {
+	d := c.call(progNFS, 3, func(e *encoder) {
+		e.opaque(dir)
+		e.string(name)
+	})
+	c.status(d, want)
+	if want != nfs3OK {
+		return nil
+	}
+	return d.opaque(maxHandle)
+}
+
+func (c *client) create(dir []byte, name string) []byte {
+	d := c.call(progNFS, 8, func(e *encoder) {
+		e.opaque(dir)
+		e.string(name)
+		e.uint32(createGuarded)
+		// mode 0600, and nothing else
+		e.bool(true)
+		e.uint32(0600)
+		for i := 0; i < 3; i++ {
+			e.bool(false)
+		}
+		e.uint32(0)
+		e.uint32(0)
+	})
+	c.status(d, nfs3OK)
+	if !d.bool() {
+		c.t.Fatalf("create returned no handle")
+	}
+	return d.opaque(maxHandle)
+}
+
+func (c *client) readdir(dir []byte) []string {
+	d := c.call(progNFS, 16, func(e *encoder) {
+		e.opaque(dir)
+		e.uint64(0)
+		e.fixed(make([]byte, 8))
+		e.uint32(4096)
+	})
+	c.status(d, nfs3OK)
+	skipAttr(d)
+	d.fixed(8)
+	var names []string
+	for d.bool() {
+		d.uint64()
+		names = append(names, d.string(maxName))
+		d.uint64()
+	}
+	if !d.bool() {
+		c.t.Errorf("readdir did not reach the end of the directory")
+	}
+	return names
+}
+
+func TestServer(t *testing.T) {
+	root, err := ioutil.TempDir("", "nfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := os.Mkdir(filepath.Join(root, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	c, stop := newClient(t, root)
+	defer stop()
+	c.call(progNFS, 0, nil)
+	rootFH := c.mount()
+
+	dir := c.lookup(rootFH, "dir", nfs3OK)
+	c.lookup(rootFH, "missing", nfs3ErrNoEnt)
+	c.lookup(rootFH, "../escape", nfs3ErrInval)
+	if parent := c.lookup(dir, "..", nfs3OK); !bytes.Equal(parent, rootFH) {
+		t.Errorf("lookup of .. = %x, want the root %x", parent, rootFH)
+	}
+	if parent := c.lookup(rootFH, "..", nfs3OK); !bytes.Equal(parent, rootFH) {
+		t.Errorf("lookup of .. of the root = %x, want the root %x", parent, rootFH)
+	}
+
+	file := c.create(dir, "file")
+	data := []byte("hello, world")
+	d := c.call(progNFS, 7, func(e *encoder) {
+		e.opaque(file)
+		e.uint64(0)
+		e.uint32(uint32(len(data)))
+		e.uint32(stableFileSync)
+		e.opaque(data)
+	})
+	c.status(d, nfs3OK)
+	skipWcc(d)
+	if n := d.uint32(); n != uint32(len(data)) {
+		t.Errorf("wrote %d bytes, want %d", n, len(data))
+	}
+	if got, err := ioutil.ReadFile(filepath.Join(root, "dir", "file")); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("file contains %q (%v), want %q", got, err, data)
+	}
+
+	d = c.call(progNFS, 1, func(e *encoder) { e.opaque(file) })
+	c.status(d, nfs3OK)
+	typ, mode, _, uid, gid, size := d.uint32(), d.uint32(), d.uint32(), d.uint32(), d.uint32(), d.uint64()
+	if typ != nf3Reg || mode != 0600 || uid != 1000 || gid != 1000 || size != uint64(len(data)) {
+		t.Errorf("getattr = type %d mode %o uid %d gid %d size %d", typ, mode, uid, gid, size)
+	}
+
+	d = c.call(progNFS, 6, func(e *encoder) {
+		e.opaque(file)
+		e.uint64(7)
+		e.uint32(100)
+	})
+	c.status(d, nfs3OK)
+	skipAttr(d)
+	d.uint32()
+	eof := d.bool()
+	if got := d.opaque(maxData); string(got) != "world" || !eof {
+		t.Errorf("read = %q (eof %v), want %q at the end of the file", got, eof, "world")
+	}
+
+	if names := c.readdir(dir); len(names) != 3 || names[2] != "file" {
+		t.Errorf("readdir = %q, want [. .. file]", names)
+	}
+
+	// the handle of a file follows it when its directory is renamed
+	d = c.call(progNFS, 14, func(e *encoder) {
+		e.opaque(rootFH)
+		e.string("dir")
+		e.opaque(rootFH)
+		e.string("renamed")
+	})
+	c.status(d, nfs3OK)
+	d = c.call(progNFS, 1, func(e *encoder) { e.opaque(file) })
+	c.status(d, nfs3OK)
+
+	d = c.call(progNFS, 13, func(e *encoder) {
+		e.opaque(rootFH)
+		e.string("renamed")
+	})
+	c.status(d, nfs3ErrNotEmpty)
+
+	renamed := c.lookup(rootFH, "renamed", nfs3OK)
+	d = c.call(progNFS, 12, func(e *encoder) {
+		e.opaque(renamed)
+		e.string("file")
+	})
+	c.status(d, nfs3OK)
+	d = c.call(progNFS, 1, func(e *encoder) { e.opaque(file) })
+	c.status(d, nfs3ErrStale)
+	d = c.call(progNFS, 1, func(e *encoder) { e.opaque([]byte("bad")) })
+	c.status(d, nfs3ErrBadHandle)
+}
+
+func TestUnavailable(t *testing.T) {
+	s, err := NewServer(os.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		name string
+		prog uint32
+		vers uint32
+		proc uint32
+		want uint32
+	}{
+		{"program", 100000, 2, 0, acceptProgUnavail},
+		{"version", progNFS, 4, 0, acceptProgMismatch},
+		{"procedure", progNFS, 3, 22, acceptProcUnavail},
+		{"garbage", progNFS, 3, 1, acceptGarbageArgs},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &encoder{}
+			for _, v := range []uint32{1, msgCall, rpcVersion, tc.prog, tc.vers, tc.proc, authNone, 0, authNone, 0} {
+				e.uint32(v)
+			}
+			reply, err := s.handle(e.Bytes())
+			if err != nil {
+				t.Fatalf("handle: %v", err)
+			}
+			d := &decoder{buf: reply}
+			d.next(12)
+			d.uint32()
+			d.opaque(maxAuth)
+			if got := d.uint32(); got != tc.want {
+				t.Errorf("accept_stat = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nfs is an in-process NFSv3 server (RFC 1813) sharing a directory of the host with the guest,
+// with the MOUNT protocol served on the same port so that no portmapper is needed.
+package nfs
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// ONC RPC (RFC 5531) constants
+const (
+	rpcVersion = 2
+
+	msgCall  = 0
+	msgReply = 1
+
+	replyAccepted = 0
+	replyDenied   = 1
+	rejectVersion = 0
+
+	acceptSuccess      = 0
+	acceptProgUnavail  = 1
+	acceptProgMismatch = 2
+	acceptProcUnavail  = 3
+	acceptGarbageArgs  = 4
+
+	authNone = 0
+	authUnix = 1
+
+	progNFS   = 100003
+	progMount = 100005
+	nfsVers   = 3
+	mountVers = 3
+
+	// lastFragment marks the last fragment of a record
+	lastFragment = 1 << 31
+	// maxRecord bounds the size of calls, which carry at most maxData bytes of data
+	maxRecord = maxData + 64*1024
+	// maxAuth is the maximum size of the body of credentials and verifiers
+	maxAuth = 400
+)
+
+// errGarbage is returned by procedures whose arguments can't be decoded
+var errGarbage = errors.New("garbage arguments")
+
+// procedure decodes the arguments of a call, and encodes its results
+type procedure func(s *Server, d *decoder, e *encoder) error
+
+// Server is an NFSv3 server sharing a directory
+type Server struct {
+	// root is the shared directory
+	root string
+	// uid and gid are reported as the owner of every file
+	uid uint32
+	gid uint32
+
+	handles  *handles
+	verifier [8]byte
+}
+
+// NewServer returns a server sharing root, whose files appear to be owned by uid and gid
+func NewServer(root string, uid uint32, gid uint32) (*Server, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, errors.Errorf("%s is not a directory", root)
+	}
+
+	s := &Server{root: root, uid: uid, gid: gid, handles: newHandles(root)}
+	// the verifier changes when the server restarts, so that clients resend uncommitted writes
+	binary.BigEndian.PutUint64(s.verifier[:], uint64(time.Now().UnixNano()))
+	return s, nil
+}
+
+// ListenAndServe serves NFS and MOUNT calls over TCP on addr
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "listen on %s", addr)
+	}
+	return s.Serve(l)
+}
+
+// Serve serves NFS and MOUNT calls on the connections of a listener
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(c)
+	}
+}
+
+func (s *Server) serveConn(c net.Conn) {
+	defer c.Close()
+	glog.Infof("nfs: connection from %s", c.RemoteAddr())
+	for {
+		call, err := readRecord(c)
+		if err != nil {
+			if err != io.EOF {
+				glog.Warningf("nfs: reading from %s: %v", c.RemoteAddr(), err)
+			}
+			return
+		}
+		reply, err := s.handle(call)
+		if err != nil {
+			glog.Warningf("nfs: bad call from %s: %v", c.RemoteAddr(), err)
+			return
+		}
+		if err := writeRecord(c, reply); err != nil {
+			glog.Warningf("nfs: writing to %s: %v", c.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// readRecord reads a record made of fragments, as RPC messages are framed over TCP
+func readRecord(r io.Reader) ([]byte, error) {
+	var rec []byte
+	for {
+		var h [4]byte
+		if _, err := io.ReadFull(r, h[:]); err != nil {
+			return nil, err
+		}
+		header := binary.BigEndian.Uint32(h[:])
+		n := int(header &^ lastFragment)
+		if len(rec)+n > maxRecord {
+			return nil, errors.Errorf("record exceeds %d bytes", maxRecord)
+		}
+		frag := make([]byte, n)
+		if _, err := io.ReadFull(r, frag); err != nil {
+			return nil, err
+		}
+		rec = append(rec, frag...)
+		if header&lastFragment != 0 {
+			return rec, nil
+		}
+	}
+}
+
+// writeRecord writes a message as a single fragment
+func writeRecord(w io.Writer, msg []byte) error {
+	var h [4]byte
+	binary.BigEndian.PutUint32(h[:], uint32(len(msg))|lastFragment)
+	_, err := w.Write(append(h[:], msg...))
+	return err
+}
+
+// handle processes an RPC call, and returns its reply
+func (s *Server) handle(call []byte) ([]byte, error) {
+	d := &decoder{buf: call}
+	xid := d.uint32()
+	if typ := d.uint32(); d.err == nil && typ != msgCall {
+		return nil, errors.Errorf("message type %d is not a call", typ)
+	}
+	rpcvers := d.uint32()
+	prog := d.uint32()
+	vers := d.uint32()
+	proc := d.uint32()
+	// credentials and verifier: the server trusts its only client, the guest
+	d.uint32()
+	d.opaque(maxAuth)
+	d.uint32()
+	d.opaque(maxAuth)
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	e := &encoder{}
+	e.uint32(xid)
+	e.uint32(msgReply)
+	if rpcvers != rpcVersion {
+		e.uint32(replyDenied)
+		e.uint32(rejectVersion)
+		e.uint32(rpcVersion)
+		e.uint32(rpcVersion)
+		return e.Bytes(), nil
+	}
+	e.uint32(replyAccepted)
+	e.uint32(authNone)
+	e.uint32(0)
+
+	var procs []procedure
+	switch {
+	case prog == progNFS && vers == nfsVers:
+		procs = nfsProcedures
+	case prog == progMount && vers == mountVers:
+		procs = mountProcedures
+	case prog == progNFS || prog == progMount:
+		e.uint32(acceptProgMismatch)
+		e.uint32(3)
+		e.uint32(3)
+		return e.Bytes(), nil
+	default:
+		e.uint32(acceptProgUnavail)
+		return e.Bytes(), nil
+	}
+	if int(proc) >= len(procs) || procs[proc] == nil {
+		e.uint32(acceptProcUnavail)
+		return e.Bytes(), nil
+	}
+
+	header := e.Len()
+	e.uint32(acceptSuccess)
+	if err := procs[proc](s, d, e); err != nil {
+		glog.Warningf("nfs: program %d procedure %d: %v", prog, proc, err)
+		e.Truncate(header)
+		e.uint32(acceptGarbageArgs)
+	}
+	return e.Bytes(), nil
+}
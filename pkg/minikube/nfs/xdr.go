@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// errShort is returned when a message ends before all of its fields were read
+var errShort = errors.New("short message")
+
+// decoder reads XDR (RFC 4506) fields from a message. The first error sticks, so that a whole
+// structure can be read before checking it.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	if n < 0 || len(d.buf) < n {
+		d.err = errShort
+		return make([]byte, n)
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) uint32() uint32 {
+	return binary.BigEndian.Uint32(d.next(4))
+}
+
+func (d *decoder) uint64() uint64 {
+	return binary.BigEndian.Uint64(d.next(8))
+}
+
+func (d *decoder) bool() bool {
+	return d.uint32() != 0
+}
+
+// fixed reads fixed-length opaque data
+func (d *decoder) fixed(n int) []byte {
+	b := d.next(n)
+	d.next(pad(n))
+	return b
+}
+
+// opaque reads variable-length opaque data, of at most max bytes
+func (d *decoder) opaque(max int) []byte {
+	n := int(d.uint32())
+	if d.err == nil && n > max {
+		d.err = errors.Errorf("opaque of %d bytes exceeds %d", n, max)
+		return nil
+	}
+	return d.fixed(n)
+}
+
+func (d *decoder) string(max int) string {
+	return string(d.opaque(max))
+}
+
+// encoder writes XDR fields
+type encoder struct {
+	bytes.Buffer
+}
+
+func (e *encoder) uint32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	e.Write(b[:])
+}
+
+func (e *encoder) uint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.Write(b[:])
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.uint32(1)
+	} else {
+		e.uint32(0)
+	}
+}
+
+// fixed writes fixed-length opaque data
+func (e *encoder) fixed(b []byte) {
+	e.Write(b)
+	e.Write(make([]byte, pad(len(b))))
+}
+
+// opaque writes variable-length opaque data
+func (e *encoder) opaque(b []byte) {
+	e.uint32(uint32(len(b)))
+	e.fixed(b)
+}
+
+func (e *encoder) string(s string) {
+	e.opaque([]byte(s))
+}
+
+// pad returns the number of bytes aligning n bytes to 4
+func pad(n int) int {
+	return (4 - n%4) % 4
+}
@@ -35,6 +35,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 )
 
 const (
@@ -105,6 +106,7 @@ func beginDownloadKicArtifacts(g *errgroup.Group, cc *config.ClusterConfig) {
 	glog.Infof("Beginning downloading kic artifacts for %s with %s", cc.Driver, cc.KubernetesConfig.ContainerRuntime)
 	if cc.Driver == "docker" {
 		if !image.ExistsImageInDaemon(cc.KicBaseImage) {
+			register.Reg.SetStep(register.PullingBaseImage)
 			out.T(out.Pulling, "Pulling base image ...")
 			g.Go(func() error {
 				// TODO #8004 : make base-image respect --image-repository
@@ -26,6 +26,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
@@ -46,8 +47,8 @@ func showVersionInfo(k8sVersion string, cr cruntime.Manager) {
 	}
 }
 
-// configureMounts configures any requested filesystem mounts
-func configureMounts(wg *sync.WaitGroup) {
+// configureMounts configures any requested filesystem mounts, with the mount type that works best with the driver
+func configureMounts(wg *sync.WaitGroup, cc config.ClusterConfig) {
 	wg.Add(1)
 	defer wg.Done()
 
@@ -61,7 +62,11 @@ func configureMounts(wg *sync.WaitGroup) {
 	if glog.V(8) {
 		mountDebugVal = 1
 	}
-	mountCmd := exec.Command(path, "mount", fmt.Sprintf("--v=%d", mountDebugVal), viper.GetString(mountString))
+	typ := viper.GetString(mountType)
+	if typ == "" {
+		typ = cluster.DefaultMountType(cc.Driver)
+	}
+	mountCmd := exec.Command(path, "mount", fmt.Sprintf("--profile=%s", cc.Name), fmt.Sprintf("--v=%d", mountDebugVal), fmt.Sprintf("--type=%s", typ), viper.GetString(mountString))
 	mountCmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
 	if glog.V(8) {
 		mountCmd.Stdout = os.Stdout
@@ -70,7 +75,7 @@ func configureMounts(wg *sync.WaitGroup) {
 	if err := mountCmd.Start(); err != nil {
 		exit.WithError("Error starting mount", err)
 	}
-	if err := lock.WriteFile(filepath.Join(localpath.MiniPath(), constants.MountProcessFileName), []byte(strconv.Itoa(mountCmd.Process.Pid)), 0644); err != nil {
+	if err := lock.WriteFile(filepath.Join(localpath.Profile(cc.Name), constants.MountProcessFileName), []byte(strconv.Itoa(mountCmd.Process.Pid)), 0644); err != nil {
 		exit.WithError("Error writing mount pid", err)
 	}
 }
@@ -19,10 +19,14 @@ package node
 import (
 	"fmt"
 
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -31,6 +35,7 @@ import (
 // TODO: Share these between cluster and node packages
 const (
 	mountString = "mount-string"
+	mountType   = "mount-type"
 	createMount = "mount"
 )
 
@@ -70,6 +75,18 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		return n, err
 	}
 
+	if n.ControlPlane {
+		Leave(api, cc, *n)
+	}
+
+	// Remove the node from the cluster, so that its etcd member and node object do not linger after the machine is gone
+	cpBs, err := cluster.ControlPlaneBootstrapper(api, &cc, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		glog.Warningf("unable to get control plane bootstrapper: %v", err)
+	} else if err := cpBs.RemoveNode(cc, *n); err != nil {
+		glog.Warningf("unable to remove node %s from the cluster: %v", n.Name, err)
+	}
+
 	err = machine.DeleteHost(api, driver.MachineName(cc, *n))
 	if err != nil {
 		return n, err
@@ -79,6 +96,34 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 	return n, config.SaveProfile(viper.GetString(config.ProfileName), &cc)
 }
 
+// Leave makes a best effort to reset kubeadm on a secondary control plane, so that it leaves the etcd cluster
+// without breaking its quorum. A machine which is not running has nothing to reset.
+func Leave(api libmachine.API, cc config.ClusterConfig, n config.Node) {
+	name := driver.MachineName(cc, n)
+	h, err := machine.LoadHost(api, name)
+	if err != nil {
+		glog.Warningf("unable to load host %s: %v", name, err)
+		return
+	}
+	if s, err := h.Driver.GetState(); err != nil || s != state.Running {
+		glog.Infof("%s is not running, skipping kubeadm reset", name)
+		return
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		glog.Warningf("unable to get command runner for %s: %v", name, err)
+		return
+	}
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), cc, r)
+	if err != nil {
+		glog.Warningf("unable to get bootstrapper for %s: %v", name, err)
+		return
+	}
+	if err := bs.DeleteCluster(cc.KubernetesConfig); err != nil {
+		glog.Warningf("unable to reset %s: %v", name, err)
+	}
+}
+
 // Retrieve finds the node by name in the given cluster
 func Retrieve(cc config.ClusterConfig, name string) (*config.Node, int, error) {
 
@@ -26,6 +26,7 @@ import (
 
 	"github.com/golang/glog"
 	isatty "github.com/mattn/go-isatty"
+	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/translate"
 )
 
@@ -50,6 +51,8 @@ var (
 	useColor = false
 	// OverrideEnv is the environment variable used to override color/emoji usage
 	OverrideEnv = "MINIKUBE_IN_STYLE"
+	// JSON is whether or not output should be printed as JSON events, updated by SetJSON.
+	JSON = false
 )
 
 // MaxLogEntries controls the number of log entries to show for each source
@@ -66,6 +69,10 @@ type V map[string]interface{}
 
 // T writes a stylized and templated message to stdout
 func T(style StyleEnum, format string, a ...V) {
+	if JSON {
+		printJSON(style, Message(format, a...), false)
+		return
+	}
 	outStyled := ApplyTemplateFormatting(style, useColor, format, a...)
 	String(outStyled)
 }
@@ -75,6 +82,10 @@ func String(format string, a ...interface{}) {
 	// Flush log buffer so that output order makes sense
 	glog.Flush()
 
+	if JSON {
+		printJSON(Empty, strings.TrimSpace(fmt.Sprintf(format, a...)), false)
+		return
+	}
 	if outFile == nil {
 		glog.Warningf("[unset outFile]: %s", fmt.Sprintf(format, a...))
 		return
@@ -92,12 +103,20 @@ func Ln(format string, a ...interface{}) {
 
 // ErrT writes a stylized and templated error message to stderr
 func ErrT(style StyleEnum, format string, a ...V) {
+	if JSON {
+		printJSON(style, Message(format, a...), true)
+		return
+	}
 	errStyled := ApplyTemplateFormatting(style, useColor, format, a...)
 	Err(errStyled)
 }
 
 // Err writes a basic formatted string to stderr
 func Err(format string, a ...interface{}) {
+	if JSON {
+		printJSON(Empty, strings.TrimSpace(fmt.Sprintf(format, a...)), true)
+		return
+	}
 	if errFile == nil {
 		glog.Errorf("[unset errFile]: %s", fmt.Sprintf(format, a...))
 		return
@@ -133,6 +152,42 @@ func FailureT(format string, a ...V) {
 	ErrT(FailureType, format, a...)
 }
 
+// Message returns a templated message without any style prefix or trailing newline
+func Message(format string, a ...V) string {
+	msg := ApplyTemplateFormatting(Empty, true, format, a...)
+	return strings.TrimSpace(strings.Replace(msg, "%%", "%", -1))
+}
+
+// printJSON prints a templated message as a JSON event. Messages are mapped to event
+// types by their style, so that every call site produces a stable event type.
+func printJSON(style StyleEnum, msg string, toErr bool) {
+	if msg == "" {
+		return
+	}
+	switch style {
+	case Warning, Conflict:
+		register.PrintWarning(msg)
+	case FailureType, FatalType:
+		register.PrintError(msg)
+	case Option, Command, Issue, LogEntry, Empty:
+		register.PrintInfo(msg)
+	default:
+		if toErr {
+			register.PrintInfo(msg)
+			return
+		}
+		register.PrintStep(msg)
+	}
+}
+
+// SetJSON configures whether output is printed as one JSON event per line, rather than styled text.
+func SetJSON(enabled bool) {
+	JSON = enabled
+	if enabled && outFile != nil {
+		register.SetOutputFile(outFile)
+	}
+}
+
 // SetOutFile configures which writer standard output goes to.
 func SetOutFile(w fdWriter) {
 	glog.Infof("Setting OutFile to fd %d ...", w.Fd())
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package register
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pborman/uuid"
+)
+
+const (
+	specVersion     = "1.0"
+	source          = "https://minikube.sigs.k8s.io/"
+	dataContentType = "application/json"
+)
+
+// Event types, which are stable and may be relied upon by consumers of the JSON output
+const (
+	StepType             = "io.k8s.sigs.minikube.step"
+	DownloadType         = "io.k8s.sigs.minikube.download"
+	DownloadProgressType = "io.k8s.sigs.minikube.download.progress"
+	InfoType             = "io.k8s.sigs.minikube.info"
+	WarningType          = "io.k8s.sigs.minikube.warning"
+	ErrorType            = "io.k8s.sigs.minikube.error"
+)
+
+var (
+	outputFile io.Writer = os.Stdout
+	// now is overridden by tests
+	now = time.Now
+	// newID is overridden by tests
+	newID = func() string { return uuid.New() }
+)
+
+// SetOutputFile sets the writer events are printed to
+func SetOutputFile(w io.Writer) {
+	outputFile = w
+}
+
+// event is a CloudEvents 1.0 envelope, see https://github.com/cloudevents/spec
+type event struct {
+	SpecVersion     string            `json:"specversion"`
+	ID              string            `json:"id"`
+	Source          string            `json:"source"`
+	Type            string            `json:"type"`
+	Time            string            `json:"time"`
+	DataContentType string            `json:"datacontenttype"`
+	Data            map[string]string `json:"data"`
+}
+
+// printAsCloudEvent prints the data as a single line of JSON
+func printAsCloudEvent(eventType string, data map[string]string) {
+	e := event{
+		SpecVersion:     specVersion,
+		ID:              newID(),
+		Source:          source,
+		Type:            eventType,
+		Time:            now().UTC().Format(time.RFC3339),
+		DataContentType: dataContentType,
+		Data:            data,
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		glog.Errorf("unable to marshal %s event: %v", eventType, err)
+		return
+	}
+	if _, err := fmt.Fprintln(outputFile, string(b)); err != nil {
+		glog.Errorf("unable to print %s event: %v", eventType, err)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package register
+
+import "fmt"
+
+// PrintStep prints a step type in JSON format
+func PrintStep(message string) {
+	printAsCloudEvent(StepType, map[string]string{
+		"name":        string(Reg.Step()),
+		"currentstep": Reg.currentStep(),
+		"totalsteps":  Reg.totalSteps(),
+		"message":     message,
+	})
+}
+
+// PrintInfo prints an info type in JSON format
+func PrintInfo(message string) {
+	printAsCloudEvent(InfoType, map[string]string{
+		"message": message,
+	})
+}
+
+// PrintDownload prints a download type in JSON format
+func PrintDownload(artifact string) {
+	printAsCloudEvent(DownloadType, map[string]string{
+		"currentstep": Reg.currentStep(),
+		"totalsteps":  Reg.totalSteps(),
+		"artifact":    artifact,
+	})
+}
+
+// PrintDownloadProgress prints a download progress type in JSON format
+func PrintDownloadProgress(artifact string, current, total int64) {
+	progress := ""
+	if total > 0 {
+		progress = fmt.Sprintf("%d%%", current*100/total)
+	}
+	printAsCloudEvent(DownloadProgressType, map[string]string{
+		"currentstep": Reg.currentStep(),
+		"totalsteps":  Reg.totalSteps(),
+		"artifact":    artifact,
+		"current":     fmt.Sprint(current),
+		"total":       fmt.Sprint(total),
+		"progress":    progress,
+	})
+}
+
+// PrintWarning prints a warning type in JSON format
+func PrintWarning(warning string) {
+	printAsCloudEvent(WarningType, map[string]string{
+		"message": warning,
+	})
+}
+
+// PrintError prints an error type in JSON format
+func PrintError(err string) {
+	printAsCloudEvent(ErrorType, map[string]string{
+		"message": err,
+	})
+}
+
+// PrintErrorExitCode prints an error type in JSON format, along with the exit code and any additional
+// details, such as the ID and advice of a known problem
+func PrintErrorExitCode(err string, exitcode int, additionalArgs ...map[string]string) {
+	data := map[string]string{
+		"message":  err,
+		"exitcode": fmt.Sprint(exitcode),
+	}
+	for _, a := range additionalArgs {
+		for k, v := range a {
+			data[k] = v
+		}
+	}
+	printAsCloudEvent(ErrorType, data)
+}
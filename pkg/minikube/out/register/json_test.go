@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package register
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func setUp() *bytes.Buffer {
+	buf := bytes.NewBuffer([]byte{})
+	SetOutputFile(buf)
+	now = func() time.Time { return time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC) }
+	newID = func() string { return "random-id" }
+	return buf
+}
+
+func tearDown() {
+	SetOutputFile(os.Stdout)
+	now = time.Now
+}
+
+func TestPrintStep(t *testing.T) {
+	buf := setUp()
+	defer tearDown()
+
+	Reg.SetStep(InitialSetup)
+	PrintStep("message")
+
+	want := `{"specversion":"1.0","id":"random-id","source":"https://minikube.sigs.k8s.io/","type":"io.k8s.sigs.minikube.step","time":"2020-06-01T12:00:00Z","datacontenttype":"application/json","data":{"currentstep":"0","message":"message","name":"Initial Minikube Setup","totalsteps":"11"}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("PrintStep() = %s, want %s", got, want)
+	}
+}
+
+func TestPrintDownloadProgress(t *testing.T) {
+	buf := setUp()
+	defer tearDown()
+
+	Reg.SetStep(DownloadingArtifacts)
+	PrintDownloadProgress("preload.tar.lz4", 25, 100)
+
+	want := `{"specversion":"1.0","id":"random-id","source":"https://minikube.sigs.k8s.io/","type":"io.k8s.sigs.minikube.download.progress","time":"2020-06-01T12:00:00Z","datacontenttype":"application/json","data":{"artifact":"preload.tar.lz4","current":"25","currentstep":"2","progress":"25%","total":"100","totalsteps":"11"}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("PrintDownloadProgress() = %s, want %s", got, want)
+	}
+}
+
+func TestPrintErrorExitCode(t *testing.T) {
+	buf := setUp()
+	defer tearDown()
+
+	PrintErrorExitCode("failed", 78, map[string]string{"name": "PROBLEM_ID", "advice": "do something"})
+
+	want := `{"specversion":"1.0","id":"random-id","source":"https://minikube.sigs.k8s.io/","type":"io.k8s.sigs.minikube.error","time":"2020-06-01T12:00:00Z","datacontenttype":"application/json","data":{"advice":"do something","exitcode":"78","message":"failed","name":"PROBLEM_ID"}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("PrintErrorExitCode() = %s, want %s", got, want)
+	}
+}
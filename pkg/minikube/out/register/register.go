@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package register contains all the logic to print out `minikube start` in JSON
+package register
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RegStep is a step in the `minikube start` process
+type RegStep string
+
+// These are the steps of `minikube start`, in the order they are expected to happen
+const (
+	InitialSetup         RegStep = "Initial Minikube Setup"
+	SelectingDriver      RegStep = "Selecting Driver"
+	DownloadingArtifacts RegStep = "Downloading Artifacts"
+	StartingNode         RegStep = "Starting Node"
+	PullingBaseImage     RegStep = "Pulling Base Image"
+	RunningLocalhost     RegStep = "Running on Localhost"
+	CreatingContainer    RegStep = "Creating Container"
+	CreatingVM           RegStep = "Creating VM"
+	PreparingKubernetes  RegStep = "Preparing Kubernetes"
+	VerifyingKubernetes  RegStep = "Verifying Kubernetes"
+	EnablingAddons       RegStep = "Enabling Addons"
+	Done                 RegStep = "Done"
+)
+
+// Register holds the current step of `minikube start`
+type Register struct {
+	lock    sync.Mutex
+	steps   []RegStep
+	current RegStep
+}
+
+// Reg is the global register used by minikube start
+var Reg = Register{
+	steps: []RegStep{
+		InitialSetup,
+		SelectingDriver,
+		DownloadingArtifacts,
+		StartingNode,
+		PullingBaseImage,
+		RunningLocalhost,
+		CreatingContainer,
+		CreatingVM,
+		PreparingKubernetes,
+		VerifyingKubernetes,
+		EnablingAddons,
+		Done,
+	},
+	current: InitialSetup,
+}
+
+// SetStep sets the current step
+func (r *Register) SetStep(s RegStep) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.current = s
+}
+
+// Step returns the name of the current step
+func (r *Register) Step() RegStep {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.current
+}
+
+// currentStep returns the index of the current step, as a string
+func (r *Register) currentStep() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i, s := range r.steps {
+		if s == r.current {
+			return fmt.Sprint(i)
+		}
+	}
+	return ""
+}
+
+// totalSteps returns the number of steps, as a string
+func (r *Register) totalSteps() string {
+	return fmt.Sprint(len(r.steps) - 1)
+}
@@ -92,6 +92,7 @@ var styles = map[StyleEnum]style{
 	AddonEnable:      {Prefix: "🌟  "},
 	Caching:          {Prefix: "🤹  "},
 	Celebrate:        {Prefix: "🎉  "},
+	CNI:              {Prefix: "🔗  "},
 	Connectivity:     {Prefix: "📶  "},
 	Containerd:       {Prefix: "📦  "},
 	ContainerRuntime: {Prefix: "🎁  "},
@@ -27,6 +27,7 @@ const (
 	Celebrate
 	Celebration
 	Check
+	CNI
 	Command
 	Conflict
 	Confused
@@ -0,0 +1,295 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package problem
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util/lock"
+	"k8s.io/minikube/pkg/version"
+	"sigs.k8s.io/yaml"
+)
+
+// BuiltIn is the source of the problems compiled into minikube
+const BuiltIn = "built-in"
+
+// urlCacheExpiry is how long problems downloaded from a URL are used before they are downloaded again
+const urlCacheExpiry = 24 * time.Hour
+
+// Definition is a known problem, and where it is defined
+type Definition struct {
+	ID string
+	// Source is the file or URL the problem is defined in, or BuiltIn
+	Source string
+	match
+}
+
+// entry is a known problem, as defined in a problem file
+type entry struct {
+	ID     string   `json:"id"`
+	Regexp string   `json:"regexp"`
+	Advice string   `json:"advice"`
+	URL    string   `json:"url,omitempty"`
+	GOOS   []string `json:"goos,omitempty"`
+	Probes []string `json:"probes,omitempty"`
+}
+
+var (
+	loadOnce sync.Once
+	loaded   []Definition
+	loadErr  error
+)
+
+// Definitions returns the known problems: those added by the user, followed by the built-in ones
+// that they do not replace. The error lists the problem files that could not be loaded.
+func Definitions() ([]Definition, error) {
+	loadOnce.Do(func() {
+		user, err := LoadUser()
+		loaded, loadErr = merge(user, builtins()), err
+	})
+	return loaded, loadErr
+}
+
+// Matches returns the known problems that match an error message on an OS, the one that applies first.
+// Problems specific to the OS take precedence over those of the user, which take precedence over the built-in ones.
+func Matches(msg string, goos string) []Definition {
+	defs, err := Definitions()
+	if err != nil {
+		glog.Warningf("loading problems: %v", err)
+	}
+	return matches(defs, msg, goos)
+}
+
+// matches returns the problems that match an error message on an OS, those specific to the OS first
+func matches(defs []Definition, msg string, goos string) []Definition {
+	osMatches := []Definition{}
+	genericMatches := []Definition{}
+	for _, d := range defs {
+		if !d.Regexp.MatchString(msg) {
+			continue
+		}
+
+		if len(d.GOOS) == 0 {
+			genericMatches = append(genericMatches, d)
+			continue
+		}
+		for _, o := range d.GOOS {
+			if o == goos {
+				osMatches = append(osMatches, d)
+				break
+			}
+		}
+	}
+	return append(osMatches, genericMatches...)
+}
+
+// LoadUser loads the problems added by the user: those of the YAML files in the problems.d directory
+// of the minikube home, followed by those at the URL of the problems-url setting
+func LoadUser() ([]Definition, error) {
+	defs, err := LoadDir(localpath.MakeMiniPath("problems.d"))
+	failed := []string{}
+	if err != nil {
+		failed = append(failed, err.Error())
+	}
+
+	if url := viper.GetString(config.ProblemsURL); url != "" {
+		ds, err := LoadURL(url, localpath.MakeMiniPath("cache", "problems"))
+		if err != nil {
+			failed = append(failed, err.Error())
+		}
+		defs = append(defs, ds...)
+	}
+
+	if len(failed) > 0 {
+		return defs, errors.New(strings.Join(failed, "; "))
+	}
+	return defs, nil
+}
+
+// LoadDir loads the problems of the YAML files in a directory, skipping the files that are invalid.
+// The error lists the files which could not be loaded.
+func LoadDir(dir string) ([]Definition, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defs := []Definition{}
+	failed := []string{}
+	for _, fi := range fis {
+		ext := filepath.Ext(fi.Name())
+		if fi.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		p := filepath.Join(dir, fi.Name())
+		ds, err := LoadFile(p)
+		if err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		defs = append(defs, ds...)
+	}
+
+	if len(failed) > 0 {
+		return defs, fmt.Errorf("invalid problem files: %s", strings.Join(failed, "; "))
+	}
+	return defs, nil
+}
+
+// LoadFile loads the problems of a YAML file
+func LoadFile(path string) ([]Definition, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data, path)
+}
+
+// LoadURL loads the problems of a YAML file at a URL. The file is cached in a directory, and downloaded
+// again once the cache expires. If it can not be downloaded, the expired cache is used.
+func LoadURL(url string, cacheDir string) ([]Definition, error) {
+	cache := filepath.Join(cacheDir, fmt.Sprintf("%x.yaml", sha256.Sum256([]byte(url))))
+	if fi, err := os.Stat(cache); err == nil && time.Since(fi.ModTime()) < urlCacheExpiry {
+		data, err := ioutil.ReadFile(cache)
+		if err == nil {
+			return parse(data, url)
+		}
+		glog.Warningf("reading cached problems of %s: %v", url, err)
+	}
+
+	data, err := download(url)
+	if err != nil {
+		glog.Warningf("downloading problems: %v", err)
+		stale, rerr := ioutil.ReadFile(cache)
+		if rerr != nil {
+			return nil, err
+		}
+		return parse(stale, url)
+	}
+
+	defs, err := parse(data, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		glog.Warningf("creating %s: %v", cacheDir, err)
+	} else if err := lock.WriteFile(cache, data, 0644); err != nil {
+		glog.Warningf("caching problems of %s: %v", url, err)
+	}
+	return defs, nil
+}
+
+// download returns the contents at a URL
+func download(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating new http request")
+	}
+	req.Header.Set("User-Agent", fmt.Sprintf("Minikube/%s Minikube-OS/%s", version.GetVersion(), runtime.GOOS))
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error with http GET for endpoint %s", url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http GET for endpoint %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// parse returns the problems of a YAML file, which is a list of entries
+func parse(data []byte, source string) ([]Definition, error) {
+	es := []entry{}
+	if err := yaml.UnmarshalStrict(data, &es); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", source)
+	}
+
+	defs := []Definition{}
+	for i, e := range es {
+		if e.ID == "" || e.Regexp == "" || e.Advice == "" {
+			return nil, fmt.Errorf("%s: problem %d must have an id, a regexp and advice", source, i+1)
+		}
+		r, err := regexp.Compile(e.Regexp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: problem %s", source, e.ID)
+		}
+		defs = append(defs, Definition{
+			ID:     e.ID,
+			Source: source,
+			match:  match{Regexp: r, Advice: e.Advice, URL: e.URL, GOOS: e.GOOS, Probes: e.Probes},
+		})
+	}
+	return defs, nil
+}
+
+// builtins returns the problems compiled into minikube, sorted by ID
+func builtins() []Definition {
+	maps := []map[string]match{
+		osProblems,
+		vmProblems,
+		netProblems,
+		deployProblems,
+		stateProblems,
+	}
+
+	defs := []Definition{}
+	for _, m := range maps {
+		for id, mt := range m {
+			defs = append(defs, Definition{ID: id, Source: BuiltIn, match: mt})
+		}
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].ID < defs[j].ID })
+	return defs
+}
+
+// merge returns the problems of the user, followed by the built-in ones they do not replace.
+// The first problem of the user with an ID replaces the others.
+func merge(user []Definition, builtin []Definition) []Definition {
+	seen := map[string]bool{}
+	defs := []Definition{}
+	for _, d := range append(user, builtin...) {
+		if seen[d.ID] {
+			glog.Infof("problem %s of %s is replaced", d.ID, d.Source)
+			continue
+		}
+		seen[d.ID] = true
+		defs = append(defs, d)
+	}
+	return defs
+}
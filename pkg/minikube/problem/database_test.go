@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package problem
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const proxyProblems = `
+- id: CORP_PROXY_AUTH
+  regexp: 'proxyconnect tcp: .*407'
+  advice: Sign in to the proxy
+  url: https://wiki.example.com/proxy
+- id: CORP_VPN
+  regexp: 'i/o timeout'
+  advice: Disconnect from the VPN
+  goos: [darwin]
+  probes: [dns]
+`
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		description string
+		data        string
+		want        []string
+		err         string
+	}{
+		{"valid", proxyProblems, []string{"CORP_PROXY_AUTH", "CORP_VPN"}, ""},
+		{"empty", "", []string{}, ""},
+		{"no advice", "- id: X\n  regexp: x\n", nil, "must have an id, a regexp and advice"},
+		{"invalid regexp", "- id: X\n  regexp: '('\n  advice: x\n", nil, "problem X"},
+		{"unknown field", "- id: X\n  regexp: x\n  advice: x\n  issues: [1]\n", nil, "unknown field"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			defs, err := parse([]byte(tc.data), "test.yaml")
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("parse() error = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse(): %v", err)
+			}
+			ids := []string{}
+			for _, d := range defs {
+				ids = append(ids, d.ID)
+				if d.Source != "test.yaml" {
+					t.Errorf("%s source = %q, want test.yaml", d.ID, d.Source)
+				}
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("parse() = %v, want %v", ids, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"proxy.yaml": proxyProblems,
+		"bad.yml":    "- id: [",
+		"README.md":  "not a problem file",
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defs, err := LoadDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "bad.yml") {
+		t.Errorf("LoadDir() error = %v, want bad.yml to be invalid", err)
+	}
+	if len(defs) != 2 {
+		t.Errorf("LoadDir() = %d problems, want 2", len(defs))
+	}
+
+	if defs, err := LoadDir(filepath.Join(dir, "missing")); err != nil || len(defs) != 0 {
+		t.Errorf("LoadDir(missing) = %v, %v, want nothing", defs, err)
+	}
+}
+
+func TestLoadURL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(proxyProblems))
+	}))
+	defer srv.Close()
+
+	load := func() {
+		t.Helper()
+		defs, err := LoadURL(srv.URL, dir)
+		if err != nil || len(defs) != 2 || defs[0].Source != srv.URL {
+			t.Fatalf("LoadURL() = %+v, %v, want 2 problems of %s", defs, err, srv.URL)
+		}
+	}
+
+	// downloaded, then cached
+	load()
+	load()
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+
+	// once the cache expires, downloaded again, or the expired cache is used
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil || len(fis) != 1 {
+		t.Fatalf("cache = %v, %v, want a file", fis, err)
+	}
+	expired := time.Now().Add(-2 * urlCacheExpiry)
+	if err := os.Chtimes(filepath.Join(dir, fis[0].Name()), expired, expired); err != nil {
+		t.Fatal(err)
+	}
+	up = false
+	load()
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+
+	if _, err := LoadURL(srv.URL+"/other", dir); err == nil {
+		t.Errorf("LoadURL() of an uncached URL that is down did not fail")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	user, err := parse([]byte(proxyProblems+`
+- id: DOWNLOAD_BLOCKED
+  regexp: 'failed to download'
+  advice: Use the mirror
+`), "user.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs := merge(user, builtins())
+
+	var tests = []struct {
+		msg  string
+		goos string
+		want []string
+	}{
+		{"proxyconnect tcp: 407 Proxy Authentication Required", "linux", []string{"CORP_PROXY_AUTH"}},
+		{"dial tcp: i/o timeout", "linux", []string{}},
+		{"dial tcp: i/o timeout", "darwin", []string{"CORP_VPN"}},
+		{"failed to download", "linux", []string{"DOWNLOAD_BLOCKED"}},
+		{"this is just a lame error message with no matches.", "linux", []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.msg, func(t *testing.T) {
+			ids := []string{}
+			for _, d := range matches(defs, tc.msg, tc.goos) {
+				ids = append(ids, d.ID)
+				if d.ID == "DOWNLOAD_BLOCKED" && d.Source != "user.yaml" {
+					t.Errorf("DOWNLOAD_BLOCKED is from %s, want it replaced by user.yaml", d.Source)
+				}
+				if d.ID == "CORP_VPN" && strings.Join(d.Probes, ",") != "dns" {
+					t.Errorf("CORP_VPN probes = %v, want [dns]", d.Probes)
+				}
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("matches(%q, %s) = %v, want %v", tc.msg, tc.goos, ids, tc.want)
+			}
+		})
+	}
+}
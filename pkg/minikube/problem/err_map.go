@@ -85,6 +85,12 @@ var vmProblems = map[string]match{
 		Regexp: re(`mkdir /var/lib/docker/volumes.*: read-only file system`),
 		Advice: "Restart Docker",
 		Issues: []int{6825},
+		Probes: []string{"docker-daemon", "disk-space"},
+	},
+	"DOCKER_NAME_CONFLICT": {
+		Regexp: re(`container name ".*" is already in use by container`),
+		Advice: "A container of a deleted cluster was left behind. Run 'minikube delete' to remove it",
+		Probes: []string{"stale-containers"},
 	},
 	"DOCKER_CHROMEOS": {
 		Regexp: re(`Container.*is not running.*chown docker:docker`),
@@ -230,6 +236,7 @@ var vmProblems = map[string]match{
 		Advice: "Check that SELinux is disabled, and that the provided apiserver flags are valid",
 		Issues: []int{6014, 4536},
 		GOOS:   []string{"linux"},
+		Probes: []string{"disk-space", "cgroup-driver"},
 	},
 	"NONE_DOCKER_EXIT_1": {
 		Regexp: re(`sudo systemctl start docker: exit status 1`),
@@ -237,6 +244,7 @@ var vmProblems = map[string]match{
 		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/none",
 		Issues: []int{4498},
 		GOOS:   []string{"linux"},
+		Probes: []string{"docker-daemon"},
 	},
 	"NONE_DOCKER_EXIT_5": {
 		Regexp: re(`sudo systemctl start docker: exit status 5`),
@@ -244,6 +252,7 @@ var vmProblems = map[string]match{
 		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/none",
 		Issues: []int{5532},
 		GOOS:   []string{"linux"},
+		Probes: []string{"docker-daemon"},
 	},
 	"NONE_CRIO_EXIT_5": {
 		Regexp: re(`sudo systemctl restart crio: exit status 5`),
@@ -257,12 +266,14 @@ var vmProblems = map[string]match{
 		Advice: "kubeadm detected a TCP port conflict with another process: probably another local Kubernetes installation. Run lsof -p<port> to find the process and kill it",
 		Issues: []int{5484},
 		GOOS:   []string{"linux"},
+		Probes: []string{"port-free"},
 	},
 	"NONE_KUBELET": {
 		Regexp: re(`The kubelet is not running`),
 		Advice: "Check output of 'journalctl -xeu kubelet', try passing --extra-config=kubelet.cgroup-driver=systemd to minikube start",
 		Issues: []int{4172},
 		GOOS:   []string{"linux"},
+		Probes: []string{"cgroup-driver", "disk-space"},
 	},
 	"NONE_DEFAULT_ROUTE": {
 		Regexp: re(`(No|from) default routes`),
@@ -377,6 +388,7 @@ var netProblems = map[string]match{
 		Advice: "minikube is unable to access the Google Container Registry. You may need to configure it to use a HTTP proxy.",
 		URL:    proxyDoc,
 		Issues: []int{3860},
+		Probes: []string{"dns"},
 	},
 	"DOWNLOAD_RESET_BY_PEER": {
 		Regexp: re(`Error downloading .*connection reset by peer`),
@@ -407,6 +419,7 @@ var netProblems = map[string]match{
 		Advice: "A firewall is blocking Docker the minikube VM from reaching the image repository. You may need to select --image-repository, or use a proxy.",
 		URL:    proxyDoc,
 		Issues: []int{3898, 6070},
+		Probes: []string{"dns"},
 	},
 	"SSH_AUTH_FAILURE": {
 		Regexp: re(`ssh: handshake failed: ssh: unable to authenticate.*, no supported methods remain`),
@@ -424,6 +437,7 @@ var netProblems = map[string]match{
 		Regexp: re(`dial tcp: lookup.*: no such host`),
 		Advice: "Verify that your HTTP_PROXY and HTTPS_PROXY environment variables are set correctly.",
 		URL:    proxyDoc,
+		Probes: []string{"dns"},
 	},
 	"HOST_CIDR_CONFLICT": {
 		Regexp: re(`host-only cidr conflicts with the network address of a host interface`),
@@ -450,6 +464,7 @@ var deployProblems = map[string]match{
 		Regexp: re(`Error configuring auth on host: OS type not recognized`),
 		Advice: "Docker inside the VM is unavailable. Try running 'minikube delete' to reset the VM.",
 		Issues: []int{3952},
+		Probes: []string{"docker-daemon"},
 	},
 	"INVALID_KUBERNETES_VERSION": {
 		Regexp: re(`No Major.Minor.Patch elements found`),
@@ -463,12 +478,14 @@ var deployProblems = map[string]match{
 		Regexp: re(`apiserver process never appeared`),
 		Advice: "Check that the provided apiserver flags are valid, and that SELinux is disabled",
 		Issues: []int{4536, 6014},
+		Probes: []string{"disk-space", "cgroup-driver"},
 	},
 	"APISERVER_TIMEOUT": {
 		Regexp: re(`apiserver: timed out waiting for the condition`),
 		Advice: "A VPN or firewall is interfering with HTTP access to the minikube VM. Alternatively, try a different VM driver: https://minikube.sigs.k8s.io/docs/start/",
 		URL:    vpnDoc,
 		Issues: []int{4302},
+		Probes: []string{"disk-space"},
 	},
 	"DNS_TIMEOUT": {
 		Regexp: re(`dns: timed out waiting for the condition`),
@@ -509,6 +526,7 @@ var deployProblems = map[string]match{
 		Regexp: re(`systemctl -f restart docker`),
 		Advice: "Remove the incompatible --docker-opt flag if one was provided",
 		Issues: []int{7070},
+		Probes: []string{"docker-daemon"},
 	},
 	"WAITING_FOR_SSH": {
 		Regexp: re(`waiting for SSH to be available`),
@@ -529,6 +547,7 @@ var osProblems = map[string]match{
 		Advice: "If using the none driver, ensure that systemctl is installed",
 		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/none/",
 		Issues: []int{2704},
+		Probes: []string{"docker-daemon"},
 	},
 	"KUBECONFIG_WRITE_FAIL": {
 		Regexp: re(`Failed to setup kubeconfig: writing kubeconfig`),
@@ -555,6 +574,7 @@ var stateProblems = map[string]match{
 		Regexp: re(`machine does not exist`),
 		Advice: "Run 'minikube delete' to delete the stale VM, or and ensure that minikube is running as the same user you are issuing this command with",
 		Issues: []int{3864, 6087},
+		Probes: []string{"stale-containers"},
 	},
 	"MACHINE_NOT_FOUND": {
 		Regexp: re(`Machine does not exist for api.Exists`),
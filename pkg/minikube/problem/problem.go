@@ -41,6 +41,8 @@ type Problem struct {
 	Issues []int
 	// Hide the new issue link: it isn't our problem, and we won't be able to suggest additional assistance.
 	ShowIssueLink bool
+	// Probes are the names of the diagnostic probes to run for this problem
+	Probes []string
 }
 
 // match maps a regular expression to problem metadata.
@@ -53,6 +55,8 @@ type match struct {
 	GOOS []string
 	// Hide the new issue link: it isn't our problem, and we won't be able to suggest additional assistance.
 	ShowIssueLink bool
+	// Probes are the names of the diagnostic probes which find the cause of this problem, see the triage package.
+	Probes []string
 }
 
 // Display problem metadata to the console
@@ -66,7 +70,7 @@ func (p *Problem) Display() {
 	}
 
 	if len(p.Issues) == 1 {
-		out.ErrT(out.Issues, "Related issue: {{.url}}", out.V{"url": fmt.Sprintf("%s/%d", issueBase, p.Issues[0])})
+		out.ErrT(out.Issues, "Related issue: {{.url}}", out.V{"url": p.IssueURL(p.Issues[0])})
 		return
 	}
 
@@ -76,62 +80,30 @@ func (p *Problem) Display() {
 		issues = issues[0:3]
 	}
 	for _, i := range issues {
-		out.ErrT(out.Issue, "{{.url}}", out.V{"url": fmt.Sprintf("%s/%d", issueBase, i)})
+		out.ErrT(out.Issue, "{{.url}}", out.V{"url": p.IssueURL(i)})
 	}
 }
 
+// IssueURL returns the URL of a related issue
+func (p *Problem) IssueURL(issue int) string {
+	return fmt.Sprintf("%s/%d", issueBase, issue)
+}
+
 // FromError returns a known problem from an error on an OS
 func FromError(err error, goos string) *Problem {
-	maps := []map[string]match{
-		osProblems,
-		vmProblems,
-		netProblems,
-		deployProblems,
-		stateProblems,
-	}
-
-	var osMatch *Problem
-	var genericMatch *Problem
-
-	for _, m := range maps {
-		for id, match := range m {
-			if !match.Regexp.MatchString(err.Error()) {
-				continue
-			}
-
-			// Does this match require an OS matchup?
-			if len(match.GOOS) > 0 {
-				foundOS := false
-				for _, o := range match.GOOS {
-					if o == goos {
-						foundOS = true
-					}
-				}
-				if !foundOS {
-					continue
-				}
-			}
-
-			p := &Problem{
-				Err:           err,
-				Advice:        match.Advice,
-				URL:           match.URL,
-				ID:            id,
-				Issues:        match.Issues,
-				ShowIssueLink: match.ShowIssueLink,
-			}
-
-			if len(match.GOOS) > 0 {
-				osMatch = p
-			} else {
-				genericMatch = p
-			}
-		}
+	ms := Matches(err.Error(), goos)
+	if len(ms) == 0 {
+		return nil
 	}
 
-	// Prioritize operating-system specific matches over general ones
-	if osMatch != nil {
-		return osMatch
+	m := ms[0]
+	return &Problem{
+		Err:           err,
+		Advice:        m.Advice,
+		URL:           m.URL,
+		ID:            m.ID,
+		Issues:        m.Issues,
+		ShowIssueLink: m.ShowIssueLink,
+		Probes:        m.Probes,
 	}
-	return genericMatch
 }
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule stops clusters on their own: at a deadline, or once their API server is idle
+package schedule
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/mitchellh/go-ps"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+// Command is the hidden minikube command that runs the watcher of a cluster
+const Command = "scheduled-stop"
+
+// pidPath returns the path of the file holding the pid of the watcher of a cluster
+func pidPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), constants.ScheduledStopProcessFileName)
+}
+
+// Start starts the watcher of a cluster in the background, replacing the running one, if any
+func Start(profile string) error {
+	if err := Cancel(profile); err != nil {
+		return errors.Wrap(err, "cancel")
+	}
+
+	c := exec.Command(os.Args[0], Command, fmt.Sprintf("--profile=%s", profile))
+	c.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
+	if err := c.Start(); err != nil {
+		return errors.Wrap(err, "start watcher")
+	}
+	glog.Infof("started scheduled stop watcher for %q: pid %d", profile, c.Process.Pid)
+	return lock.WriteFile(pidPath(profile), []byte(strconv.Itoa(c.Process.Pid)), 0644)
+}
+
+// Running returns the pid of the watcher of a cluster, if it is running
+func Running(profile string) (int, bool) {
+	b, err := ioutil.ReadFile(pidPath(profile))
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, false
+	}
+	p, err := ps.FindProcess(pid)
+	if err != nil || p == nil {
+		return 0, false
+	}
+	return pid, true
+}
+
+// Cancel kills the watcher of a cluster, if it is running. A watcher does not cancel itself.
+func Cancel(profile string) error {
+	pid, ok := Running(profile)
+	if !ok || pid == os.Getpid() {
+		return Done(profile)
+	}
+
+	glog.Infof("killing scheduled stop watcher for %q: pid %d", profile, pid)
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return errors.Wrap(err, "find process")
+	}
+	if err := p.Kill(); err != nil {
+		return errors.Wrapf(err, "kill %d", pid)
+	}
+	return Done(profile)
+}
+
+// Done forgets the watcher of a cluster, once it has nothing left to do
+func Done(profile string) error {
+	if err := os.Remove(pidPath(profile)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "remove pid file")
+	}
+	return nil
+}
+
+// TimeFormat is the format of the time a cluster is due to stop
+const TimeFormat = "2006-01-02 15:04:05"
+
+// Pending describes the pending stop of a cluster, or returns "" if there is none
+func Pending(cc config.ClusterConfig) string {
+	var when []string
+	if cc.ScheduledStop != nil {
+		when = append(when, fmt.Sprintf("at %s", cc.ScheduledStop.Format(TimeFormat)))
+	}
+	if cc.AutoStop > 0 {
+		when = append(when, fmt.Sprintf("after %s without API server requests", cc.AutoStop))
+	}
+	return strings.Join(when, ", or ")
+}
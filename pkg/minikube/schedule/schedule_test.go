@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Second)
+	soon := now.Add(10 * time.Second)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		description string
+		cc          config.ClusterConfig
+		last        time.Time
+		due         bool
+		next        time.Duration
+	}{
+		{"deadline passed", config.ClusterConfig{ScheduledStop: &past}, now, true, -time.Second},
+		{"deadline ahead", config.ClusterConfig{ScheduledStop: &future}, now, false, checkInterval},
+		{"deadline within interval", config.ClusterConfig{ScheduledStop: &soon}, now, false, 10 * time.Second},
+		{"idle", config.ClusterConfig{AutoStop: time.Hour}, now.Add(-time.Hour), true, checkInterval},
+		{"in use", config.ClusterConfig{AutoStop: time.Hour}, now.Add(-time.Minute), false, checkInterval},
+		{"idle before deadline", config.ClusterConfig{AutoStop: time.Minute, ScheduledStop: &future}, now.Add(-2 * time.Minute), true, checkInterval},
+		{"nothing scheduled", config.ClusterConfig{}, now.Add(-24 * time.Hour), false, checkInterval},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := due(tc.cc, now, tc.last); got != tc.due {
+				t.Errorf("due() = %v, want %v", got, tc.due)
+			}
+			if got := nextCheck(tc.cc, now); got != tc.next {
+				t.Errorf("nextCheck() = %s, want %s", got, tc.next)
+			}
+		})
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	rules := `Chain INPUT (policy ACCEPT 2113 packets, 402357 bytes)
+    pkts      bytes target     prot opt in     out     source               destination
+      17     1020            tcp  --  *      *       192.168.49.1         0.0.0.0/0            tcp dpt:8443 flags:0x17/0x02 /* minikube-auto-stop */
+  185023 89112348 KUBE-FIREWALL  all  --  *      *       0.0.0.0/0            0.0.0.0/0
+`
+	got, err := parseCount(rules)
+	if err != nil {
+		t.Fatalf("parseCount: %v", err)
+	}
+	if got != 17 {
+		t.Errorf("parseCount() = %d, want 17", got)
+	}
+
+	if _, err := parseCount("Chain INPUT (policy ACCEPT)\n"); err == nil {
+		t.Errorf("parseCount() without the rule succeeded")
+	}
+}
+
+// setConnections makes a fake runner report a count for the connections rule of a port
+func setConnections(f *command.FakeCommandRunner, port int, count int) {
+	rule := fmt.Sprintf("INPUT -p tcp --syn --dport %d -m comment --comment %s", port, ruleComment)
+	f.SetCommandToOutput(map[string]string{
+		fmt.Sprintf(`/bin/bash -c "sudo iptables -C %s 2>/dev/null || sudo iptables -I %s"`, rule, rule): "",
+		"sudo iptables -L INPUT -n -v -x": fmt.Sprintf(`Chain INPUT (policy ACCEPT 2113 packets, 402357 bytes)
+    pkts      bytes target     prot opt in     out     source               destination
+%8d     1020            tcp  --  *      *       0.0.0.0/0            0.0.0.0/0            tcp dpt:%d flags:0x17/0x02 /* minikube-auto-stop */
+`, count, port),
+	})
+}
+
+func TestRequestsCheck(t *testing.T) {
+	cc := config.ClusterConfig{Nodes: []config.Node{
+		{Name: "", Port: 8443, ControlPlane: true},
+		{Name: "m02", Port: 8443, ControlPlane: true},
+		{Name: "m03", Port: 8443, Worker: true},
+	}}
+	cp := command.NewFakeCommandRunner()
+	cp2 := command.NewFakeCommandRunner()
+	setConnections(cp, 8443, 17)
+	setConnections(cp2, 8443, 5)
+
+	// the worker has no runner: loading its host from a nil API would panic
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	r := &requests{runners: map[string]command.Runner{"": cp, "m02": cp2}, last: start}
+
+	if got := r.check(nil, cc, start.Add(time.Minute)); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("first check = %s, want %s", got, start.Add(time.Minute))
+	}
+	if r.count != 22 {
+		t.Errorf("count = %d, want the sum over both control planes, 22", r.count)
+	}
+	if got := r.check(nil, cc, start.Add(2*time.Minute)); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("check without new connections = %s, want %s", got, start.Add(time.Minute))
+	}
+
+	// a connection through the second control plane, such as the virtual IP of an HA cluster
+	setConnections(cp2, 8443, 6)
+	if got := r.check(nil, cc, start.Add(3*time.Minute)); !got.Equal(start.Add(3 * time.Minute)) {
+		t.Errorf("check after a connection to m02 = %s, want %s", got, start.Add(3*time.Minute))
+	}
+}
+
+func TestPending(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(30 * time.Minute)
+
+	tests := []struct {
+		cc   config.ClusterConfig
+		want string
+	}{
+		{config.ClusterConfig{}, ""},
+		{config.ClusterConfig{ScheduledStop: &deadline}, "at 2020-07-01 12:30:00"},
+		{config.ClusterConfig{AutoStop: time.Hour}, "after 1h0m0s without API server requests"},
+		{config.ClusterConfig{AutoStop: time.Hour, ScheduledStop: &deadline}, "at 2020-07-01 12:30:00, or after 1h0m0s without API server requests"},
+	}
+	for _, tc := range tests {
+		if got := Pending(tc.cc); got != tc.want {
+			t.Errorf("Pending(%+v) = %q, want %q", tc.cc, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// checkInterval is how often the watcher checks the schedule and the requests to the API server
+const checkInterval = 30 * time.Second
+
+// ruleComment marks the firewall rule counting the connections to the API server
+const ruleComment = "minikube-auto-stop"
+
+// Wait blocks until a cluster is due to stop, and returns true, or returns false once there is
+// nothing left to wait for: the schedule was cancelled, or the cluster was stopped by other means.
+func Wait(api libmachine.API, profile string) (bool, error) {
+	var reqs *requests
+	for {
+		cc, err := config.Load(profile)
+		if err != nil {
+			return false, errors.Wrap(err, "load config")
+		}
+		if cc.ScheduledStop == nil && cc.AutoStop <= 0 {
+			glog.Infof("no stop is scheduled for %q", profile)
+			return false, nil
+		}
+
+		cp, err := config.PrimaryControlPlane(cc)
+		if err != nil {
+			return false, errors.Wrap(err, "primary control plane")
+		}
+		st, err := machine.Status(api, driver.MachineName(*cc, cp))
+		if err != nil {
+			return false, errors.Wrap(err, "host status")
+		}
+		if st != state.Running.String() {
+			glog.Infof("%q is %s, nothing to stop", profile, st)
+			return false, nil
+		}
+
+		now := time.Now()
+		last := now
+		if cc.AutoStop > 0 {
+			if reqs == nil {
+				reqs = &requests{runners: map[string]command.Runner{}, last: now}
+			}
+			last = reqs.check(api, *cc, now)
+		}
+		if due(*cc, now, last) {
+			return true, nil
+		}
+		time.Sleep(nextCheck(*cc, now))
+	}
+}
+
+// due returns whether a cluster is due to stop, given the time of the last request to its API server
+func due(cc config.ClusterConfig, now time.Time, last time.Time) bool {
+	if cc.ScheduledStop != nil && !now.Before(*cc.ScheduledStop) {
+		return true
+	}
+	return cc.AutoStop > 0 && now.Sub(last) >= cc.AutoStop
+}
+
+// nextCheck returns how long to wait before checking again, so that a deadline is not overshot
+func nextCheck(cc config.ClusterConfig, now time.Time) time.Duration {
+	d := checkInterval
+	if cc.ScheduledStop != nil {
+		if left := cc.ScheduledStop.Sub(now); left < d {
+			d = left
+		}
+	}
+	return d
+}
+
+// requests tracks the connections opened to the API servers of every control plane, whichever way
+// they arrive: from the host, through a port forward of the driver, or through the virtual IP of an
+// HA cluster. Components within the cluster keep their connections open, so new connections come from clients.
+type requests struct {
+	runners map[string]command.Runner
+	count   int64
+	last    time.Time
+}
+
+// check returns the time of the last request seen. If the requests cannot be counted, the cluster
+// is assumed to be in use.
+func (r *requests) check(api libmachine.API, cc config.ClusterConfig, now time.Time) time.Time {
+	var total int64
+	for _, n := range cc.Nodes {
+		if !n.ControlPlane {
+			continue
+		}
+		count, err := r.countNode(api, cc, n)
+		if err != nil {
+			glog.Warningf("counting API server connections to %s: %v", n.Name, err)
+			delete(r.runners, n.Name)
+			r.last = now
+			return r.last
+		}
+		total += count
+	}
+	if total != r.count {
+		glog.Infof("%d connections to the API servers so far", total)
+		r.count = total
+		r.last = now
+	}
+	return r.last
+}
+
+// countNode returns the number of connections opened to the API server of a control plane
+func (r *requests) countNode(api libmachine.API, cc config.ClusterConfig, n config.Node) (int64, error) {
+	runner, ok := r.runners[n.Name]
+	if !ok {
+		h, err := machine.LoadHost(api, driver.MachineName(cc, n))
+		if err != nil {
+			return 0, errors.Wrap(err, "load host")
+		}
+		if runner, err = machine.CommandRunner(h); err != nil {
+			return 0, errors.Wrap(err, "command runner")
+		}
+		r.runners[n.Name] = runner
+	}
+	return countConnections(runner, n.Port)
+}
+
+// countConnections returns the number of connections opened to a port of a node, as counted by
+// a firewall rule that is inserted the first time, or again after the node restarts
+func countConnections(r command.Runner, port int) (int64, error) {
+	rule := fmt.Sprintf("INPUT -p tcp --syn --dport %d -m comment --comment %s", port, ruleComment)
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo iptables -C %s 2>/dev/null || sudo iptables -I %s", rule, rule))
+	if _, err := r.RunCmd(c); err != nil {
+		return 0, errors.Wrap(err, "insert rule")
+	}
+
+	rr, err := r.RunCmd(exec.Command("sudo", "iptables", "-L", "INPUT", "-n", "-v", "-x"))
+	if err != nil {
+		return 0, errors.Wrap(err, "list rules")
+	}
+	return parseCount(rr.Stdout.String())
+}
+
+// parseCount returns the packet count of the rule counting connections in the output of iptables -L -v -x
+func parseCount(rules string) (int64, error) {
+	s := bufio.NewScanner(strings.NewReader(rules))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || !strings.Contains(s.Text(), "/* "+ruleComment+" */") {
+			continue
+		}
+		return strconv.ParseInt(fields[0], 10, 64)
+	}
+	return 0, fmt.Errorf("no %s rule in:\n%s", ruleComment, rules)
+}
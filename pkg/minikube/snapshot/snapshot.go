@@ -0,0 +1,488 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot saves and restores the state of a minikube cluster
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// manifestFile is the name of the file describing a snapshot
+	manifestFile = "manifest.json"
+	// archiveFile is the name of the tarball holding the node state
+	archiveFile = "node.tar.gz"
+	// configFile is the name of the saved ClusterConfig
+	configFile = "config.json"
+	// certsDir is the directory holding the saved profile certificates
+	certsDir = "certs"
+	// backupSuffix is appended to the guest paths which are set aside during a restore
+	backupSuffix = ".orig"
+)
+
+// stagingDir is where a snapshot is extracted to within the guest VM, before it is swapped in
+var stagingDir = path.Join(vmpath.GuestPersistentDir, "snapshot")
+
+// Manifest describes a saved snapshot
+type Manifest struct {
+	Name              string
+	Profile           string
+	CreationTime      time.Time
+	KubernetesVersion string
+	ContainerRuntime  string
+	Driver            string
+	// Paths are the guest paths captured in the archive
+	Paths []string
+	// Images are the images present in the container runtime when the snapshot was taken
+	Images []string
+}
+
+// guestPaths returns the paths inside the node which make up a snapshot
+func guestPaths() []string {
+	return []string{
+		bsutil.EtcdDataDir(),
+		vmpath.GuestKubernetesCertsDir,
+		vmpath.GuestAddonsDir,
+		vmpath.GuestManifestsDir,
+	}
+}
+
+// Dir returns the directory where snapshots of a profile are stored
+func Dir(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "snapshots")
+}
+
+// Path returns the directory of a named snapshot
+func Path(profile string, name string) string {
+	return filepath.Join(Dir(profile), name)
+}
+
+// validateName checks that a snapshot name is a single path element, so that it stays within the profile
+func validateName(name string) error {
+	if !config.ProfileNameValid(name) {
+		return fmt.Errorf("invalid snapshot name: %q", name)
+	}
+	return nil
+}
+
+// Exists returns whether a named snapshot exists for a profile
+func Exists(profile string, name string) bool {
+	_, err := os.Stat(filepath.Join(Path(profile, name), manifestFile))
+	return err == nil
+}
+
+// Save captures etcd data, certificates, the cluster config and image list of the control plane
+func Save(cc *config.ClusterConfig, cr cruntime.Manager, r command.Runner, name string) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if Exists(cc.Name, name) {
+		return nil, fmt.Errorf("snapshot %q already exists", name)
+	}
+
+	dir := Path(cc.Name, name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+
+	m, err := save(cc, cr, r, name, dir)
+	if err != nil {
+		if rerr := os.RemoveAll(dir); rerr != nil {
+			glog.Warningf("failed to clean up %s: %v", dir, rerr)
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func save(cc *config.ClusterConfig, cr cruntime.Manager, r command.Runner, name string, dir string) (*Manifest, error) {
+	images, err := cr.ListImages()
+	if err != nil {
+		return nil, errors.Wrap(err, "list images")
+	}
+
+	// Pause the cluster so that etcd has flushed its state before it is archived. Only what is
+	// paused here is unpaused afterwards, so a cluster that was already paused stays paused.
+	sm := sysinit.New(r)
+	kubelet := sm.Active("kubelet")
+	ids, err := cluster.Pause(cr, r, nil)
+	if err != nil {
+		if rerr := resume(cr, sm, ids, kubelet); rerr != nil {
+			glog.Warningf("failed to resume cluster: %v", rerr)
+		}
+		return nil, errors.Wrap(err, "pause")
+	}
+	archiveErr := archive(r, filepath.Join(dir, archiveFile))
+	if err := resume(cr, sm, ids, kubelet); err != nil {
+		return nil, errors.Wrap(err, "unpause")
+	}
+	if archiveErr != nil {
+		return nil, errors.Wrap(archiveErr, "archive")
+	}
+
+	if err := copyCerts(localpath.Profile(cc.Name), filepath.Join(dir, certsDir)); err != nil {
+		return nil, errors.Wrap(err, "copy certs")
+	}
+
+	if err := writeJSON(filepath.Join(dir, configFile), cc); err != nil {
+		return nil, errors.Wrap(err, "save config")
+	}
+
+	m := &Manifest{
+		Name:              name,
+		Profile:           cc.Name,
+		CreationTime:      time.Now(),
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
+		Driver:            cc.Driver,
+		Paths:             guestPaths(),
+		Images:            images,
+	}
+	return m, writeJSON(filepath.Join(dir, manifestFile), m)
+}
+
+// resume unpauses the given containers, and starts kubelet if it was running before the pause
+func resume(cr cruntime.Manager, sm sysinit.Manager, ids []string, kubelet bool) error {
+	if len(ids) > 0 {
+		if err := cr.UnpauseContainers(ids); err != nil {
+			return errors.Wrap(err, "unpause containers")
+		}
+	}
+	if kubelet {
+		return sm.Start("kubelet")
+	}
+	return nil
+}
+
+// Restore rolls the control plane back to a previously saved snapshot
+func Restore(cc *config.ClusterConfig, cr cruntime.Manager, r command.Runner, name string) (*Manifest, error) {
+	m, err := Load(cc.Name, name)
+	if err != nil {
+		return nil, err
+	}
+	if m.ContainerRuntime != cc.KubernetesConfig.ContainerRuntime {
+		return nil, fmt.Errorf("snapshot %q was taken with container runtime %q, but the cluster uses %q", name, m.ContainerRuntime, cc.KubernetesConfig.ContainerRuntime)
+	}
+	// etcd data and the saved config can't be rolled back across upgrades, as the binaries stay newer
+	if m.KubernetesVersion != cc.KubernetesConfig.KubernetesVersion {
+		return nil, fmt.Errorf("snapshot %q was taken with Kubernetes %s, but the cluster runs %s", name, m.KubernetesVersion, cc.KubernetesConfig.KubernetesVersion)
+	}
+
+	dir := Path(cc.Name, name)
+	data, err := ioutil.ReadFile(filepath.Join(dir, configFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "read saved config")
+	}
+	saved := &config.ClusterConfig{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, errors.Wrap(err, "unmarshal saved config")
+	}
+
+	// Extract into a staging directory while the cluster is still running, so that a broken
+	// archive leaves the cluster untouched
+	staging, err := stage(r, filepath.Join(dir, archiveFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "extract")
+	}
+	defer func() {
+		if _, err := r.RunCmd(exec.Command("sudo", "rm", "-rf", staging)); err != nil {
+			glog.Warningf("unable to remove %s: %v", staging, err)
+		}
+	}()
+
+	if err := replace(cr, r, staging, m.Paths, filepath.Join(dir, certsDir), localpath.Profile(cc.Name)); err != nil {
+		return nil, err
+	}
+
+	// Machine specific details may have changed since the snapshot was taken
+	saved.Nodes = cc.Nodes
+	if err := config.SaveProfile(cc.Name, saved); err != nil {
+		return nil, errors.Wrap(err, "save config")
+	}
+	return m, nil
+}
+
+// MissingImages returns the images from the manifest that are no longer present in the runtime
+func MissingImages(m *Manifest, cr cruntime.Manager) ([]string, error) {
+	current, err := cr.ListImages()
+	if err != nil {
+		return nil, err
+	}
+	present := map[string]bool{}
+	for _, i := range current {
+		present[i] = true
+	}
+	missing := []string{}
+	for _, i := range m.Images {
+		if !present[i] {
+			missing = append(missing, i)
+		}
+	}
+	return missing, nil
+}
+
+// Load loads the manifest of a named snapshot
+func Load(profile string, name string) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(Path(profile, name), manifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %q does not exist", name)
+		}
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	return m, nil
+}
+
+// List returns the manifests of all snapshots for a profile, oldest first
+func List(profile string) ([]*Manifest, error) {
+	items, err := ioutil.ReadDir(Dir(profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ms := []*Manifest{}
+	for _, f := range items {
+		if !f.IsDir() {
+			continue
+		}
+		m, err := Load(profile, f.Name())
+		if err != nil {
+			glog.Warningf("skipping snapshot %s: %v", f.Name(), err)
+			continue
+		}
+		ms = append(ms, m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].CreationTime.Before(ms[j].CreationTime) })
+	return ms, nil
+}
+
+// Delete removes a named snapshot
+func Delete(profile string, name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if !Exists(profile, name) {
+		return fmt.Errorf("snapshot %q does not exist", name)
+	}
+	return os.RemoveAll(Path(profile, name))
+}
+
+// archive streams a tarball of the snapshot paths out of the node into dst
+func archive(r command.Runner, dst string) error {
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	args := []string{"sudo", "tar", "-C", "/", "-czf", "-"}
+	for _, p := range guestPaths() {
+		args = append(args, strings.TrimPrefix(p, "/"))
+	}
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdout = f
+	if _, err := r.RunCmd(c); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// replace stops the control plane, swaps the staged paths and the saved certificates in, and
+// starts it again. If any step fails, the previous state is put back and kubelet is restarted.
+func replace(cr cruntime.Manager, r command.Runner, staging string, paths []string, certs string, profile string) (err error) {
+	sm := sysinit.New(r)
+	if err := sm.Stop("kubelet"); err != nil {
+		return errors.Wrap(err, "kubelet stop")
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if serr := sm.Start("kubelet"); serr != nil {
+			glog.Warningf("failed to restart kubelet: %v", serr)
+		}
+	}()
+
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.All})
+	if err != nil {
+		return errors.Wrap(err, "list containers")
+	}
+	if len(ids) > 0 {
+		if err := cr.StopContainers(ids); err != nil {
+			return errors.Wrap(err, "stop containers")
+		}
+	}
+
+	if err := swap(r, staging, paths); err != nil {
+		return errors.Wrap(err, "swap")
+	}
+
+	backup, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		unswap(r, paths)
+		return errors.Wrap(err, "tempdir")
+	}
+	defer os.RemoveAll(backup)
+	if err := copyCerts(profile, backup); err != nil {
+		unswap(r, paths)
+		return errors.Wrap(err, "backup certs")
+	}
+	if err := copyCerts(certs, profile); err != nil {
+		if cerr := copyCerts(backup, profile); cerr != nil {
+			glog.Warningf("failed to put back certs: %v", cerr)
+		}
+		unswap(r, paths)
+		return errors.Wrap(err, "restore certs")
+	}
+
+	if err := sm.Start("kubelet"); err != nil {
+		return errors.Wrap(err, "kubelet start")
+	}
+	for _, p := range paths {
+		if _, err := r.RunCmd(exec.Command("sudo", "rm", "-rf", p+backupSuffix)); err != nil {
+			glog.Warningf("unable to remove %s: %v", p+backupSuffix, err)
+		}
+	}
+	return nil
+}
+
+// stage copies a tarball into the node and extracts it into a staging directory, returning its path
+func stage(r command.Runner, src string) (string, error) {
+	f, err := assets.NewFileAsset(src, vmpath.GuestEphemeralDir, archiveFile, "0600")
+	if err != nil {
+		return "", errors.Wrap(err, "file asset")
+	}
+
+	if err := r.Copy(f); err != nil {
+		return "", errors.Wrap(err, "copy")
+	}
+	tarball := path.Join(vmpath.GuestEphemeralDir, archiveFile)
+	defer func() {
+		if _, err := r.RunCmd(exec.Command("sudo", "rm", "-f", tarball)); err != nil {
+			glog.Warningf("unable to remove %s: %v", tarball, err)
+		}
+	}()
+
+	if _, err := r.RunCmd(exec.Command("sudo", "rm", "-rf", stagingDir)); err != nil {
+		return "", errors.Wrapf(err, "remove %s", stagingDir)
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "mkdir", "-p", stagingDir)); err != nil {
+		return "", errors.Wrap(err, "mkdir")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "tar", "-C", stagingDir, "-xzf", tarball)); err != nil {
+		if _, rerr := r.RunCmd(exec.Command("sudo", "rm", "-rf", stagingDir)); rerr != nil {
+			glog.Warningf("unable to remove %s: %v", stagingDir, rerr)
+		}
+		return "", errors.Wrap(err, "untar")
+	}
+	return stagingDir, nil
+}
+
+// swap moves each path aside and replaces it with its staged copy. On failure, the paths already
+// swapped are put back.
+func swap(r command.Runner, staging string, paths []string) error {
+	for i, p := range paths {
+		cmds := [][]string{
+			{"sudo", "rm", "-rf", p + backupSuffix},
+			{"sudo", "mkdir", "-p", p},
+			{"sudo", "mv", p, p + backupSuffix},
+			{"sudo", "mv", path.Join(staging, p), p},
+		}
+		for _, args := range cmds {
+			if _, err := r.RunCmd(exec.Command(args[0], args[1:]...)); err != nil {
+				// put back the current path as well, in case it was already moved aside
+				unswap(r, paths[:i+1])
+				return errors.Wrapf(err, "replace %s", p)
+			}
+		}
+	}
+	return nil
+}
+
+// unswap moves the original copy of each path back in place
+func unswap(r command.Runner, paths []string) {
+	for _, p := range paths {
+		c := exec.Command("sudo", "/bin/bash", "-c", fmt.Sprintf("if [ -e %[1]s ]; then rm -rf %[2]s && mv %[1]s %[2]s; fi", p+backupSuffix, p))
+		if _, err := r.RunCmd(c); err != nil {
+			glog.Warningf("unable to put back %s: %v", p, err)
+		}
+	}
+}
+
+// copyCerts copies the certificates and keys found in src into dst
+func copyCerts(src string, dst string) error {
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return err
+	}
+	items, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, f := range items {
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || (ext != ".crt" && ext != ".key") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(src, f.Name()))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dst, f.Name()), data, f.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// fakeRunner records the commands it is asked to run, failing those starting with any of failOn
+type fakeRunner struct {
+	cmds   []string
+	failOn []string
+}
+
+func (f *fakeRunner) RunCmd(c *exec.Cmd) (*command.RunResult, error) {
+	rr := &command.RunResult{Args: c.Args}
+	cmd := strings.Join(c.Args, " ")
+	f.cmds = append(f.cmds, cmd)
+	for _, prefix := range f.failOn {
+		if strings.HasPrefix(cmd, prefix) {
+			return rr, fmt.Errorf("%s: failed", cmd)
+		}
+	}
+	if c.Stdout != nil {
+		fmt.Fprint(c.Stdout, "tarball")
+	}
+	return rr, nil
+}
+
+func (f *fakeRunner) Copy(assets.CopyableFile) error {
+	return nil
+}
+
+func (f *fakeRunner) Remove(assets.CopyableFile) error {
+	return nil
+}
+
+// ran returns whether a command was run
+func (f *fakeRunner) ran(cmd string) bool {
+	for _, c := range f.cmds {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeRuntime tracks the state of containers, and the ones it was asked to unpause
+type fakeRuntime struct {
+	cruntime.Manager
+	running  []string
+	paused   []string
+	unpaused []string
+}
+
+func (f *fakeRuntime) ListImages() ([]string, error) {
+	return []string{"k8s.gcr.io/pause:3.2"}, nil
+}
+
+func (f *fakeRuntime) ListContainers(o cruntime.ListOptions) ([]string, error) {
+	switch o.State {
+	case cruntime.Running:
+		return f.running, nil
+	case cruntime.Paused:
+		return f.paused, nil
+	}
+	return append(append([]string{}, f.running...), f.paused...), nil
+}
+
+func (f *fakeRuntime) PauseContainers(ids []string) error {
+	f.paused = append(f.paused, ids...)
+	f.running = nil
+	return nil
+}
+
+func (f *fakeRuntime) UnpauseContainers(ids []string) error {
+	f.unpaused = append(f.unpaused, ids...)
+	return nil
+}
+
+func (f *fakeRuntime) StopContainers([]string) error {
+	return nil
+}
+
+// setupProfile points MINIKUBE_HOME at a temporary directory, holding a profile with a certificate
+func setupProfile(t *testing.T, cert string) (*config.ClusterConfig, func()) {
+	td, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	old := os.Getenv(localpath.MinikubeHome)
+	os.Setenv(localpath.MinikubeHome, td)
+	cleanup := func() {
+		os.Setenv(localpath.MinikubeHome, old)
+		os.RemoveAll(td)
+	}
+
+	cc := &config.ClusterConfig{Name: "p1", KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}
+	if err := os.MkdirAll(localpath.Profile(cc.Name), 0700); err != nil {
+		cleanup()
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(localpath.Profile(cc.Name), "client.crt"), []byte(cert), 0600); err != nil {
+		cleanup()
+		t.Fatalf("write cert: %v", err)
+	}
+	return cc, cleanup
+}
+
+func readCert(t *testing.T, profile string) string {
+	data, err := ioutil.ReadFile(filepath.Join(localpath.Profile(profile), "client.crt"))
+	if err != nil {
+		t.Fatalf("read cert: %v", err)
+	}
+	return string(data)
+}
+
+func TestSave(t *testing.T) {
+	tests := []struct {
+		description  string
+		running      []string
+		paused       []string
+		failOn       []string
+		wantUnpaused []string
+		wantKubelet  bool
+	}{
+		{
+			description:  "running cluster",
+			running:      []string{"a", "b"},
+			wantUnpaused: []string{"a", "b"},
+			wantKubelet:  true,
+		},
+		{
+			description: "paused cluster",
+			paused:      []string{"a", "b"},
+			failOn:      []string{"sudo systemctl is-active"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cc, cleanup := setupProfile(t, "saved")
+			defer cleanup()
+
+			cr := &fakeRuntime{running: tc.running, paused: tc.paused}
+			r := &fakeRunner{failOn: tc.failOn}
+			m, err := Save(cc, cr, r, "snap")
+			if err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantUnpaused, cr.unpaused); diff != "" {
+				t.Errorf("unpaused mismatch (-want +got):\n%s", diff)
+			}
+			if got := r.ran("sudo systemctl start kubelet"); got != tc.wantKubelet {
+				t.Errorf("kubelet started = %v, want %v", got, tc.wantKubelet)
+			}
+			if diff := cmp.Diff([]string{"k8s.gcr.io/pause:3.2"}, m.Images); diff != "" {
+				t.Errorf("images mismatch (-want +got):\n%s", diff)
+			}
+			if !Exists(cc.Name, "snap") {
+				t.Errorf("snapshot does not exist after Save")
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		description string
+		failOn      []string
+		upgradedTo  string
+		wantErr     bool
+		wantCert    string
+		wantStopped bool
+		wantCmds    []string
+	}{
+		{
+			description: "success",
+			wantCert:    "saved",
+			wantStopped: true,
+			wantCmds: []string{
+				"sudo mv /var/lib/minikube/snapshot/var/lib/minikube/etcd /var/lib/minikube/etcd",
+				"sudo rm -rf /var/lib/minikube/etcd.orig",
+				"sudo rm -rf /var/lib/minikube/snapshot",
+			},
+		},
+		{
+			description: "upgraded cluster",
+			upgradedTo:  "v1.19.0",
+			wantErr:     true,
+			wantCert:    "current",
+		},
+		{
+			description: "broken archive",
+			failOn:      []string{"sudo tar"},
+			wantErr:     true,
+			wantCert:    "current",
+		},
+		{
+			description: "swap failure",
+			failOn:      []string{"sudo mv /var/lib/minikube/snapshot/var/lib/minikube/certs"},
+			wantErr:     true,
+			wantCert:    "current",
+			wantStopped: true,
+			wantCmds: []string{
+				"sudo /bin/bash -c if [ -e /var/lib/minikube/etcd.orig ]; then rm -rf /var/lib/minikube/etcd && mv /var/lib/minikube/etcd.orig /var/lib/minikube/etcd; fi",
+				"sudo /bin/bash -c if [ -e /var/lib/minikube/certs.orig ]; then rm -rf /var/lib/minikube/certs && mv /var/lib/minikube/certs.orig /var/lib/minikube/certs; fi",
+				"sudo systemctl start kubelet",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cc, cleanup := setupProfile(t, "saved")
+			defer cleanup()
+
+			if _, err := Save(cc, &fakeRuntime{}, &fakeRunner{}, "snap"); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(localpath.Profile(cc.Name), "client.crt"), []byte("current"), 0600); err != nil {
+				t.Fatalf("write cert: %v", err)
+			}
+			if tc.upgradedTo != "" {
+				cc.KubernetesConfig.KubernetesVersion = tc.upgradedTo
+			}
+
+			r := &fakeRunner{failOn: tc.failOn}
+			_, err := Restore(cc, &fakeRuntime{running: []string{"a"}}, r, "snap")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Restore error = %v, want error: %v", err, tc.wantErr)
+			}
+			if got := readCert(t, cc.Name); got != tc.wantCert {
+				t.Errorf("cert = %q, want %q", got, tc.wantCert)
+			}
+			if got := r.ran("sudo systemctl stop kubelet"); got != tc.wantStopped {
+				t.Errorf("kubelet stopped = %v, want %v", got, tc.wantStopped)
+			}
+			for _, c := range tc.wantCmds {
+				if !r.ran(c) {
+					t.Errorf("expected command %q, got:\n%s", c, strings.Join(r.cmds, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestListAndDelete(t *testing.T) {
+	td, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(td)
+
+	old := os.Getenv(localpath.MinikubeHome)
+	defer os.Setenv(localpath.MinikubeHome, old)
+	os.Setenv(localpath.MinikubeHome, td)
+
+	ms, err := List("p1")
+	if err != nil {
+		t.Fatalf("List on empty profile: %v", err)
+	}
+	if len(ms) != 0 {
+		t.Errorf("List on empty profile returned %d snapshots, want 0", len(ms))
+	}
+
+	now := time.Now()
+	for i, name := range []string{"newer", "older"} {
+		if err := os.MkdirAll(Path("p1", name), 0700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		m := &Manifest{Name: name, Profile: "p1", CreationTime: now.Add(time.Duration(-i) * time.Hour)}
+		if err := writeJSON(filepath.Join(Path("p1", name), manifestFile), m); err != nil {
+			t.Fatalf("write manifest: %v", err)
+		}
+	}
+	// directories without a manifest are ignored
+	if err := os.MkdirAll(Path("p1", "broken"), 0700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	ms, err = List("p1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	got := []string{}
+	for _, m := range ms {
+		got = append(got, m.Name)
+	}
+	if diff := cmp.Diff([]string{"older", "newer"}, got); diff != "" {
+		t.Errorf("List mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := Delete("p1", "older"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	if Exists("p1", "older") {
+		t.Errorf("snapshot still exists after Delete")
+	}
+	if err := Delete("p1", "older"); err == nil {
+		t.Errorf("expected error deleting missing snapshot")
+	}
+}
+
+func TestNameTraversal(t *testing.T) {
+	td, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(td)
+
+	old := os.Getenv(localpath.MinikubeHome)
+	defer os.Setenv(localpath.MinikubeHome, old)
+	os.Setenv(localpath.MinikubeHome, td)
+
+	// a snapshot of another profile, reachable from p1 by a relative path
+	if err := os.MkdirAll(Path("p2", "snap"), 0700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := writeJSON(filepath.Join(Path("p2", "snap"), manifestFile), &Manifest{Name: "snap", Profile: "p2"}); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	name := filepath.Join("..", "..", "p2", "snapshots", "snap")
+	if _, err := os.Stat(Path("p1", name)); err != nil {
+		t.Fatalf("test setup: %s is not reachable from p1: %v", name, err)
+	}
+
+	if _, err := Load("p1", name); err == nil {
+		t.Errorf("expected Load to refuse %q", name)
+	}
+	if err := Delete("p1", name); err == nil {
+		t.Errorf("expected Delete to refuse %q", name)
+	}
+	if !Exists("p2", "snap") {
+		t.Errorf("snapshot of another profile was deleted")
+	}
+}
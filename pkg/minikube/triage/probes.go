@@ -0,0 +1,282 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triage
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// Names of the probes, as referred to by problems
+const (
+	PortFree        = "port-free"
+	DNS             = "dns"
+	DockerDaemon    = "docker-daemon"
+	DiskSpace       = "disk-space"
+	CgroupDriver    = "cgroup-driver"
+	StaleContainers = "stale-containers"
+)
+
+// maxDiskUsed is the percentage of the disk of a node beyond which it is full
+const maxDiskUsed = 95
+
+// notRunning is why the probes of the node are skipped when it is not running
+const notRunning = skipped("the node is not running")
+
+var probes = map[string]Probe{
+	PortFree: {
+		Description: "The ports in conflict are free on the host",
+		Check:       checkPortFree,
+	},
+	DNS: {
+		Description: "The image repository resolves",
+		Check:       checkDNS,
+	},
+	DockerDaemon: {
+		Description: "The docker daemon is reachable",
+		Check:       checkDockerDaemon,
+	},
+	DiskSpace: {
+		Description: "The node has free disk space",
+		Check:       checkDiskSpace,
+	},
+	CgroupDriver: {
+		Description: "The kubelet and the container runtime use the same cgroup driver",
+		Check:       checkCgroupDriver,
+	},
+	StaleContainers: {
+		Description: "No containers of a deleted cluster are left behind",
+		Check:       checkStaleContainers,
+	},
+}
+
+// portRe matches the ports of the errors of port conflicts, such as "[ERROR Port-10250]: Port 10250 is in use",
+// "Bind for 0.0.0.0:8443 failed: port is already allocated" or "listen tcp :8443: bind: address already in use"
+var portRe = regexp.MustCompile(`Port[- ](\d+)|:(\d+) failed: port is already allocated|:(\d+): bind: address already in use`)
+
+// conflictPorts returns the ports in conflict of an error message, in order
+func conflictPorts(msg string) []int {
+	seen := map[int]bool{}
+	ports := []int{}
+	for _, m := range portRe.FindAllStringSubmatch(msg, -1) {
+		for _, s := range m[1:] {
+			p, err := strconv.Atoi(s)
+			if err != nil || seen[p] {
+				continue
+			}
+			seen[p] = true
+			ports = append(ports, p)
+		}
+	}
+	return ports
+}
+
+// checkPortFree checks that the ports in conflict of the error, or else the API server port of a bare metal cluster, are free on the host
+func checkPortFree(env Env) error {
+	if env.Config != nil && driver.IsVM(env.Config.Driver) {
+		return skipped("the ports of a VM are not on the host")
+	}
+
+	ports := []int{}
+	if env.Err != nil {
+		ports = conflictPorts(env.Err.Error())
+	}
+	if len(ports) == 0 && env.Config != nil && driver.BareMetal(env.Config.Driver) {
+		if cp, err := config.PrimaryControlPlane(env.Config); err == nil {
+			ports = append(ports, cp.Port)
+		}
+	}
+	if len(ports) == 0 {
+		return skipped("no port is in conflict")
+	}
+
+	used := []string{}
+	for _, p := range ports {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", p))
+		if err != nil {
+			glog.Infof("listen on port %d: %v", p, err)
+			used = append(used, strconv.Itoa(p))
+			continue
+		}
+		l.Close()
+	}
+	if len(used) > 0 {
+		return fmt.Errorf("ports in use on the host: %s. Find the process using one with 'sudo lsof -i :%s'", strings.Join(used, ", "), used[0])
+	}
+	return nil
+}
+
+// repositoryHost returns the host of the image repository of a cluster
+func repositoryHost(cc *config.ClusterConfig) string {
+	repo := images.DefaultKubernetesRepo
+	if cc != nil && cc.KubernetesConfig.ImageRepository != "" {
+		repo = cc.KubernetesConfig.ImageRepository
+	}
+	host := strings.SplitN(repo, "/", 2)[0]
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// checkDNS checks that the image repository resolves on the host, and within the node if it is running
+func checkDNS(env Env) error {
+	host := repositoryHost(env.Config)
+	if _, err := net.LookupHost(host); err != nil {
+		return fmt.Errorf("%s does not resolve on the host: %v", host, err)
+	}
+
+	if env.Runner == nil {
+		return nil
+	}
+	if _, err := env.Runner.RunCmd(exec.Command("getent", "hosts", host)); err != nil {
+		return fmt.Errorf("%s resolves on the host, but not within the node", host)
+	}
+	return nil
+}
+
+// checkDockerDaemon checks that the docker or podman daemon of the host is reachable for container drivers,
+// and that the docker daemon within the node is reachable if it is the container runtime
+func checkDockerDaemon(env Env) error {
+	if env.Config == nil {
+		return skipped("the cluster does not exist")
+	}
+
+	kic := driver.IsKIC(env.Config.Driver)
+	if kic {
+		bin := oci.Docker
+		if env.Config.Driver == driver.Podman {
+			bin = oci.Podman
+		}
+		if _, err := oci.DaemonInfo(bin); err != nil {
+			return fmt.Errorf("the %s daemon of the host is not reachable: %v", bin, err)
+		}
+	}
+
+	if rt := env.Config.KubernetesConfig.ContainerRuntime; rt != "" && rt != "docker" {
+		if kic {
+			return nil
+		}
+		return skipped(fmt.Sprintf("the container runtime is %s", rt))
+	}
+	if env.Runner == nil {
+		if kic {
+			return nil
+		}
+		return notRunning
+	}
+	if _, err := env.Runner.RunCmd(exec.Command("docker", "version", "--format", "{{.Server.Version}}")); err != nil {
+		return fmt.Errorf("the docker daemon of the node is not reachable: %v", err)
+	}
+	return nil
+}
+
+// checkDiskSpace checks that the disk of the node is not full
+func checkDiskSpace(env Env) error {
+	if env.Runner == nil {
+		return notRunning
+	}
+
+	used, err := machine.DiskUsed(env.Runner, "/var")
+	if err != nil {
+		return skipped(fmt.Sprintf("unable to get the disk usage: %v", err))
+	}
+	if used >= maxDiskUsed {
+		return fmt.Errorf("%d%% of the disk of the node is used. Remove unused images, or recreate the cluster with a larger --disk-size", used)
+	}
+	return nil
+}
+
+// cgroupDriverRe matches the cgroup driver flag of the kubelet
+var cgroupDriverRe = regexp.MustCompile(`--cgroup-driver=(\w+)`)
+
+// kubeletCgroupDriver returns the cgroup driver of a kubelet systemd unit, the last flag winning
+func kubeletCgroupDriver(unit string) string {
+	ms := cgroupDriverRe.FindAllStringSubmatch(unit, -1)
+	if len(ms) == 0 {
+		return "cgroupfs"
+	}
+	return ms[len(ms)-1][1]
+}
+
+// checkCgroupDriver checks that the kubelet uses the cgroup driver of the container runtime
+func checkCgroupDriver(env Env) error {
+	if env.Config == nil || env.Runner == nil {
+		return notRunning
+	}
+
+	cr, err := cruntime.New(cruntime.Config{Type: env.Config.KubernetesConfig.ContainerRuntime, Runner: env.Runner})
+	if err != nil {
+		return skipped(fmt.Sprintf("unknown container runtime: %v", err))
+	}
+	want, err := cr.CGroupDriver()
+	if err != nil {
+		return skipped(fmt.Sprintf("unable to get the cgroup driver of %s: %v", cr.Name(), err))
+	}
+
+	rr, err := env.Runner.RunCmd(exec.Command("sudo", "cat", bsutil.KubeletSystemdConfFile))
+	if err != nil {
+		return skipped("the kubelet is not configured")
+	}
+	if got := kubeletCgroupDriver(rr.Stdout.String()); got != want {
+		return fmt.Errorf("the kubelet uses the %s cgroup driver, but %s uses %s. Pass --extra-config=kubelet.cgroup-driver=%s to 'minikube start'", got, cr.Name(), want, want)
+	}
+	return nil
+}
+
+// checkStaleContainers checks that no docker or podman containers are labeled with the cluster when its machine does not exist,
+// as left behind by a cluster that was not entirely deleted
+func checkStaleContainers(env Env) error {
+	if env.Config != nil && !driver.IsKIC(env.Config.Driver) {
+		return skipped("the cluster does not use containers")
+	}
+	if _, err := os.Stat(localpath.MachinePath(env.Profile)); err == nil {
+		return nil
+	}
+
+	stale := []string{}
+	for _, bin := range []string{oci.Docker, oci.Podman} {
+		if _, err := exec.LookPath(bin); err != nil {
+			continue
+		}
+		cs, err := oci.ListContainersByLabel(bin, fmt.Sprintf("%s=%s", oci.ProfileLabelKey, env.Profile))
+		if err != nil {
+			glog.Warningf("listing %s containers of %s: %v", bin, env.Profile, err)
+			continue
+		}
+		stale = append(stale, cs...)
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("containers of a deleted cluster are left behind: %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package triage runs diagnostic probes for the known problems that minikube fails with.
+package triage
+
+import (
+	"sort"
+
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Env is what the probes of a problem inspect
+type Env struct {
+	// Profile is the name of the cluster
+	Profile string
+	// Config is the configuration of the cluster, or nil if it does not exist
+	Config *config.ClusterConfig
+	// Runner runs commands on the primary control plane, or is nil if it is not running
+	Runner command.Runner
+	// Err is the error that the problem was matched from
+	Err error
+}
+
+// Probe is a diagnostic check of the host or the node
+type Probe struct {
+	// Description is what the probe checks
+	Description string
+	// Check returns what is wrong, or a skipped error if the probe does not apply
+	Check func(Env) error
+}
+
+// Status is the outcome of a probe
+type Status int
+
+const (
+	// Passed means that the probe found nothing wrong
+	Passed Status = iota
+	// Failed means that the probe found what is wrong
+	Failed
+	// Skipped means that the probe does not apply, or could not run
+	Skipped
+)
+
+// Result is the outcome of a probe of a problem
+type Result struct {
+	Name        string
+	Description string
+	Status      Status
+	// Detail is what the probe found if it failed, or why it was skipped
+	Detail string
+}
+
+// skipped is the error of a probe that does not apply, which is the reason why
+type skipped string
+
+func (s skipped) Error() string {
+	return string(s)
+}
+
+// Names returns the names of the probes, sorted
+func Names() []string {
+	names := []string{}
+	for n := range probes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs probes by name, in order. Unknown probes are skipped.
+func Run(names []string, env Env) []Result {
+	rs := []Result{}
+	for _, n := range names {
+		p, ok := probes[n]
+		if !ok {
+			rs = append(rs, Result{Name: n, Status: Skipped, Detail: "unknown probe"})
+			continue
+		}
+
+		r := Result{Name: n, Description: p.Description}
+		err := p.Check(env)
+		switch err.(type) {
+		case nil:
+			r.Status = Passed
+		case skipped:
+			r.Status = Skipped
+			r.Detail = err.Error()
+		default:
+			r.Status = Failed
+			r.Detail = err.Error()
+		}
+		glog.Infof("probe %s of %s: status=%d %s", n, env.Profile, r.Status, r.Detail)
+		rs = append(rs, r)
+	}
+	return rs
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triage
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+func TestConflictPorts(t *testing.T) {
+	var tests = []struct {
+		msg  string
+		want []int
+	}{
+		{"[ERROR Port-10250]: Port 10250 is in use\n[ERROR Port-10252]: Port 10252 is in use", []int{10250, 10252}},
+		{"Bind for 0.0.0.0:8443 failed: port is already allocated", []int{8443}},
+		{"listen tcp 127.0.0.1:2379: bind: address already in use", []int{2379}},
+		{"dial tcp 192.168.39.2:8443: connect: connection refused", []int{}},
+	}
+	for _, tc := range tests {
+		got := conflictPorts(tc.msg)
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("conflictPorts(%q) = %v, want %v", tc.msg, got, tc.want)
+		}
+	}
+}
+
+func TestCheckPortFree(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	cause := fmt.Errorf("[ERROR Port-%d]: Port %d is in use", port, port)
+	if err := checkPortFree(Env{Err: cause}); err == nil || !strings.Contains(err.Error(), fmt.Sprint(port)) {
+		t.Errorf("checkPortFree() with port %d in use = %v, want it reported", port, err)
+	}
+
+	vm := &config.ClusterConfig{Driver: driver.VirtualBox}
+	if err := checkPortFree(Env{Config: vm, Err: cause}); err == nil || err.Error() != "the ports of a VM are not on the host" {
+		t.Errorf("checkPortFree() of a VM = %v, want it skipped", err)
+	}
+}
+
+func TestKubeletCgroupDriver(t *testing.T) {
+	var tests = []struct {
+		unit string
+		want string
+	}{
+		{"ExecStart=/var/lib/minikube/binaries/v1.18.3/kubelet --cgroup-driver=systemd --hostname-override=minikube", "systemd"},
+		{"ExecStart=/usr/bin/kubelet --cgroup-driver=cgroupfs\nExecStart=/usr/bin/kubelet --cgroup-driver=systemd", "systemd"},
+		{"ExecStart=/usr/bin/kubelet --hostname-override=minikube", "cgroupfs"},
+	}
+	for _, tc := range tests {
+		if got := kubeletCgroupDriver(tc.unit); got != tc.want {
+			t.Errorf("kubeletCgroupDriver(%q) = %q, want %q", tc.unit, got, tc.want)
+		}
+	}
+}
+
+func TestRepositoryHost(t *testing.T) {
+	var tests = []struct {
+		repo string
+		want string
+	}{
+		{"", "k8s.gcr.io"},
+		{"registry.cn-hangzhou.aliyuncs.com/google_containers", "registry.cn-hangzhou.aliyuncs.com"},
+		{"localhost:5000", "localhost"},
+		{"registry.local:5000/k8s", "registry.local"},
+	}
+	for _, tc := range tests {
+		cc := &config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ImageRepository: tc.repo}}
+		if got := repositoryHost(cc); got != tc.want {
+			t.Errorf("repositoryHost(%q) = %q, want %q", tc.repo, got, tc.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	probes["test-passed"] = Probe{Description: "passes", Check: func(Env) error { return nil }}
+	probes["test-failed"] = Probe{Description: "fails", Check: func(Env) error { return errors.New("broken") }}
+	defer delete(probes, "test-passed")
+	defer delete(probes, "test-failed")
+
+	rs := Run([]string{"test-failed", "test-passed", DiskSpace, "unknown"}, Env{Profile: "p"})
+	want := []Result{
+		{Name: "test-failed", Description: "fails", Status: Failed, Detail: "broken"},
+		{Name: "test-passed", Description: "passes", Status: Passed},
+		{Name: DiskSpace, Description: probes[DiskSpace].Description, Status: Skipped, Detail: "the node is not running"},
+		{Name: "unknown", Status: Skipped, Detail: "unknown probe"},
+	}
+	if fmt.Sprint(rs) != fmt.Sprint(want) {
+		t.Errorf("Run() = %v, want %v", rs, want)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ProfileDomain returns the domain whose names all resolve to the node of a profile
+func ProfileDomain(profile string) string {
+	return profile + ".test"
+}
+
+// Resolver answers for the hostnames of a cluster by looking them up through the Kubernetes API:
+// every name below the profile domain, the names of services, and the hosts of Ingress rules.
+type Resolver struct {
+	client kubernetes.Interface
+	// profileDomain is the domain whose names resolve to the node, such as minikube.test
+	profileDomain string
+	// clusterDomain is the DNS domain of the cluster, such as cluster.local
+	clusterDomain string
+	// nodeIP is the IP of the node, which runs the ingress controller
+	nodeIP net.IP
+}
+
+// NewResolver returns a resolver for a cluster
+func NewResolver(client kubernetes.Interface, profile string, clusterDomain string, nodeIP net.IP) *Resolver {
+	return &Resolver{
+		client:        client,
+		profileDomain: ProfileDomain(profile),
+		clusterDomain: clusterDomain,
+		nodeIP:        nodeIP,
+	}
+}
+
+// Lookup returns the IPs of a hostname, or none if the name is unknown
+func (r *Resolver) Lookup(name string) ([]net.IP, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	if within(name, r.profileDomain) {
+		return []net.IP{r.nodeIP}, nil
+	}
+	if within(name, r.clusterDomain) {
+		return r.lookupService(name)
+	}
+	return r.lookupIngress(name)
+}
+
+// Domains returns the domains the resolver answers for, for split DNS: the profile and cluster domains,
+// and the hosts of Ingress rules.
+func (r *Resolver) Domains() ([]string, error) {
+	domains := map[string]bool{r.profileDomain: true, r.clusterDomain: true}
+	ingresses, err := r.client.NetworkingV1beta1().Ingresses("").List(meta.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing ingresses")
+	}
+	for _, ing := range ingresses.Items {
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host == "" {
+				continue
+			}
+			host := strings.ToLower(strings.TrimPrefix(rule.Host, "*."))
+			if !within(host, r.profileDomain) && !within(host, r.clusterDomain) {
+				domains[host] = true
+			}
+		}
+	}
+
+	var ds []string
+	for d := range domains {
+		ds = append(ds, d)
+	}
+	sort.Strings(ds)
+	return ds, nil
+}
+
+// lookupService resolves <service>.<namespace>.svc.<cluster domain> to the ingress IP of LoadBalancer
+// services exposed by the tunnel, or else to the ClusterIP.
+func (r *Resolver) lookupService(name string) ([]net.IP, error) {
+	labels := strings.Split(strings.TrimSuffix(name, "."+r.clusterDomain), ".")
+	if len(labels) != 3 || labels[2] != "svc" {
+		return nil, nil
+	}
+	svc, err := r.client.CoreV1().Services(labels[1]).Get(labels[0], meta.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting service %s/%s", labels[1], labels[0])
+	}
+
+	if ips := ingressIPs(svc.Status.LoadBalancer.Ingress); len(ips) > 0 {
+		return ips, nil
+	}
+	if ip := net.ParseIP(svc.Spec.ClusterIP); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	return nil, nil
+}
+
+// lookupIngress resolves the host of an Ingress rule to the IP of its load balancer, or else to the node
+func (r *Resolver) lookupIngress(name string) ([]net.IP, error) {
+	ingresses, err := r.client.NetworkingV1beta1().Ingresses("").List(meta.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing ingresses")
+	}
+	for _, ing := range ingresses.Items {
+		for _, rule := range ing.Spec.Rules {
+			if !matchHost(rule.Host, name) {
+				continue
+			}
+			if ips := ingressIPs(ing.Status.LoadBalancer.Ingress); len(ips) > 0 {
+				return ips, nil
+			}
+			return []net.IP{r.nodeIP}, nil
+		}
+	}
+	return nil, nil
+}
+
+// ingressIPs returns the IPs of load balancer ingresses
+func ingressIPs(ingresses []core.LoadBalancerIngress) []net.IP {
+	var ips []net.IP
+	for _, i := range ingresses {
+		if ip := net.ParseIP(i.IP); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// matchHost returns whether a name matches the host of an Ingress rule, which may start with a wildcard label
+func matchHost(host string, name string) bool {
+	host = strings.ToLower(host)
+	if host == "" {
+		return false
+	}
+	if strings.HasPrefix(host, "*.") {
+		suffix := host[1:]
+		return strings.HasSuffix(name, suffix) && !strings.Contains(strings.TrimSuffix(name, suffix), ".")
+	}
+	return host == name
+}
+
+// within returns whether name is domain or below it
+func within(name string, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
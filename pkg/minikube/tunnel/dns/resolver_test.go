@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testResolver() *Resolver {
+	client := fake.NewSimpleClientset(
+		&core.Service{
+			ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       core.ServiceSpec{ClusterIP: "10.96.0.20"},
+		},
+		&core.Service{
+			ObjectMeta: meta.ObjectMeta{Name: "lb", Namespace: "apps"},
+			Spec:       core.ServiceSpec{Type: core.ServiceTypeLoadBalancer, ClusterIP: "10.96.0.30"},
+			Status:     core.ServiceStatus{LoadBalancer: core.LoadBalancerStatus{Ingress: []core.LoadBalancerIngress{{IP: "127.0.0.1"}}}},
+		},
+		&core.Service{
+			ObjectMeta: meta.ObjectMeta{Name: "headless", Namespace: "default"},
+			Spec:       core.ServiceSpec{ClusterIP: "None"},
+		},
+		&networking.Ingress{
+			ObjectMeta: meta.ObjectMeta{Name: "hello", Namespace: "default"},
+			Spec: networking.IngressSpec{Rules: []networking.IngressRule{
+				{Host: "hello.example.com"},
+				{Host: "*.apps.example.com"},
+				{Host: "api.minikube.test"},
+			}},
+		},
+	)
+	return NewResolver(client, "minikube", "cluster.local", net.ParseIP("192.168.39.10"))
+}
+
+func TestLookup(t *testing.T) {
+	r := testResolver()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "minikube.test.", want: "192.168.39.10"},
+		{name: "Grafana.Minikube.test.", want: "192.168.39.10"},
+		{name: "web.default.svc.cluster.local.", want: "10.96.0.20"},
+		{name: "lb.apps.svc.cluster.local.", want: "127.0.0.1"},
+		{name: "headless.default.svc.cluster.local.", want: ""},
+		{name: "missing.default.svc.cluster.local.", want: ""},
+		{name: "web.default.cluster.local.", want: ""},
+		{name: "hello.example.com.", want: "192.168.39.10"},
+		{name: "shop.apps.example.com.", want: "192.168.39.10"},
+		{name: "a.shop.apps.example.com.", want: ""},
+		{name: "example.com.", want: ""},
+	}
+	for _, tc := range tests {
+		ips, err := r.Lookup(tc.name)
+		if err != nil {
+			t.Errorf("Lookup(%s): %v", tc.name, err)
+			continue
+		}
+		got := ""
+		if len(ips) > 0 {
+			got = ips[0].String()
+		}
+		if got != tc.want {
+			t.Errorf("Lookup(%s) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDomains(t *testing.T) {
+	got, err := testResolver().Domains()
+	if err != nil {
+		t.Fatalf("Domains: %v", err)
+	}
+	want := []string{"apps.example.com", "cluster.local", "hello.example.com", "minikube.test"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Domains mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// refreshInterval is how often the domains of Ingress rules are synced to the split DNS configuration
+const refreshInterval = 10 * time.Second
+
+// Start answers DNS queries for a cluster on the host until the context is done. The host resolver is
+// configured to route the domains of the cluster to the responder, and restored on the way out.
+func Start(ctx context.Context, name string, r *Resolver, port int) error {
+	link, src, err := Link(r.nodeIP)
+	if err != nil {
+		return errors.Wrap(err, "finding the interface to the cluster")
+	}
+
+	srv, err := NewServer(net.JoinHostPort(src.String(), strconv.Itoa(port)), r)
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+	go func() {
+		if err := srv.Serve(); err != nil {
+			glog.Infof("DNS responder stopped: %v", err)
+		}
+	}()
+
+	split, err := NewSplitDNS(name, link, srv.Addr().(*net.UDPAddr))
+	if err != nil {
+		return errors.Wrap(err, "configuring split DNS")
+	}
+	defer func() {
+		if err := split.Teardown(); err != nil {
+			glog.Errorf("unable to restore DNS configuration: %v", err)
+		}
+	}()
+
+	var current []string
+	for {
+		domains, err := r.Domains()
+		if err != nil {
+			glog.Warningf("unable to list domains: %v", err)
+		} else if !reflect.DeepEqual(domains, current) {
+			glog.Infof("routing DNS queries for %v to %s", domains, srv.Addr())
+			if err := split.Update(domains); err != nil {
+				return errors.Wrap(err, "updating split DNS")
+			}
+			current = domains
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(refreshInterval):
+		}
+	}
+}
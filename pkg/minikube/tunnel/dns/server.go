@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// ttl is the number of seconds answers may be cached for, short as services and ingresses come and go
+const ttl = 5
+
+// lookuper returns the IPs of a hostname
+type lookuper interface {
+	Lookup(name string) ([]net.IP, error)
+}
+
+// Server is a DNS responder on the host for the hostnames of a cluster
+type Server struct {
+	resolver lookuper
+	conn     net.PacketConn
+}
+
+// NewServer listens for DNS queries over UDP on addr
+func NewServer(addr string, r *Resolver) (*Server, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listen on %s", addr)
+	}
+	return &Server{resolver: r, conn: conn}, nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Serve answers queries until the server is closed
+func (s *Server) Serve() error {
+	for {
+		buf := make([]byte, 512)
+		n, client, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		go func() {
+			resp, err := s.answer(buf[:n])
+			if err != nil {
+				glog.Warningf("unable to answer DNS query from %s: %v", client, err)
+				return
+			}
+			if _, err := s.conn.WriteTo(resp, client); err != nil {
+				glog.Warningf("unable to send DNS answer to %s: %v", client, err)
+			}
+		}()
+	}
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	return s.conn.Close()
+}
+
+// answer builds the response to a query
+func (s *Server) answer(req []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing header")
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing question")
+	}
+
+	rcode := dnsmessage.RCodeSuccess
+	ips, err := s.resolver.Lookup(q.Name.String())
+	if err != nil {
+		glog.Warningf("lookup %s: %v", q.Name, err)
+		rcode = dnsmessage.RCodeServerFailure
+	} else if len(ips) == 0 {
+		rcode = dnsmessage.RCodeNameError
+	}
+
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{
+		ID:               h.ID,
+		Response:         true,
+		Authoritative:    true,
+		RecursionDesired: h.RecursionDesired,
+		RCode:            rcode,
+	})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: ttl}
+	for _, ip := range ips {
+		switch {
+		case q.Type == dnsmessage.TypeA && ip.To4() != nil:
+			var a [4]byte
+			copy(a[:], ip.To4())
+			err = b.AResource(rh, dnsmessage.AResource{A: a})
+		case q.Type == dnsmessage.TypeAAAA && ip.To4() == nil:
+			var aaaa [16]byte
+			copy(aaaa[:], ip.To16())
+			err = b.AAAAResource(rh, dnsmessage.AAAAResource{AAAA: aaaa})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+type staticLookuper map[string][]net.IP
+
+func (s staticLookuper) Lookup(name string) ([]net.IP, error) {
+	return s[name], nil
+}
+
+func query(t *testing.T, name string, typ dnsmessage.Type) []byte {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 42, RecursionDesired: true})
+	if err := b.StartQuestions(); err != nil {
+		t.Fatalf("start questions: %v", err)
+	}
+	if err := b.Question(dnsmessage.Question{Name: dnsmessage.MustNewName(name), Type: typ, Class: dnsmessage.ClassINET}); err != nil {
+		t.Fatalf("question: %v", err)
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	return msg
+}
+
+func TestAnswer(t *testing.T) {
+	s := &Server{resolver: staticLookuper{
+		"web.minikube.test.": {net.ParseIP("192.168.39.10")},
+	}}
+
+	tests := []struct {
+		name    string
+		typ     dnsmessage.Type
+		rcode   dnsmessage.RCode
+		answers int
+	}{
+		{name: "web.minikube.test.", typ: dnsmessage.TypeA, rcode: dnsmessage.RCodeSuccess, answers: 1},
+		{name: "web.minikube.test.", typ: dnsmessage.TypeAAAA, rcode: dnsmessage.RCodeSuccess, answers: 0},
+		{name: "db.minikube.test.", typ: dnsmessage.TypeA, rcode: dnsmessage.RCodeNameError, answers: 0},
+	}
+	for _, tc := range tests {
+		resp, err := s.answer(query(t, tc.name, tc.typ))
+		if err != nil {
+			t.Errorf("answer(%s): %v", tc.name, err)
+			continue
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(resp); err != nil {
+			t.Errorf("unpack: %v", err)
+			continue
+		}
+		if msg.Header.ID != 42 || !msg.Header.Response {
+			t.Errorf("unexpected header: %+v", msg.Header)
+		}
+		if msg.Header.RCode != tc.rcode {
+			t.Errorf("%s %s: rcode = %v, want %v", tc.name, tc.typ, msg.Header.RCode, tc.rcode)
+		}
+		if len(msg.Answers) != tc.answers {
+			t.Errorf("%s %s: got %d answers, want %d", tc.name, tc.typ, len(msg.Answers), tc.answers)
+		}
+	}
+}
+
+func TestParseRouteGet(t *testing.T) {
+	link, src, err := parseRouteGet("192.168.39.10 dev virbr1 src 192.168.39.1 uid 1000 \n    cache \n")
+	if err != nil {
+		t.Fatalf("parseRouteGet: %v", err)
+	}
+	if link != "virbr1" || src.String() != "192.168.39.1" {
+		t.Errorf("parseRouteGet = %s, %s, want virbr1, 192.168.39.1", link, src)
+	}
+	if _, _, err := parseRouteGet("RTNETLINK answers: Network is unreachable"); err == nil {
+		t.Errorf("expected error for unreachable IP")
+	}
+}
+
+func TestDnsmasqConfig(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.ParseIP("172.17.0.1"), Port: 5353}
+	got := dnsmasqConfig(addr, []string{"cluster.local", "minikube.test"})
+	want := "server=/cluster.local/172.17.0.1#5353\nserver=/minikube.test/172.17.0.1#5353\n"
+	if got != want {
+		t.Errorf("dnsmasqConfig = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SplitDNS routes the queries for some domains to the responder, through the resolver of the host
+type SplitDNS interface {
+	// Update sets the domains whose queries are routed to the responder
+	Update(domains []string) error
+	// Teardown restores the DNS configuration of the host
+	Teardown() error
+}
+
+// parseRouteGet returns the interface and source address from the output of `ip route get`
+func parseRouteGet(out string) (link string, src net.IP, err error) {
+	fields := strings.Fields(out)
+	for i := 0; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "dev":
+			link = fields[i+1]
+		case "src":
+			src = net.ParseIP(fields[i+1])
+		}
+	}
+	if link == "" || src == nil {
+		return "", nil, fmt.Errorf("no interface found in %q", out)
+	}
+	return link, src, nil
+}
+
+// dnsmasqConfig returns the NetworkManager dnsmasq configuration routing domains to the responder
+func dnsmasqConfig(addr *net.UDPAddr, domains []string) string {
+	var b strings.Builder
+	for _, d := range domains {
+		fmt.Fprintf(&b, "server=/%s/%s#%d\n", d, addr.IP, addr.Port)
+	}
+	return b.String()
+}
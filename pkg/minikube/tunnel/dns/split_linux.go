@@ -0,0 +1,139 @@
+// +build linux
+
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// nmDnsmasqDir holds the dnsmasq configuration of NetworkManager
+const nmDnsmasqDir = "/etc/NetworkManager/dnsmasq.d"
+
+// Link returns the interface of the host that reaches an IP, and the address of the host on it
+func Link(ip net.IP) (string, net.IP, error) {
+	cmd := exec.Command("ip", "route", "get", ip.String())
+	cmd.Env = append(cmd.Env, "LC_ALL=C")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "ip route get: %s", out)
+	}
+	return parseRouteGet(string(out))
+}
+
+// NewSplitDNS configures the host to send the queries for some domains to the responder at addr, which
+// listens on link. systemd-resolved is preferred, then the dnsmasq plugin of NetworkManager.
+func NewSplitDNS(name string, link string, addr *net.UDPAddr) (SplitDNS, error) {
+	if _, err := exec.LookPath("resolvectl"); err == nil && exec.Command("systemctl", "is-active", "--quiet", "systemd-resolved").Run() == nil {
+		return newResolved(link, addr)
+	}
+	if _, err := exec.LookPath("nmcli"); err == nil {
+		if _, err := os.Stat(nmDnsmasqDir); err == nil {
+			return &networkManager{path: fmt.Sprintf("%s/minikube-%s.conf", nmDnsmasqDir, name), addr: addr}, nil
+		}
+	}
+	return nil, errors.New("neither systemd-resolved nor the dnsmasq plugin of NetworkManager is in use")
+}
+
+// resolved configures systemd-resolved with a DNS server and routing domains for a link
+type resolved struct {
+	link string
+}
+
+func newResolved(link string, addr *net.UDPAddr) (*resolved, error) {
+	server := addr.IP.String()
+	if addr.Port != 53 {
+		// servers with a port are supported since systemd 246
+		server = net.JoinHostPort(server, strconv.Itoa(addr.Port))
+	}
+	r := &resolved{link: link}
+	if err := sudo("resolvectl", "dns", link, server); err != nil {
+		return nil, err
+	}
+	// only the routed domains should be sent to the responder
+	if err := sudo("resolvectl", "default-route", link, "false"); err != nil {
+		glog.Warningf("unable to unset the default DNS route of %s: %v", link, err)
+	}
+	return r, nil
+}
+
+// Update sets the routing domains of the link
+func (r *resolved) Update(domains []string) error {
+	args := []string{"resolvectl", "domain", r.link}
+	for _, d := range domains {
+		args = append(args, "~"+d)
+	}
+	return sudo(args...)
+}
+
+// Teardown reverts the DNS configuration of the link
+func (r *resolved) Teardown() error {
+	return sudo("resolvectl", "revert", r.link)
+}
+
+// networkManager routes domains to the responder with a dnsmasq configuration file
+type networkManager struct {
+	path string
+	addr *net.UDPAddr
+}
+
+// Update rewrites the configuration file and reloads the DNS configuration of NetworkManager
+func (n *networkManager) Update(domains []string) error {
+	tf, err := ioutil.TempFile("", "minikube-dnsmasq-")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(tf.Name())
+
+	if _, err = tf.WriteString(dnsmasqConfig(n.addr, domains)); err != nil {
+		return errors.Wrap(err, "write")
+	}
+	if err = tf.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+	if err := sudo("cp", tf.Name(), n.path); err != nil {
+		return err
+	}
+	return sudo("nmcli", "general", "reload", "dns-full")
+}
+
+// Teardown removes the configuration file and reloads the DNS configuration of NetworkManager
+func (n *networkManager) Teardown() error {
+	if err := sudo("rm", "-f", n.path); err != nil {
+		return err
+	}
+	return sudo("nmcli", "general", "reload", "dns-full")
+}
+
+// sudo runs a command as root
+func sudo(args ...string) error {
+	cmd := exec.Command("sudo", args...)
+	glog.Infof("About to run command: %s", cmd.Args)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%s: %s", cmd.Args, out)
+	}
+	return nil
+}
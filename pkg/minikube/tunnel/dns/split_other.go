@@ -0,0 +1,35 @@
+// +build !linux
+
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Link is only supported on Linux
+func Link(ip net.IP) (string, net.IP, error) {
+	return "", nil, errors.New("the DNS responder is only supported on linux")
+}
+
+// NewSplitDNS is only supported on Linux
+func NewSplitDNS(name string, link string, addr *net.UDPAddr) (SplitDNS, error) {
+	return nil, errors.New("the DNS responder is only supported on linux")
+}
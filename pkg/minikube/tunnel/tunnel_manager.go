@@ -25,6 +25,7 @@ import (
 
 	"github.com/docker/machine/libmachine"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/localpath"
@@ -47,6 +48,31 @@ func RegistryPath() string {
 	return filepath.Join(localpath.MiniPath(), "tunnels.json")
 }
 
+// RunningTunnels returns the tunnels registered for a machine whose processes are still running
+func RunningTunnels(machineName string) ([]*ID, error) {
+	r := &persistentRegistry{path: RegistryPath()}
+	tunnels, err := r.List()
+	if err != nil {
+		return nil, errors.Wrap(err, "list tunnels")
+	}
+
+	running := []*ID{}
+	for _, t := range tunnels {
+		if t.MachineName != machineName {
+			continue
+		}
+		ok, err := checkIfRunning(t.Pid)
+		if err != nil {
+			glog.Warningf("unable to check tunnel process %d: %v", t.Pid, err)
+			continue
+		}
+		if ok {
+			running = append(running, t)
+		}
+	}
+	return running, nil
+}
+
 // NewManager creates a new Manager
 func NewManager() *Manager {
 	return &Manager{
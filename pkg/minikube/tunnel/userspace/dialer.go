@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userspace
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	core "k8s.io/api/core/v1"
+)
+
+// Dialer connects to a port of a service in the cluster
+type Dialer interface {
+	Dial(svc core.Service, port core.ServicePort) (net.Conn, error)
+}
+
+// NodePortDialer reaches services through their NodePort, for nodes that are reachable from the host
+type NodePortDialer struct {
+	// IP is the address of a node
+	IP string
+}
+
+// Dial connects to the NodePort of a service port on the node
+func (d NodePortDialer) Dial(svc core.Service, port core.ServicePort) (net.Conn, error) {
+	if port.NodePort == 0 {
+		return nil, fmt.Errorf("%s/%s has no NodePort for port %d", svc.Namespace, svc.Name, port.Port)
+	}
+	return net.Dial(network(port), net.JoinHostPort(d.IP, strconv.Itoa(int(port.NodePort))))
+}
+
+// SSHDialer reaches the ClusterIP of services through an SSH connection to a node,
+// for nodes that can't be reached directly, such as containers of Docker Desktop.
+type SSHDialer struct {
+	Client *ssh.Client
+}
+
+// Dial connects to the ClusterIP of a service port from the node. Only TCP is supported.
+func (d SSHDialer) Dial(svc core.Service, port core.ServicePort) (net.Conn, error) {
+	if network(port) != "tcp" {
+		return nil, errors.Errorf("%s cannot be forwarded over SSH", port.Protocol)
+	}
+	return d.Client.Dial("tcp", net.JoinHostPort(svc.Spec.ClusterIP, strconv.Itoa(int(port.Port))))
+}
+
+// network returns the Go network name of the protocol of a service port
+func network(port core.ServicePort) string {
+	if port.Protocol == core.ProtocolUDP {
+		return "udp"
+	}
+	return "tcp"
+}
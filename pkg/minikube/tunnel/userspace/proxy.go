@@ -0,0 +1,243 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userspace
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+)
+
+// udpIdleTimeout is how long a UDP client may stay silent before its upstream connection is closed
+const udpIdleTimeout = 2 * time.Minute
+
+// proxy forwards a port of the host to a port of a LoadBalancer service
+type proxy struct {
+	svc    core.Service
+	port   core.ServicePort
+	dialer Dialer
+
+	listener net.Listener
+	packets  net.PacketConn
+
+	mu      sync.Mutex
+	stopped bool
+	conns   map[net.Conn]bool
+	// sessions are the upstream connections of UDP clients, by client address
+	sessions map[string]net.Conn
+}
+
+// newProxy listens on listenPort of bindIP, and starts forwarding it to the port of a service
+func newProxy(bindIP string, listenPort int, svc core.Service, port core.ServicePort, dialer Dialer) (*proxy, error) {
+	p := &proxy{
+		svc:      svc,
+		port:     port,
+		dialer:   dialer,
+		conns:    map[net.Conn]bool{},
+		sessions: map[string]net.Conn{},
+	}
+	addr := net.JoinHostPort(bindIP, strconv.Itoa(listenPort))
+
+	var err error
+	if network(port) == "udp" {
+		p.packets, err = net.ListenPacket("udp", addr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listen on %s/udp", addr)
+		}
+		go p.serveUDP()
+		return p, nil
+	}
+
+	p.listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listen on %s/tcp", addr)
+	}
+	go p.serveTCP()
+	return p, nil
+}
+
+// addr returns the address the proxy listens on
+func (p *proxy) addr() net.Addr {
+	if p.packets != nil {
+		return p.packets.LocalAddr()
+	}
+	return p.listener.Addr()
+}
+
+// stop stops listening, and closes every forwarded connection, including those still dialing the service
+func (p *proxy) stop() error {
+	var err error
+	if p.listener != nil {
+		err = p.listener.Close()
+	}
+	if p.packets != nil {
+		err = p.packets.Close()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	for c := range p.conns {
+		c.Close()
+	}
+	for _, c := range p.sessions {
+		c.Close()
+	}
+	return err
+}
+
+// track records an open connection so that it can be closed by stop. Connections opened once the proxy
+// is stopped are closed right away, and track returns false.
+func (p *proxy) track(c net.Conn, open bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !open {
+		delete(p.conns, c)
+		return true
+	}
+	if p.stopped {
+		c.Close()
+		return false
+	}
+	p.conns[c] = true
+	return true
+}
+
+func (p *proxy) serveTCP() {
+	for {
+		c, err := p.listener.Accept()
+		if err != nil {
+			glog.Infof("stopped forwarding %s: %v", p.addr(), err)
+			return
+		}
+		go p.forwardTCP(c)
+	}
+}
+
+func (p *proxy) forwardTCP(c net.Conn) {
+	// the client is tracked while the service is dialed, so that stopping the proxy closes it meanwhile
+	if !p.track(c, true) {
+		return
+	}
+	defer func() {
+		c.Close()
+		p.track(c, false)
+	}()
+
+	up, err := p.dialer.Dial(p.svc, p.port)
+	if err != nil {
+		glog.Warningf("unable to reach %s/%s:%d: %v", p.svc.Namespace, p.svc.Name, p.port.Port, err)
+		return
+	}
+	if !p.track(up, true) {
+		return
+	}
+	defer func() {
+		up.Close()
+		p.track(up, false)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		copyAndClose(up, c)
+	}()
+	go func() {
+		defer wg.Done()
+		copyAndClose(c, up)
+	}()
+	wg.Wait()
+}
+
+// copyAndClose copies until src is exhausted, then closes the write side of dst so that the peer sees EOF
+func copyAndClose(dst net.Conn, src net.Conn) {
+	if _, err := io.Copy(dst, src); err != nil {
+		glog.V(3).Infof("copy: %v", err)
+	}
+	if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+	} else {
+		dst.Close()
+	}
+}
+
+func (p *proxy) serveUDP() {
+	buf := make([]byte, 65535)
+	for {
+		n, client, err := p.packets.ReadFrom(buf)
+		if err != nil {
+			glog.Infof("stopped forwarding %s: %v", p.addr(), err)
+			return
+		}
+		up, err := p.session(client)
+		if err != nil {
+			glog.Warningf("unable to reach %s/%s:%d: %v", p.svc.Namespace, p.svc.Name, p.port.Port, err)
+			continue
+		}
+		if _, err := up.Write(buf[:n]); err != nil {
+			glog.Warningf("unable to forward packet from %s: %v", client, err)
+		}
+	}
+}
+
+// session returns the upstream connection of a UDP client, dialing one and relaying its replies if needed
+func (p *proxy) session(client net.Addr) (net.Conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return nil, errors.New("proxy is stopped")
+	}
+	if up, ok := p.sessions[client.String()]; ok {
+		return up, nil
+	}
+
+	up, err := p.dialer.Dial(p.svc, p.port)
+	if err != nil {
+		return nil, err
+	}
+	p.sessions[client.String()] = up
+
+	go func() {
+		defer func() {
+			up.Close()
+			p.mu.Lock()
+			delete(p.sessions, client.String())
+			p.mu.Unlock()
+		}()
+		buf := make([]byte, 65535)
+		for {
+			if err := up.SetReadDeadline(time.Now().Add(udpIdleTimeout)); err != nil {
+				return
+			}
+			n, err := up.Read(buf)
+			if err != nil {
+				return
+			}
+			if _, err := p.packets.WriteTo(buf[:n], client); err != nil {
+				return
+			}
+		}
+	}()
+	return up, nil
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userspace
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+// Tunnel exposes LoadBalancer services on the host with in-process proxies, so that no root privileges
+// are needed to change the routes of the host.
+type Tunnel struct {
+	ctx                  context.Context
+	bindIP               string
+	portOffset           int
+	dialer               Dialer
+	v1Core               typed_core.CoreV1Interface
+	LoadBalancerEmulator tunnel.LoadBalancerEmulator
+
+	// patchIP sets the ingress IP of a service
+	patchIP func(svc core.Service, ip string) error
+	// services are the exposed services, by namespace/name
+	services map[string]*exposed
+}
+
+// exposed are the proxies of a service, and the spec they were created for
+type exposed struct {
+	fingerprint string
+	proxies     []*proxy
+}
+
+// privilegedPorts are the ports that only root may listen on, on most systems
+const privilegedPorts = 1024
+
+// NewTunnel creates a tunnel listening on bindIP, that reaches services with dialer. Privileged ports of
+// services are listened on with portOffset added, if it is set.
+func NewTunnel(ctx context.Context, bindIP string, portOffset int, dialer Dialer, v1Core typed_core.CoreV1Interface) *Tunnel {
+	t := &Tunnel{
+		ctx:                  ctx,
+		bindIP:               bindIP,
+		portOffset:           portOffset,
+		dialer:               dialer,
+		v1Core:               v1Core,
+		LoadBalancerEmulator: tunnel.NewLoadBalancerEmulator(v1Core),
+		services:             map[string]*exposed{},
+	}
+	t.patchIP = func(svc core.Service, ip string) error {
+		return t.LoadBalancerEmulator.PatchServiceIP(t.v1Core.RESTClient(), svc, ip)
+	}
+	return t
+}
+
+// Start exposes LoadBalancer services as they are created, changed and deleted, until the context is done.
+// The ingress of every LoadBalancer service is unset on the way out.
+func (t *Tunnel) Start() error {
+	for {
+		if err := t.watch(); err != nil {
+			glog.Warningf("error watching services: %v", err)
+		}
+
+		select {
+		case <-t.ctx.Done():
+			for k := range t.services {
+				t.remove(k)
+			}
+			_, err := t.LoadBalancerEmulator.Cleanup()
+			if err != nil {
+				glog.Errorf("error cleaning up: %v", err)
+			}
+			return err
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// watch syncs every service, then each service event until the watch ends
+func (t *Tunnel) watch() error {
+	services, err := t.v1Core.Services("").List(meta.ListOptions{})
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, svc := range services.Items {
+		seen[key(svc)] = true
+		t.sync(svc)
+	}
+	for k := range t.services {
+		if !seen[k] {
+			t.remove(k)
+		}
+	}
+
+	w, err := t.v1Core.Services("").Watch(meta.ListOptions{ResourceVersion: services.ResourceVersion})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-t.ctx.Done():
+			return nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				// the API server ends watches from time to time, and the next one starts from a fresh list
+				return nil
+			}
+			svc, ok := ev.Object.(*core.Service)
+			if !ok {
+				glog.Warningf("unexpected watch event: %+v", ev)
+				continue
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				t.sync(*svc)
+			case watch.Deleted:
+				t.remove(key(*svc))
+			}
+		}
+	}
+}
+
+// sync makes sure that a LoadBalancer service is exposed for its current spec, and that other services aren't
+func (t *Tunnel) sync(svc core.Service) {
+	k := key(svc)
+	if svc.Spec.Type != core.ServiceTypeLoadBalancer {
+		t.remove(k)
+		return
+	}
+
+	fp := fingerprint(svc)
+	if e, ok := t.services[k]; !ok || e.fingerprint != fp {
+		t.remove(k)
+		t.services[k] = t.expose(svc, fp)
+	}
+
+	ip := t.ingressIP()
+	ingresses := svc.Status.LoadBalancer.Ingress
+	if len(ingresses) == 1 && ingresses[0].IP == ip {
+		return
+	}
+	if err := t.patchIP(svc, ip); err != nil {
+		glog.Errorf("error patching service: %v", err)
+	}
+}
+
+// expose starts a proxy for every port of a service
+func (t *Tunnel) expose(svc core.Service, fp string) *exposed {
+	e := &exposed{fingerprint: fp}
+	var ports []string
+	for _, port := range svc.Spec.Ports {
+		if port.Protocol == core.ProtocolSCTP {
+			out.WarningT("Port {{.port}} of {{.service}} uses SCTP, which cannot be forwarded", out.V{"port": port.Port, "service": svc.Name})
+			continue
+		}
+		p, err := t.listen(svc, port)
+		if err != nil {
+			out.WarningT("Unable to expose port {{.port}} of {{.service}}: {{.error}}", out.V{"port": port.Port, "service": svc.Name, "error": err})
+			continue
+		}
+		e.proxies = append(e.proxies, p)
+		if lp := listenedPort(p); lp != int(port.Port) {
+			ports = append(ports, fmt.Sprintf("%d->%d/%s", lp, port.Port, network(port)))
+		} else {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, network(port)))
+		}
+	}
+	if len(ports) > 0 {
+		out.T(out.Running, "Exposing service {{.service}} on {{.ip}}: {{.ports}}", out.V{"service": svc.Name, "ip": t.ingressIP(), "ports": strings.Join(ports, ", ")})
+	}
+	return e
+}
+
+// listen starts a proxy for a port of a service. Privileged ports are offset by portOffset, or fall back to
+// an unprivileged port picked by the OS if listening on them is not permitted.
+func (t *Tunnel) listen(svc core.Service, port core.ServicePort) (*proxy, error) {
+	listenPort := int(port.Port)
+	if t.portOffset > 0 && listenPort < privilegedPorts {
+		listenPort += t.portOffset
+	}
+	p, err := newProxy(t.bindIP, listenPort, svc, port, t.dialer)
+	if err == nil || listenPort >= privilegedPorts || !errors.Is(err, os.ErrPermission) {
+		return p, err
+	}
+
+	p, err = newProxy(t.bindIP, 0, svc, port, t.dialer)
+	if err != nil {
+		return nil, err
+	}
+	out.WarningT("Listening on port {{.port}} of {{.service}} requires root, so it is exposed on port {{.unprivileged}} instead. Use --port-offset to choose where privileged ports are exposed.", out.V{"port": port.Port, "service": svc.Name, "unprivileged": listenedPort(p)})
+	return p, nil
+}
+
+// listenedPort returns the port a proxy listens on
+func listenedPort(p *proxy) int {
+	switch a := p.addr().(type) {
+	case *net.TCPAddr:
+		return a.Port
+	case *net.UDPAddr:
+		return a.Port
+	}
+	return 0
+}
+
+// remove stops the proxies of a service, if any
+func (t *Tunnel) remove(k string) {
+	e, ok := t.services[k]
+	if !ok {
+		return
+	}
+	for _, p := range e.proxies {
+		if err := p.stop(); err != nil {
+			glog.Warningf("error stopping proxy for %s: %v", k, err)
+		}
+	}
+	delete(t.services, k)
+	out.T(out.Deleted, "Stopped exposing service {{.service}}", out.V{"service": k})
+}
+
+// ingressIP returns the IP set as the ingress of services
+func (t *Tunnel) ingressIP() string {
+	ip := net.ParseIP(t.bindIP)
+	if ip == nil || ip.IsUnspecified() {
+		return "127.0.0.1"
+	}
+	return t.bindIP
+}
+
+// key identifies a service
+func key(svc core.Service) string {
+	return svc.Namespace + "/" + svc.Name
+}
+
+// fingerprint changes whenever the proxies of a service have to be recreated
+func fingerprint(svc core.Service) string {
+	n := []string{svc.Spec.ClusterIP}
+	for _, port := range svc.Spec.Ports {
+		n = append(n, fmt.Sprintf("%s:%d:%d", port.Protocol, port.Port, port.NodePort))
+	}
+	return strings.Join(n, "-")
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userspace
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// localDialer reaches every service port on a local address
+type localDialer struct {
+	addr string
+}
+
+func (d localDialer) Dial(svc core.Service, port core.ServicePort) (net.Conn, error) {
+	return net.Dial(network(port), d.addr)
+}
+
+func TestProxyTCP(t *testing.T) {
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer upstream.Close()
+	go func() {
+		for {
+			c, err := upstream.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				line, _ := bufio.NewReader(c).ReadString('\n')
+				c.Write([]byte("echo " + line))
+			}()
+		}
+	}()
+
+	p, err := newProxy("127.0.0.1", 0, core.Service{}, core.ServicePort{Port: 0}, localDialer{upstream.Addr().String()})
+	if err != nil {
+		t.Fatalf("newProxy: %v", err)
+	}
+	defer p.stop()
+
+	c, err := net.Dial("tcp", p.addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+	if _, err := c.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if got != "echo hello\n" {
+		t.Errorf("got %q, want %q", got, "echo hello\n")
+	}
+}
+
+func TestProxyUDP(t *testing.T) {
+	upstream, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer upstream.Close()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := upstream.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			upstream.WriteTo(append([]byte("echo "), buf[:n]...), addr)
+		}
+	}()
+
+	port := core.ServicePort{Port: 0, Protocol: core.ProtocolUDP}
+	p, err := newProxy("127.0.0.1", 0, core.Service{}, port, localDialer{upstream.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("newProxy: %v", err)
+	}
+	defer p.stop()
+
+	c, err := net.Dial("udp", p.addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+	if _, err := c.Write([]byte("hello")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := c.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("deadline: %v", err)
+	}
+	buf := make([]byte, 1024)
+	n, err := c.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if got := string(buf[:n]); got != "echo hello" {
+		t.Errorf("got %q, want %q", got, "echo hello")
+	}
+}
+
+// blockingDialer dials once it is released, so that proxies can be stopped while dialing
+type blockingDialer struct {
+	release chan struct{}
+	dialed  chan net.Conn
+}
+
+func (d blockingDialer) Dial(svc core.Service, port core.ServicePort) (net.Conn, error) {
+	<-d.release
+	c, s := net.Pipe()
+	d.dialed <- s
+	return c, nil
+}
+
+func TestProxyStopWhileDialing(t *testing.T) {
+	d := blockingDialer{release: make(chan struct{}), dialed: make(chan net.Conn, 1)}
+	p, err := newProxy("127.0.0.1", 0, core.Service{}, core.ServicePort{}, d)
+	if err != nil {
+		t.Fatalf("newProxy: %v", err)
+	}
+
+	c, err := net.Dial("tcp", p.addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+	// wait for the proxy to accept the client
+	for {
+		p.mu.Lock()
+		n := len(p.conns)
+		p.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := p.stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if err := c.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("deadline: %v", err)
+	}
+	if _, err := c.Read(make([]byte, 1)); err == nil {
+		t.Errorf("expected the client to be closed while the service was dialed")
+	}
+
+	// the connection dialed once the proxy is stopped is closed right away
+	close(d.release)
+	s := <-d.dialed
+	// the deadline only matters if the connection was left open, in which case setting it cannot fail
+	_ = s.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := s.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("expected the upstream connection to be closed, got %v", err)
+	}
+}
+
+func TestListenPortOffset(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	tun := NewTunnel(context.Background(), "127.0.0.1", port-80, localDialer{}, fake.NewSimpleClientset().CoreV1())
+	p, err := tun.listen(core.Service{}, core.ServicePort{Port: 80, Protocol: core.ProtocolTCP})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer p.stop()
+	if got := listenedPort(p); got != port {
+		t.Errorf("listened on port %d, want %d", got, port)
+	}
+}
+
+func TestSync(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	// find a free port for the service, then release it for the proxy
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	tun := NewTunnel(context.Background(), "0.0.0.0", 0, localDialer{}, fake.NewSimpleClientset().CoreV1())
+	patched := map[string]string{}
+	tun.patchIP = func(svc core.Service, ip string) error {
+		patched[key(svc)] = ip
+		return nil
+	}
+
+	svc := core.Service{
+		ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: core.ServiceSpec{
+			Type:      core.ServiceTypeLoadBalancer,
+			ClusterIP: "10.96.0.10",
+			Ports:     []core.ServicePort{{Port: int32(port), Protocol: core.ProtocolTCP}},
+		},
+	}
+	tun.sync(svc)
+	if patched["default/web"] != "127.0.0.1" {
+		t.Errorf("ingress = %q, want 127.0.0.1", patched["default/web"])
+	}
+	e, ok := tun.services["default/web"]
+	if !ok || len(e.proxies) != 1 {
+		t.Fatalf("expected one proxy for default/web, got %+v", e)
+	}
+
+	// an unchanged service with its ingress set is left alone
+	delete(patched, "default/web")
+	svc.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: "127.0.0.1"}}
+	tun.sync(svc)
+	if _, ok := patched["default/web"]; ok {
+		t.Errorf("service was patched again")
+	}
+	if tun.services["default/web"] != e {
+		t.Errorf("proxies were recreated for an unchanged service")
+	}
+
+	// services that are no longer LoadBalancers are no longer exposed
+	svc.Spec.Type = core.ServiceTypeClusterIP
+	tun.sync(svc)
+	if _, ok := tun.services["default/web"]; ok {
+		t.Errorf("service is still exposed")
+	}
+	if _, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port))); err == nil {
+		t.Errorf("proxy is still listening")
+	}
+}
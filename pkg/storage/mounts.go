@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// mount is an entry of /proc/self/mountinfo
+type mount struct {
+	point  string
+	fstype string
+	source string
+}
+
+// mountOf returns the mount that contains a path, given the contents of /proc/self/mountinfo
+func mountOf(mountinfo io.Reader, path string) (mount, error) {
+	path = filepath.Clean(path)
+	var found mount
+	scanner := bufio.NewScanner(mountinfo)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, f := range fields {
+			if f == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 5 || len(fields) < sep+3 {
+			continue
+		}
+		m := mount{point: fields[4], fstype: fields[sep+1], source: fields[sep+2]}
+		if !within(path, m.point) {
+			continue
+		}
+		// the longest mount point wins, and later mounts shadow earlier ones
+		if len(m.point) >= len(found.point) {
+			found = m
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return found, errors.Wrap(err, "reading mountinfo")
+	}
+	if found.point == "" {
+		return found, errors.Errorf("no mount found for %s", path)
+	}
+	return found, nil
+}
+
+// within returns whether path is dir or below it
+func within(path string, dir string) bool {
+	if dir == "/" || path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, dir+"/")
+}
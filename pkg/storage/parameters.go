@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// capacityReport reports the disk usage of volumes, without limiting it
+	capacityReport = "report"
+	// capacityEnforce limits volumes to their requested size with XFS project quotas
+	capacityEnforce = "enforce"
+)
+
+// volumeRoots are the directories of the node which are mounted into the provisioner, and may hold volumes
+var volumeRoots = []string{"/tmp/hostpath-provisioner", "/data"}
+
+// parameters are the StorageClass parameters understood by the provisioner
+type parameters struct {
+	// path is the directory on the node to create volumes in
+	path string
+	// capacity is how the requested size of volumes is handled
+	capacity string
+}
+
+// parseParameters parses the parameters of a StorageClass, rejecting unknown ones
+func parseParameters(params map[string]string) (parameters, error) {
+	p := parameters{capacity: capacityReport}
+	for k, v := range params {
+		switch k {
+		case "path":
+			if !path.IsAbs(v) {
+				return p, fmt.Errorf("path %q must be absolute", v)
+			}
+			p.path = path.Clean(v)
+			if !inVolumeRoot(p.path) {
+				return p, fmt.Errorf("path %q must be within one of %s", v, strings.Join(volumeRoots, ", "))
+			}
+		case "capacity":
+			if v != capacityReport && v != capacityEnforce {
+				return p, fmt.Errorf("capacity %q is invalid, valid values are %q and %q", v, capacityReport, capacityEnforce)
+			}
+			p.capacity = v
+		default:
+			return p, fmt.Errorf("unknown StorageClass parameter %q", k)
+		}
+	}
+	return p, nil
+}
+
+// inVolumeRoot returns whether a directory of the node is within one of the volume roots
+func inVolumeRoot(dir string) bool {
+	for _, r := range volumeRoots {
+		if within(dir, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// +build linux
+
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"os"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ioctls reading and writing the extended attributes of a file, which hold its project
+	fsIOCGetXattr = 0x801c581f
+	fsIOCSetXattr = 0x401c5820
+	// fsXflagProjInherit makes new files inherit the project of their directory
+	fsXflagProjInherit = 0x200
+
+	// qXSetQLim is Q_XSETQLIM for project quotas, as built by the QCMD macro
+	qXSetQLim = (('X'<<8)+4)<<8 | prjQuota
+	prjQuota  = 2
+
+	fsDquotVersion = 1
+	fsProjQuota    = 2
+	fsDqBSoft      = 1 << 0
+	fsDqBHard      = 1 << 1
+)
+
+// fsxattr mirrors struct fsxattr from linux/fs.h
+type fsxattr struct {
+	xflags     uint32
+	extsize    uint32
+	nextents   uint32
+	projid     uint32
+	cowextsize uint32
+	pad        [8]byte
+}
+
+// fsDiskQuota mirrors struct fs_disk_quota from linux/dqblk_xfs.h
+type fsDiskQuota struct {
+	version      int8
+	flags        int8
+	fieldmask    uint16
+	id           uint32
+	blkHardlimit uint64
+	blkSoftlimit uint64
+	inoHardlimit uint64
+	inoSoftlimit uint64
+	bcount       uint64
+	icount       uint64
+	itimer       int32
+	btimer       int32
+	iwarns       uint16
+	bwarns       uint16
+	padding2     int32
+	rtbHardlimit uint64
+	rtbSoftlimit uint64
+	rtbcount     uint64
+	rtbtimer     int32
+	rtbwarns     uint16
+	padding3     int16
+	padding4     [8]byte
+}
+
+// setQuota limits the size of a directory by assigning it an XFS project with a block limit
+func setQuota(dir string, id uint32, bytes int64) error {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return errors.Wrap(err, "opening mountinfo")
+	}
+	defer f.Close()
+	m, err := mountOf(f, dir)
+	if err != nil {
+		return err
+	}
+	if m.fstype != "xfs" {
+		return errors.Errorf("capacity can only be enforced on xfs, but %s is %s", m.point, m.fstype)
+	}
+
+	if err := setProject(dir, id); err != nil {
+		return errors.Wrap(err, "setting project")
+	}
+
+	// limits are counted in 512-byte basic blocks
+	blocks := uint64(bytes+511) / 512
+	q := fsDiskQuota{
+		version:      fsDquotVersion,
+		flags:        fsProjQuota,
+		fieldmask:    fsDqBSoft | fsDqBHard,
+		id:           id,
+		blkHardlimit: blocks,
+		blkSoftlimit: blocks,
+	}
+	dev, err := unix.BytePtrFromString(m.source)
+	if err != nil {
+		return err
+	}
+	if _, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, qXSetQLim, uintptr(unsafe.Pointer(dev)), uintptr(id), uintptr(unsafe.Pointer(&q)), 0, 0); errno != 0 {
+		return errors.Wrapf(errno, "setting quota on %s", m.source)
+	}
+	return nil
+}
+
+// setProject assigns a directory, and whatever is created below it, to an XFS project
+func setProject(dir string, id uint32) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	var attr fsxattr
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, d.Fd(), fsIOCGetXattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return errno
+	}
+	attr.projid = id
+	attr.xflags |= fsXflagProjInherit
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, d.Fd(), fsIOCSetXattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -20,71 +20,132 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/sig-storage-lib-external-provisioner/controller"
 )
 
-const provisionerName = "k8s.io/minikube-hostpath"
+const (
+	provisionerName = "k8s.io/minikube-hostpath"
+
+	// identityAnnotation records the node whose provisioner owns a PV
+	identityAnnotation = "hostPathProvisionerIdentity"
+	// capacityAnnotation records how the capacity of a PV is handled
+	capacityAnnotation = "minikube.k8s.io/capacity"
+	// usageAnnotation reports the disk space used by a PV
+	usageAnnotation = "minikube.k8s.io/volume-usage"
+
+	// hostnameLabel is the node label that the node affinity of PVs is keyed on
+	hostnameLabel = "kubernetes.io/hostname"
+	// masterLabel marks control plane nodes, which get the volumes of claims bound immediately
+	masterLabel = "node-role.kubernetes.io/master"
+
+	// resyncPeriod is how often volumes are checked for expansion and usage
+	resyncPeriod = time.Minute
+)
 
 type hostPathProvisioner struct {
-	// The directory to create PV-backing directories in
+	// The directory to create PV-backing directories in, unless the StorageClass sets one
 	pvDir string
 
-	// Identity of this hostPathProvisioner, generated. Used to identify "this"
-	// provisioner's PVs.
-	identity types.UID
+	// The node this provisioner runs on. It only provisions volumes placed on this node, and
+	// doubles as the identity used to recognize "this" provisioner's PVs.
+	nodeName string
+
+	// Where the root filesystem of the node is mounted, as PV paths are relative to the node
+	hostRoot string
+
+	client kubernetes.Interface
 }
 
-// NewHostPathProvisioner creates a new Provisioner using host paths
-func NewHostPathProvisioner(pvDir string) controller.Provisioner {
+// NewHostPathProvisioner creates a new Provisioner using host paths on the given node
+func NewHostPathProvisioner(client kubernetes.Interface, pvDir string, nodeName string, hostRoot string) controller.Provisioner {
 	return &hostPathProvisioner{
 		pvDir:    pvDir,
-		identity: uuid.NewUUID(),
+		nodeName: nodeName,
+		hostRoot: hostRoot,
+		client:   client,
 	}
 }
 
 var _ controller.Provisioner = &hostPathProvisioner{}
 
-// Provision creates a storage asset and returns a PV object representing it.
+// Provision creates a storage asset on the node the claim was scheduled to, and returns a PV object representing it.
 func (p *hostPathProvisioner) Provision(options controller.ProvisionOptions) (*core.PersistentVolume, error) {
+	node, err := p.nodeFor(options.SelectedNode)
+	if err != nil {
+		return nil, errors.Wrap(err, "selecting node")
+	}
+	if node.Name != p.nodeName {
+		return nil, &controller.IgnoredError{Reason: fmt.Sprintf("volume belongs on node %s", node.Name)}
+	}
+
+	params, err := parseParameters(options.StorageClass.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
 	glog.Infof("Provisioning volume %v", options)
-	path := path.Join(p.pvDir, options.PVC.Name)
-	if err := os.MkdirAll(path, 0777); err != nil {
+	base := p.pvDir
+	if params.path != "" {
+		base = params.path
+	}
+	// Volume directories are named after the PV, so that a new claim never picks up the data of a retained volume
+	path := path.Join(base, options.PVName)
+	dir := p.hostPath(path)
+	if err := os.MkdirAll(dir, 0777); err != nil {
 		return nil, err
 	}
 
 	// Explicitly chmod created dir, so we know mode is set to 0777 regardless of umask
-	if err := os.Chmod(path, 0777); err != nil {
+	if err := os.Chmod(dir, 0777); err != nil {
 		return nil, err
 	}
 
+	size := options.PVC.Spec.Resources.Requests[core.ResourceStorage]
+	if params.capacity == capacityEnforce {
+		if err := setQuota(dir, projectID(options.PVName), size.Value()); err != nil {
+			if rerr := os.RemoveAll(dir); rerr != nil {
+				glog.Warningf("Unable to remove %s: %v", dir, rerr)
+			}
+			return nil, errors.Wrap(err, "enforcing capacity")
+		}
+	}
+
+	reclaim := core.PersistentVolumeReclaimDelete
+	if options.StorageClass.ReclaimPolicy != nil {
+		reclaim = *options.StorageClass.ReclaimPolicy
+	}
+
 	pv := &core.PersistentVolume{
 		ObjectMeta: meta.ObjectMeta{
 			Name: options.PVName,
 			Annotations: map[string]string{
-				"hostPathProvisionerIdentity": string(p.identity),
+				identityAnnotation: p.nodeName,
+				capacityAnnotation: params.capacity,
 			},
 		},
 		Spec: core.PersistentVolumeSpec{
-			PersistentVolumeReclaimPolicy: *options.StorageClass.ReclaimPolicy,
+			PersistentVolumeReclaimPolicy: reclaim,
 			AccessModes:                   options.PVC.Spec.AccessModes,
 			Capacity: core.ResourceList{
-				core.ResourceStorage: options.PVC.Spec.Resources.Requests[core.ResourceStorage],
+				core.ResourceStorage: size,
 			},
 			PersistentVolumeSource: core.PersistentVolumeSource{
 				HostPath: &core.HostPathVolumeSource{
 					Path: path,
 				},
 			},
+			NodeAffinity: nodeAffinity(node),
 		},
 	}
 
@@ -95,24 +156,108 @@ func (p *hostPathProvisioner) Provision(options controller.ProvisionOptions) (*c
 // by the given PV.
 func (p *hostPathProvisioner) Delete(volume *core.PersistentVolume) error {
 	glog.Infof("Deleting volume %v", volume)
-	ann, ok := volume.Annotations["hostPathProvisionerIdentity"]
-	if !ok {
+	if _, ok := volume.Annotations[identityAnnotation]; !ok {
 		return errors.New("identity annotation not found on PV")
 	}
-	if ann != string(p.identity) {
+	owned, err := p.owns(volume)
+	if err != nil {
+		return errors.Wrap(err, "checking owner")
+	}
+	if !owned {
 		return &controller.IgnoredError{Reason: "identity annotation on PV does not match ours"}
 	}
 
-	if err := os.RemoveAll(volume.Spec.PersistentVolumeSource.HostPath.Path); err != nil {
+	if err := os.RemoveAll(p.hostPath(volume.Spec.PersistentVolumeSource.HostPath.Path)); err != nil {
 		return errors.Wrap(err, "removing hostpath PV")
 	}
 
 	return nil
 }
 
-// StartStorageProvisioner will start storage provisioner server
-func StartStorageProvisioner(pvDir string) error {
+// hostPath returns where a path of the node can be found by the provisioner
+func (p *hostPathProvisioner) hostPath(path string) string {
+	return filepath.Join(p.hostRoot, path)
+}
+
+// owns returns whether a PV was provisioned on this node. PVs provisioned before every node ran its own
+// provisioner carry a random identity and no node affinity. They are on the node which gets the volumes
+// of claims bound immediately, where the single provisioner used to run.
+func (p *hostPathProvisioner) owns(pv *core.PersistentVolume) (bool, error) {
+	ann, ok := pv.Annotations[identityAnnotation]
+	if !ok || pv.Spec.HostPath == nil {
+		return false, nil
+	}
+	if ann == p.nodeName {
+		return true, nil
+	}
+	if pv.Spec.NodeAffinity != nil {
+		return false, nil
+	}
+	node, err := p.nodeFor(nil)
+	if err != nil {
+		return false, err
+	}
+	return node.Name == p.nodeName, nil
+}
+
+// nodeFor returns the node a volume should be placed on: the one its pod was scheduled to, or
+// with immediate binding, the first control plane.
+func (p *hostPathProvisioner) nodeFor(selected *core.Node) (*core.Node, error) {
+	if selected != nil {
+		return selected, nil
+	}
+
+	nodes, err := p.client.CoreV1().Nodes().List(meta.ListOptions{LabelSelector: masterLabel})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing nodes")
+	}
+	if len(nodes.Items) == 0 {
+		nodes, err = p.client.CoreV1().Nodes().List(meta.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "listing nodes")
+		}
+	}
+	return firstNode(nodes.Items)
+}
+
+// firstNode returns the node with the lowest name, which is stable across provisioners
+func firstNode(nodes []core.Node) (*core.Node, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("no nodes found")
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return &nodes[0], nil
+}
+
+// nodeAffinity pins a PV to the node its directory was created on
+func nodeAffinity(node *core.Node) *core.VolumeNodeAffinity {
+	hostname := node.Labels[hostnameLabel]
+	if hostname == "" {
+		hostname = node.Name
+	}
+	return &core.VolumeNodeAffinity{
+		Required: &core.NodeSelector{
+			NodeSelectorTerms: []core.NodeSelectorTerm{
+				{
+					MatchExpressions: []core.NodeSelectorRequirement{
+						{
+							Key:      hostnameLabel,
+							Operator: core.NodeSelectorOpIn,
+							Values:   []string{hostname},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// StartStorageProvisioner will start storage provisioner server for the volumes of a node
+func StartStorageProvisioner(pvDir string, nodeName string, hostRoot string) error {
 	glog.Infof("Initializing the minikube storage provisioner...")
+	if nodeName == "" {
+		return errors.New("the name of the node is required")
+	}
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		return err
@@ -131,13 +276,20 @@ func StartStorageProvisioner(pvDir string) error {
 
 	// Create the provisioner: it implements the Provisioner interface expected by
 	// the controller
-	hostPathProvisioner := NewHostPathProvisioner(pvDir)
+	p := &hostPathProvisioner{
+		pvDir:    pvDir,
+		nodeName: nodeName,
+		hostRoot: hostRoot,
+		client:   clientset,
+	}
 
 	// Start the provision controller which will dynamically provision hostPath
-	// PVs
-	pc := controller.NewProvisionController(clientset, provisionerName, hostPathProvisioner, serverVersion.GitVersion)
+	// PVs. Every node runs its own provisioner, so there is no leader to elect.
+	pc := controller.NewProvisionController(clientset, provisionerName, p, serverVersion.GitVersion, controller.LeaderElection(false))
+
+	go wait.Until(p.resync, resyncPeriod, wait.NeverStop)
 
-	glog.Info("Storage provisioner initialized, now starting service!")
+	glog.Infof("Storage provisioner initialized for node %s, now starting service!", nodeName)
 	pc.Run(wait.NeverStop)
 	return nil
 }
@@ -0,0 +1,262 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/sig-storage-lib-external-provisioner/controller"
+)
+
+func TestParseParameters(t *testing.T) {
+	tests := []struct {
+		params  map[string]string
+		want    parameters
+		wantErr bool
+	}{
+		{params: nil, want: parameters{capacity: capacityReport}},
+		{params: map[string]string{"path": "/data/pv/", "capacity": "enforce"}, want: parameters{path: "/data/pv", capacity: capacityEnforce}},
+		{params: map[string]string{"path": "data"}, wantErr: true},
+		{params: map[string]string{"path": "/etc"}, wantErr: true},
+		{params: map[string]string{"path": "/database"}, wantErr: true},
+		{params: map[string]string{"capacity": "limit"}, wantErr: true},
+		{params: map[string]string{"type": "ssd"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := parseParameters(tc.params)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseParameters(%v) error = %v, wantErr %v", tc.params, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && got != tc.want {
+			t.Errorf("parseParameters(%v) = %+v, want %+v", tc.params, got, tc.want)
+		}
+	}
+}
+
+func TestMountOf(t *testing.T) {
+	mountinfo := `21 1 0:19 / / rw,relatime - rootfs rootfs rw
+36 21 8:1 / /mnt/sda1 rw,relatime shared:1 - xfs /dev/sda1 rw,prjquota
+37 21 8:1 /hostpath-provisioner /tmp/hostpath-provisioner rw,relatime shared:1 - xfs /dev/sda1 rw,prjquota
+38 21 0:20 / /tmp rw - tmpfs tmpfs rw
+`
+	tests := []struct {
+		path string
+		want mount
+	}{
+		{path: "/tmp/hostpath-provisioner/default/claim", want: mount{point: "/tmp/hostpath-provisioner", fstype: "xfs", source: "/dev/sda1"}},
+		{path: "/tmp/hostpath_pv", want: mount{point: "/tmp", fstype: "tmpfs", source: "tmpfs"}},
+		{path: "/mnt/sda1x", want: mount{point: "/", fstype: "rootfs", source: "rootfs"}},
+	}
+	for _, tc := range tests {
+		got, err := mountOf(strings.NewReader(mountinfo), tc.path)
+		if err != nil {
+			t.Errorf("mountOf(%s): %v", tc.path, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("mountOf(%s) = %+v, want %+v", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestProvision(t *testing.T) {
+	root, err := ioutil.TempDir("", "hostroot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	node := &core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube-m02", Labels: map[string]string{hostnameLabel: "minikube-m02"}}}
+	retain := core.PersistentVolumeReclaimRetain
+	options := controller.ProvisionOptions{
+		StorageClass: &storage.StorageClass{
+			Parameters:    map[string]string{"path": "/data"},
+			ReclaimPolicy: &retain,
+		},
+		PVName: "pvc-1234",
+		PVC: &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{Name: "claim", Namespace: "default"},
+			Spec: core.PersistentVolumeClaimSpec{
+				AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		},
+		SelectedNode: node,
+	}
+
+	other := NewHostPathProvisioner(fake.NewSimpleClientset(), "/tmp/hostpath-provisioner", "minikube", root)
+	if _, err := other.Provision(options); err == nil {
+		t.Errorf("expected the provisioner of another node to ignore the claim")
+	} else if _, ok := err.(*controller.IgnoredError); !ok {
+		t.Errorf("expected IgnoredError, got %v", err)
+	}
+
+	p := NewHostPathProvisioner(fake.NewSimpleClientset(), "/tmp/hostpath-provisioner", "minikube-m02", root)
+	pv, err := p.Provision(options)
+	if err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	if pv.Spec.HostPath.Path != "/data/pvc-1234" {
+		t.Errorf("path = %s, want /data/pvc-1234", pv.Spec.HostPath.Path)
+	}
+	if _, err := os.Stat(filepath.Join(root, "data/pvc-1234")); err != nil {
+		t.Errorf("volume directory was not created: %v", err)
+	}
+	if pv.Spec.PersistentVolumeReclaimPolicy != retain {
+		t.Errorf("reclaim policy = %s, want %s", pv.Spec.PersistentVolumeReclaimPolicy, retain)
+	}
+	values := pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values
+	if len(values) != 1 || values[0] != "minikube-m02" {
+		t.Errorf("node affinity = %v, want [minikube-m02]", values)
+	}
+
+	if err := other.Delete(pv); err == nil {
+		t.Errorf("expected the provisioner of another node to ignore the volume")
+	}
+	if err := p.Delete(pv); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "data/pvc-1234")); !os.IsNotExist(err) {
+		t.Errorf("volume directory still exists: %v", err)
+	}
+}
+
+func TestProvisionImmediate(t *testing.T) {
+	root, err := ioutil.TempDir("", "hostroot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	client := fake.NewSimpleClientset(
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube-m02", Labels: map[string]string{masterLabel: ""}}},
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube", Labels: map[string]string{masterLabel: ""}}},
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube-m03"}},
+	)
+	options := controller.ProvisionOptions{
+		StorageClass: &storage.StorageClass{},
+		PVName:       "pvc-1234",
+		PVC:          &core.PersistentVolumeClaim{ObjectMeta: meta.ObjectMeta{Name: "claim", Namespace: "default"}},
+	}
+
+	p := NewHostPathProvisioner(client, "/tmp/hostpath-provisioner", "minikube", root)
+	pv, err := p.Provision(options)
+	if err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	if pv.Spec.PersistentVolumeReclaimPolicy != core.PersistentVolumeReclaimDelete {
+		t.Errorf("reclaim policy = %s, want Delete", pv.Spec.PersistentVolumeReclaimPolicy)
+	}
+	values := pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values
+	if len(values) != 1 || values[0] != "minikube" {
+		t.Errorf("node affinity = %v, want [minikube]", values)
+	}
+}
+
+func TestDeleteLegacy(t *testing.T) {
+	root, err := ioutil.TempDir("", "hostroot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	client := fake.NewSimpleClientset(
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube", Labels: map[string]string{masterLabel: ""}}},
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube-m02"}},
+	)
+	// provisioned by the single provisioner of older releases, with a random identity and no node affinity
+	pv := &core.PersistentVolume{
+		ObjectMeta: meta.ObjectMeta{Name: "pvc-1234", Annotations: map[string]string{identityAnnotation: "6a1f3bd2-5c8e-4b3b-9d0e-2f1f1c7e9a10"}},
+		Spec: core.PersistentVolumeSpec{
+			PersistentVolumeSource: core.PersistentVolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/tmp/hostpath-provisioner/claim"}},
+		},
+	}
+	dir := filepath.Join(root, "tmp/hostpath-provisioner/claim")
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	other := NewHostPathProvisioner(client, "/tmp/hostpath-provisioner", "minikube-m02", root)
+	if err := other.Delete(pv); err == nil {
+		t.Errorf("expected the provisioner of a worker to ignore the volume")
+	}
+	p := NewHostPathProvisioner(client, "/tmp/hostpath-provisioner", "minikube", root)
+	if err := p.Delete(pv); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("volume directory still exists: %v", err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	class := "standard"
+	allow := true
+	pv := &core.PersistentVolume{
+		ObjectMeta: meta.ObjectMeta{Name: "pvc-1234", Annotations: map[string]string{identityAnnotation: "minikube", capacityAnnotation: capacityReport}},
+		Spec: core.PersistentVolumeSpec{
+			Capacity:               core.ResourceList{core.ResourceStorage: resource.MustParse("1Gi")},
+			PersistentVolumeSource: core.PersistentVolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/tmp/hostpath-provisioner/default/claim"}},
+			ClaimRef:               &core.ObjectReference{Namespace: "default", Name: "claim"},
+		},
+		Status: core.PersistentVolumeStatus{Phase: core.VolumeBound},
+	}
+	pvc := &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{Name: "claim", Namespace: "default"},
+		Spec: core.PersistentVolumeClaimSpec{
+			StorageClassName: &class,
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("2Gi")},
+			},
+		},
+	}
+	sc := &storage.StorageClass{ObjectMeta: meta.ObjectMeta{Name: class}, AllowVolumeExpansion: &allow}
+	client := fake.NewSimpleClientset(pv, pvc, sc)
+
+	p := &hostPathProvisioner{nodeName: "minikube", hostRoot: "/", client: client}
+	if err := p.expand(pv.DeepCopy()); err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+
+	want := resource.MustParse("2Gi")
+	got, err := client.CoreV1().PersistentVolumes().Get(pv.Name, meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("get pv: %v", err)
+	}
+	if c := got.Spec.Capacity[core.ResourceStorage]; c.Cmp(want) != 0 {
+		t.Errorf("pv capacity = %s, want %s", c.String(), want.String())
+	}
+	claim, err := client.CoreV1().PersistentVolumeClaims("default").Get(pvc.Name, meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("get pvc: %v", err)
+	}
+	if c := claim.Status.Capacity[core.ResourceStorage]; c.Cmp(want) != 0 {
+		t.Errorf("pvc capacity = %s, want %s", c.String(), want.String())
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"hash/fnv"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resync expands the volumes of this node whose claims have grown, and reports their usage
+func (p *hostPathProvisioner) resync() {
+	pvs, err := p.client.CoreV1().PersistentVolumes().List(meta.ListOptions{})
+	if err != nil {
+		glog.Warningf("Unable to list volumes: %v", err)
+		return
+	}
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		owned, err := p.owns(pv)
+		if err != nil {
+			glog.Warningf("Unable to check the owner of %s: %v", pv.Name, err)
+			continue
+		}
+		if !owned {
+			continue
+		}
+		if err := p.expand(pv); err != nil {
+			glog.Warningf("Unable to expand %s: %v", pv.Name, err)
+		}
+		if err := p.reportUsage(pv); err != nil {
+			glog.Warningf("Unable to report usage of %s: %v", pv.Name, err)
+		}
+	}
+}
+
+// expand grows a bound volume to the size requested by its claim, if its StorageClass allows it.
+// hostPath volumes need no filesystem resize, so both the PV and the claim are updated directly.
+func (p *hostPathProvisioner) expand(pv *core.PersistentVolume) error {
+	if pv.Status.Phase != core.VolumeBound || pv.Spec.ClaimRef == nil {
+		return nil
+	}
+	ref := pv.Spec.ClaimRef
+	pvc, err := p.client.CoreV1().PersistentVolumeClaims(ref.Namespace).Get(ref.Name, meta.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "getting claim")
+	}
+
+	want := pvc.Spec.Resources.Requests[core.ResourceStorage]
+	have := pv.Spec.Capacity[core.ResourceStorage]
+	if want.Cmp(have) <= 0 {
+		return nil
+	}
+
+	if pvc.Spec.StorageClassName == nil {
+		return nil
+	}
+	sc, err := p.client.StorageV1().StorageClasses().Get(*pvc.Spec.StorageClassName, meta.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "getting storage class")
+	}
+	if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
+		return nil
+	}
+
+	glog.Infof("Expanding %s from %s to %s", pv.Name, have.String(), want.String())
+	if pv.Annotations[capacityAnnotation] == capacityEnforce {
+		if err := setQuota(p.hostPath(pv.Spec.HostPath.Path), projectID(pv.Name), want.Value()); err != nil {
+			return errors.Wrap(err, "enforcing capacity")
+		}
+	}
+
+	pv.Spec.Capacity[core.ResourceStorage] = want
+	if _, err := p.client.CoreV1().PersistentVolumes().Update(pv); err != nil {
+		return errors.Wrap(err, "updating volume")
+	}
+
+	if pvc.Status.Capacity == nil {
+		pvc.Status.Capacity = core.ResourceList{}
+	}
+	pvc.Status.Capacity[core.ResourceStorage] = want
+	if _, err := p.client.CoreV1().PersistentVolumeClaims(pvc.Namespace).UpdateStatus(pvc); err != nil {
+		return errors.Wrap(err, "updating claim")
+	}
+	return nil
+}
+
+// reportUsage records the disk space used by a volume in an annotation, warning when it exceeds the capacity
+func (p *hostPathProvisioner) reportUsage(pv *core.PersistentVolume) error {
+	used, err := diskUsage(p.hostPath(pv.Spec.HostPath.Path))
+	if err != nil {
+		return err
+	}
+
+	usage := resource.NewQuantity(used, resource.BinarySI).String()
+	if pv.Annotations[usageAnnotation] == usage {
+		return nil
+	}
+	capacity := pv.Spec.Capacity[core.ResourceStorage]
+	if used > capacity.Value() {
+		glog.Warningf("%s uses %s, which exceeds its capacity of %s", pv.Name, usage, capacity.String())
+	}
+
+	latest, err := p.client.CoreV1().PersistentVolumes().Get(pv.Name, meta.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "getting volume")
+	}
+	if latest.Annotations == nil {
+		latest.Annotations = map[string]string{}
+	}
+	latest.Annotations[usageAnnotation] = usage
+	_, err = p.client.CoreV1().PersistentVolumes().Update(latest)
+	return err
+}
+
+// diskUsage returns the number of bytes used by the files below a directory
+func diskUsage(dir string) (int64, error) {
+	var total int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// projectID returns the XFS project used to enforce the capacity of a volume
+func projectID(pvName string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(pvName))
+	// project 0 is the default project of every file
+	return h.Sum32()%(1<<31-1) + 1
+}
@@ -69,6 +69,12 @@ func validatePersistentVolumeClaim(ctx context.Context, t *testing.T, profile st
 		t.Fatalf("kubectl apply pvc.yaml failed: args %q: %v", rr.Command(), err)
 	}
 
+	// The default StorageClass waits for a consumer, so the claim is only bound once a pod uses it
+	rr, err = Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "apply", "-f", filepath.Join(*testdataDir, "pvc-pod.yaml")))
+	if err != nil {
+		t.Fatalf("kubectl apply pvc-pod.yaml failed: args %q: %v", rr.Command(), err)
+	}
+
 	checkStoragePhase := func() error {
 		rr, err := Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "get", "pvc", "testpvc", "-o=json"))
 		if err != nil {
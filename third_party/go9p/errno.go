@@ -0,0 +1,59 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// The error numbers of 9P2000.u and 9P2000.L are those of Linux,
+// so the ones of other hosts have to be translated.
+var linuxErrnos = map[syscall.Errno]uint32{
+	syscall.EPERM:        EPERM,
+	syscall.ENOENT:       ENOENT,
+	syscall.EIO:          EIO,
+	syscall.EBADF:        EBADF,
+	syscall.EAGAIN:       EAGAIN,
+	syscall.EACCES:       EACCES,
+	syscall.EBUSY:        EBUSY,
+	syscall.EEXIST:       EEXIST,
+	syscall.EXDEV:        EXDEV,
+	syscall.ENOTDIR:      ENOTDIR,
+	syscall.EISDIR:       EISDIR,
+	syscall.EINVAL:       EINVAL,
+	syscall.EFBIG:        EFBIG,
+	syscall.ENOSPC:       ENOSPC,
+	syscall.EROFS:        EROFS,
+	syscall.EMLINK:       EMLINK,
+	syscall.ENAMETOOLONG: ENAMETOOLONG,
+	syscall.ENOSYS:       ENOSYS,
+	syscall.ENOTEMPTY:    ENOTEMPTY,
+	syscall.ELOOP:        ELOOP,
+}
+
+// Returns the Linux error number for an error number of the host.
+func linuxErrno(errno syscall.Errno) uint32 {
+	if runtime.GOOS == "linux" {
+		return uint32(errno)
+	}
+
+	if ecode, ok := linuxErrnos[errno]; ok {
+		return ecode
+	}
+
+	// Windows reports its own error codes, which os can classify
+	switch {
+	case os.IsNotExist(errno):
+		return ENOENT
+	case os.IsExist(errno):
+		return EEXIST
+	case os.IsPermission(errno):
+		return EACCES
+	}
+
+	return EIO
+}
@@ -85,6 +85,27 @@ func (d *Dir) String() string {
 	return ret
 }
 
+var msgNames = map[uint8]string{
+	Tversion: "Tversion", Tauth: "Tauth", Tattach: "Tattach", Tflush: "Tflush",
+	Twalk: "Twalk", Topen: "Topen", Tcreate: "Tcreate", Tread: "Tread",
+	Twrite: "Twrite", Tclunk: "Tclunk", Tremove: "Tremove", Tstat: "Tstat",
+	Twstat: "Twstat", Tstatfs: "Tstatfs", Tlopen: "Tlopen", Tlcreate: "Tlcreate",
+	Tsymlink: "Tsymlink", Tmknod: "Tmknod", Trename: "Trename", Treadlink: "Treadlink",
+	Tgetattr: "Tgetattr", Tsetattr: "Tsetattr", Txattrwalk: "Txattrwalk",
+	Txattrcreate: "Txattrcreate", Treaddir: "Treaddir", Tfsync: "Tfsync",
+	Tlock: "Tlock", Tgetlock: "Tgetlock", Tlink: "Tlink", Tmkdir: "Tmkdir",
+	Trenameat: "Trenameat", Tunlinkat: "Tunlinkat",
+}
+
+// Returns the name of a T-message type
+func msgName(typ uint8) string {
+	if name, ok := msgNames[typ]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("T%d", typ)
+}
+
 func (fc *Fcall) String() string {
 	ret := ""
 
@@ -155,6 +176,81 @@ func (fc *Fcall) String() string {
 		ret = fmt.Sprintf("Rremove tag %d", fc.Tag)
 	case Rwstat:
 		ret = fmt.Sprintf("Rwstat tag %d", fc.Tag)
+	case Rlerror:
+		ret = fmt.Sprintf("Rlerror tag %d ecode %d", fc.Tag, fc.Errornum)
+	case Tstatfs:
+		ret = fmt.Sprintf("Tstatfs tag %d fid %d", fc.Tag, fc.Fid)
+	case Rstatfs:
+		ret = fmt.Sprintf("Rstatfs tag %d st %+v", fc.Tag, fc.Lstatfs)
+	case Tlopen:
+		ret = fmt.Sprintf("Tlopen tag %d fid %d flags %o", fc.Tag, fc.Fid, fc.Lflags)
+	case Rlopen:
+		ret = fmt.Sprintf("Rlopen tag %d qid %v iounit %d", fc.Tag, &fc.Qid, fc.Iounit)
+	case Tlcreate:
+		ret = fmt.Sprintf("Tlcreate tag %d fid %d name '%s' flags %o mode %o gid %d",
+			fc.Tag, fc.Fid, fc.Name, fc.Lflags, fc.Lmode, fc.Lgid)
+	case Rlcreate:
+		ret = fmt.Sprintf("Rlcreate tag %d qid %v iounit %d", fc.Tag, &fc.Qid, fc.Iounit)
+	case Tsymlink:
+		ret = fmt.Sprintf("Tsymlink tag %d fid %d name '%s' target '%s' gid %d",
+			fc.Tag, fc.Fid, fc.Name, fc.Target, fc.Lgid)
+	case Rsymlink:
+		ret = fmt.Sprintf("Rsymlink tag %d qid %v", fc.Tag, &fc.Qid)
+	case Tmknod:
+		ret = fmt.Sprintf("Tmknod tag %d fid %d name '%s' mode %o gid %d", fc.Tag, fc.Fid, fc.Name, fc.Lmode, fc.Lgid)
+	case Trename:
+		ret = fmt.Sprintf("Trename tag %d fid %d dfid %d name '%s'", fc.Tag, fc.Fid, fc.Dfid, fc.Name)
+	case Rrename:
+		ret = fmt.Sprintf("Rrename tag %d", fc.Tag)
+	case Treadlink:
+		ret = fmt.Sprintf("Treadlink tag %d fid %d", fc.Tag, fc.Fid)
+	case Rreadlink:
+		ret = fmt.Sprintf("Rreadlink tag %d target '%s'", fc.Tag, fc.Target)
+	case Tgetattr:
+		ret = fmt.Sprintf("Tgetattr tag %d fid %d mask %x", fc.Tag, fc.Fid, fc.Mask)
+	case Rgetattr:
+		ret = fmt.Sprintf("Rgetattr tag %d attr %+v", fc.Tag, fc.Lattr)
+	case Tsetattr:
+		ret = fmt.Sprintf("Tsetattr tag %d fid %d attr %+v", fc.Tag, fc.Fid, fc.Lsetattr)
+	case Rsetattr:
+		ret = fmt.Sprintf("Rsetattr tag %d", fc.Tag)
+	case Txattrwalk:
+		ret = fmt.Sprintf("Txattrwalk tag %d fid %d newfid %d name '%s'", fc.Tag, fc.Fid, fc.Newfid, fc.Name)
+	case Txattrcreate:
+		ret = fmt.Sprintf("Txattrcreate tag %d fid %d name '%s'", fc.Tag, fc.Fid, fc.Name)
+	case Treaddir:
+		ret = fmt.Sprintf("Treaddir tag %d fid %d offset %d count %d", fc.Tag, fc.Fid, fc.Offset, fc.Count)
+	case Rreaddir:
+		ret = fmt.Sprintf("Rreaddir tag %d count %d", fc.Tag, fc.Count)
+	case Tfsync:
+		ret = fmt.Sprintf("Tfsync tag %d fid %d", fc.Tag, fc.Fid)
+	case Rfsync:
+		ret = fmt.Sprintf("Rfsync tag %d", fc.Tag)
+	case Tlock:
+		ret = fmt.Sprintf("Tlock tag %d fid %d lock %+v", fc.Tag, fc.Fid, fc.Llock)
+	case Rlock:
+		ret = fmt.Sprintf("Rlock tag %d status %d", fc.Tag, fc.Lstatus)
+	case Tgetlock:
+		ret = fmt.Sprintf("Tgetlock tag %d fid %d lock %+v", fc.Tag, fc.Fid, fc.Llock)
+	case Rgetlock:
+		ret = fmt.Sprintf("Rgetlock tag %d lock %+v", fc.Tag, fc.Llock)
+	case Tlink:
+		ret = fmt.Sprintf("Tlink tag %d dfid %d fid %d name '%s'", fc.Tag, fc.Dfid, fc.Fid, fc.Name)
+	case Rlink:
+		ret = fmt.Sprintf("Rlink tag %d", fc.Tag)
+	case Tmkdir:
+		ret = fmt.Sprintf("Tmkdir tag %d fid %d name '%s' mode %o gid %d", fc.Tag, fc.Fid, fc.Name, fc.Lmode, fc.Lgid)
+	case Rmkdir:
+		ret = fmt.Sprintf("Rmkdir tag %d qid %v", fc.Tag, &fc.Qid)
+	case Trenameat:
+		ret = fmt.Sprintf("Trenameat tag %d fid %d name '%s' dfid %d newname '%s'",
+			fc.Tag, fc.Fid, fc.Name, fc.Dfid, fc.Newname)
+	case Rrenameat:
+		ret = fmt.Sprintf("Rrenameat tag %d", fc.Tag)
+	case Tunlinkat:
+		ret = fmt.Sprintf("Tunlinkat tag %d fid %d name '%s' flags %x", fc.Tag, fc.Fid, fc.Name, fc.Lflags)
+	case Runlinkat:
+		ret = fmt.Sprintf("Runlinkat tag %d", fc.Tag)
 	}
 
 	return ret
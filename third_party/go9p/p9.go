@@ -101,14 +101,30 @@ const (
 	NOUID uint32 = 0xFFFFFFFF // no uid specified
 )
 
-// Error values
+// Error values, which are those of Linux
 const (
-	EPERM   = 1
-	ENOENT  = 2
-	EIO     = 5
-	EEXIST  = 17
-	ENOTDIR = 20
-	EINVAL  = 22
+	EPERM        = 1
+	ENOENT       = 2
+	EIO          = 5
+	EBADF        = 9
+	EAGAIN       = 11
+	EACCES       = 13
+	EBUSY        = 16
+	EEXIST       = 17
+	EXDEV        = 18
+	ENOTDIR      = 20
+	EISDIR       = 21
+	EINVAL       = 22
+	EFBIG        = 27
+	ENOSPC       = 28
+	EROFS        = 30
+	EMLINK       = 31
+	ENAMETOOLONG = 36
+	ENOSYS       = 38
+	ENOTEMPTY    = 39
+	ELOOP        = 40
+	ENODATA      = 61
+	EOPNOTSUPP   = 95
 )
 
 // Error represents a 9P2000 (and 9P2000.u) error
@@ -177,6 +193,20 @@ type Fcall struct {
 	Ext      string // special file description, 9P2000.u only (used by Tcreate)
 	Unamenum uint32 // user ID, 9P2000.u only (used by Tauth, Tattach)
 
+	/* 9P2000.L extensions */
+	Dfid     uint32   // directory fid (used by Trename, Trenameat, Tlink)
+	Lflags   uint32   // open(2) or unlinkat(2) flags (used by Tlopen, Tlcreate, Tunlinkat)
+	Lmode    uint32   // file mode (used by Tlcreate, Tmkdir)
+	Lgid     uint32   // group ID (used by Tlcreate, Tsymlink, Tmkdir)
+	Newname  string   // new file name (used by Trenameat)
+	Target   string   // symbolic link target (used by Tsymlink, Rreadlink)
+	Mask     uint64   // requested attributes (used by Tgetattr)
+	Lattr    Lattr    // file attributes (used by Rgetattr)
+	Lsetattr Lsetattr // attributes to change (used by Tsetattr)
+	Lstatfs  Lstatfs  // file system information (used by Rstatfs)
+	Llock    Llock    // lock description (used by Tlock, Tgetlock, Rgetlock)
+	Lstatus  uint8    // lock status (used by Rlock)
+
 	Pkt []uint8 // raw packet data
 	Buf []uint8 // buffer to put the raw data in
 }
@@ -0,0 +1,379 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"fmt"
+)
+
+// 9P2000.L message types. The messages shared with 9P2000.u keep their types.
+const (
+	Tlerror      = 6
+	Rlerror      = 7
+	Tstatfs      = 8
+	Rstatfs      = 9
+	Tlopen       = 12
+	Rlopen       = 13
+	Tlcreate     = 14
+	Rlcreate     = 15
+	Tsymlink     = 16
+	Rsymlink     = 17
+	Tmknod       = 18
+	Rmknod       = 19
+	Trename      = 20
+	Rrename      = 21
+	Treadlink    = 22
+	Rreadlink    = 23
+	Tgetattr     = 24
+	Rgetattr     = 25
+	Tsetattr     = 26
+	Rsetattr     = 27
+	Txattrwalk   = 30
+	Rxattrwalk   = 31
+	Txattrcreate = 32
+	Rxattrcreate = 33
+	Treaddir     = 40
+	Rreaddir     = 41
+	Tfsync       = 50
+	Rfsync       = 51
+	Tlock        = 52
+	Rlock        = 53
+	Tgetlock     = 54
+	Rgetlock     = 55
+	Tlink        = 70
+	Rlink        = 71
+	Tmkdir       = 72
+	Rmkdir       = 73
+	Trenameat    = 74
+	Rrenameat    = 75
+	Tunlinkat    = 76
+	Runlinkat    = 77
+)
+
+// Flags for the flags field in Tlopen and Tlcreate messages, which are those of open(2) on Linux
+const (
+	LOREAD   = 0       // open read-only
+	LOWRITE  = 1       // open write-only
+	LORDWR   = 2       // open read-write
+	LOCREATE = 0100    // create the file if it does not exist
+	LOEXCL   = 0200    // with LOCREATE, fail if the file exists
+	LOTRUNC  = 01000   // truncate the file first
+	LOAPPEND = 02000   // write at the end of the file
+	LODIR    = 0200000 // fail if the file is not a directory
+)
+
+// Flags for the flags field in Tunlinkat messages
+const (
+	ATREMOVEDIR = 0x200 // remove a directory rather than a file
+)
+
+// Bits for the valid field in Tsetattr messages
+const (
+	ATTRMODE     = 0x1   // set the mode
+	ATTRUID      = 0x2   // set the owner
+	ATTRGID      = 0x4   // set the group
+	ATTRSIZE     = 0x8   // set the size
+	ATTRATIME    = 0x10  // set the access time
+	ATTRMTIME    = 0x20  // set the modification time
+	ATTRCTIME    = 0x40  // set the change time
+	ATTRATIMESET = 0x80  // set the access time to the given value rather than the current time
+	ATTRMTIMESET = 0x100 // set the modification time to the given value rather than the current time
+)
+
+// Bits for the mask fields in Tgetattr and Rgetattr messages
+const (
+	GETATTRBASIC = 0x7ff // mode, nlink, uid, gid, rdev, atime, mtime, ctime, ino, size and blocks
+)
+
+// Values of the Tlock and Rlock messages
+const (
+	LOCKTYPERDLCK = 0 // read lock
+	LOCKTYPEWRLCK = 1 // write lock
+	LOCKTYPEUNLCK = 2 // unlock
+	LOCKSUCCESS   = 0 // the lock was acquired
+	LOCKBLOCKED   = 1 // the lock is held by someone else
+)
+
+// Lattr describes a file in 9P2000.L (used by Rgetattr)
+type Lattr struct {
+	Valid       uint64 // which of the attributes are set
+	Qid                // file's Qid
+	Mode        uint32 // file type and permissions, as in stat(2)
+	Uid         uint32 // owner ID
+	Gid         uint32 // group ID
+	Nlink       uint64 // number of hard links
+	Rdev        uint64 // device ID, for special files
+	Size        uint64 // file length in bytes
+	Blksize     uint64 // preferred block size for I/O
+	Blocks      uint64 // number of 512 byte blocks allocated
+	AtimeSec    uint64 // last access time
+	AtimeNsec   uint64
+	MtimeSec    uint64 // last modification time
+	MtimeNsec   uint64
+	CtimeSec    uint64 // last status change time
+	CtimeNsec   uint64
+	BtimeSec    uint64 // creation time
+	BtimeNsec   uint64
+	Gen         uint64
+	DataVersion uint64
+}
+
+// Lsetattr describes the changes to a file in 9P2000.L (used by Tsetattr)
+type Lsetattr struct {
+	Valid     uint32 // which of the attributes to change (ATTR* flags)
+	Mode      uint32 // permissions
+	Uid       uint32 // owner ID
+	Gid       uint32 // group ID
+	Size      uint64 // file length in bytes
+	AtimeSec  uint64 // last access time
+	AtimeNsec uint64
+	MtimeSec  uint64 // last modification time
+	MtimeNsec uint64
+}
+
+// Lstatfs describes a file system in 9P2000.L (used by Rstatfs)
+type Lstatfs struct {
+	Type    uint32 // type of the file system
+	Bsize   uint32 // block size
+	Blocks  uint64 // total number of blocks
+	Bfree   uint64 // number of free blocks
+	Bavail  uint64 // number of blocks available to unprivileged users
+	Files   uint64 // total number of files
+	Ffree   uint64 // number of free files
+	Fsid    uint64 // file system ID
+	Namelen uint32 // maximum length of file names
+}
+
+// Llock describes a POSIX record lock in 9P2000.L (used by Tlock, Tgetlock, Rgetlock)
+type Llock struct {
+	Type     uint8  // LOCKTYPE* value
+	Flags    uint32 // lock flags, Tlock only
+	Start    uint64 // offset of the first locked byte
+	Length   uint64 // number of locked bytes, 0 for all the bytes from Start on
+	ProcId   uint32 // process holding the lock
+	ClientId string // client holding the lock
+}
+
+// minimum size of a 9P2000.L message for a type, without size[4] type[1] tag[2]
+var minFclsize = map[uint8]int{
+	Tstatfs:      4,  /* fid[4] */
+	Tlopen:       8,  /* fid[4] flags[4] */
+	Tlcreate:     18, /* fid[4] name[s] flags[4] mode[4] gid[4] */
+	Tsymlink:     12, /* fid[4] name[s] symtgt[s] gid[4] */
+	Tmknod:       22, /* dfid[4] name[s] mode[4] major[4] minor[4] gid[4] */
+	Trename:      10, /* fid[4] dfid[4] name[s] */
+	Treadlink:    4,  /* fid[4] */
+	Tgetattr:     12, /* fid[4] request_mask[8] */
+	Tsetattr:     60, /* fid[4] valid[4] mode[4] uid[4] gid[4] size[8] atime[16] mtime[16] */
+	Txattrwalk:   10, /* fid[4] newfid[4] name[s] */
+	Txattrcreate: 18, /* fid[4] name[s] attr_size[8] flags[4] */
+	Treaddir:     16, /* fid[4] offset[8] count[4] */
+	Tfsync:       4,  /* fid[4] (datasync[4]) */
+	Tlock:        31, /* fid[4] type[1] flags[4] start[8] length[8] proc_id[4] client_id[s] */
+	Tgetlock:     27, /* fid[4] type[1] start[8] length[8] proc_id[4] client_id[s] */
+	Tlink:        10, /* dfid[4] fid[4] name[s] */
+	Tmkdir:       14, /* dfid[4] name[s] mode[4] gid[4] */
+	Trenameat:    12, /* olddirfid[4] oldname[s] newdirfid[4] newname[s] */
+	Tunlinkat:    10, /* dirfd[4] name[s] flags[4] */
+}
+
+// Creates a Fcall value from the on-the-wire representation of a
+// 9P2000.L message. The messages that 9P2000.L shares with 9P2000.u
+// are read as 9P2000.u messages. Returns the unpacked message, error
+// and how many bytes from the buffer were used by the message.
+func UnpackL(buf []byte) (fc *Fcall, err error, fcsz int) {
+	var minsz int
+	var ok bool
+
+	if len(buf) < 7 {
+		return nil, &Error{"buffer too short", EINVAL}, 0
+	}
+
+	if buf[4] >= Tversion {
+		return Unpack(buf, true)
+	}
+
+	fc = new(Fcall)
+	fc.Fid = NOFID
+	fc.Afid = NOFID
+	fc.Newfid = NOFID
+	fc.Dfid = NOFID
+
+	p := buf
+	fc.Size, p = gint32(p)
+	fc.Type, p = gint8(p)
+	fc.Tag, p = gint16(p)
+
+	if int(fc.Size) > len(buf) || fc.Size < 7 {
+		return nil, &Error{fmt.Sprintf("buffer too short: %d expected %d",
+				len(buf), fc.Size),
+				EINVAL},
+			0
+	}
+
+	p = p[0 : fc.Size-7]
+	fc.Pkt = buf[0:fc.Size]
+	fcsz = int(fc.Size)
+	if minsz, ok = minFclsize[fc.Type]; !ok {
+		return nil, &Error{"invalid id", EINVAL}, 0
+	}
+
+	if len(p) < minsz {
+		goto szerror
+	}
+
+	switch fc.Type {
+	case Tstatfs, Treadlink:
+		fc.Fid, p = gint32(p)
+
+	case Tlopen:
+		fc.Fid, p = gint32(p)
+		fc.Lflags, p = gint32(p)
+
+	case Tlcreate:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if len(p) < 12 {
+			goto szerror
+		}
+		fc.Lflags, p = gint32(p)
+		fc.Lmode, p = gint32(p)
+		fc.Lgid, p = gint32(p)
+
+	case Tsymlink:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		fc.Target, p = gstr(p)
+		if len(p) < 4 {
+			goto szerror
+		}
+		fc.Lgid, p = gint32(p)
+
+	case Tmknod:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if len(p) < 16 {
+			goto szerror
+		}
+		fc.Lmode, p = gint32(p)
+		_, p = gint32(p) /* major */
+		_, p = gint32(p) /* minor */
+		fc.Lgid, p = gint32(p)
+
+	case Trename:
+		fc.Fid, p = gint32(p)
+		fc.Dfid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tgetattr:
+		fc.Fid, p = gint32(p)
+		fc.Mask, p = gint64(p)
+
+	case Tsetattr:
+		a := &fc.Lsetattr
+		fc.Fid, p = gint32(p)
+		a.Valid, p = gint32(p)
+		a.Mode, p = gint32(p)
+		a.Uid, p = gint32(p)
+		a.Gid, p = gint32(p)
+		a.Size, p = gint64(p)
+		a.AtimeSec, p = gint64(p)
+		a.AtimeNsec, p = gint64(p)
+		a.MtimeSec, p = gint64(p)
+		a.MtimeNsec, p = gint64(p)
+
+	case Txattrwalk:
+		fc.Fid, p = gint32(p)
+		fc.Newfid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Txattrcreate:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if len(p) < 12 {
+			goto szerror
+		}
+		_, p = gint64(p) /* attr_size */
+		fc.Lflags, p = gint32(p)
+
+	case Treaddir:
+		fc.Fid, p = gint32(p)
+		fc.Offset, p = gint64(p)
+		fc.Count, p = gint32(p)
+
+	case Tfsync:
+		fc.Fid, p = gint32(p)
+		if len(p) >= 4 {
+			_, p = gint32(p) /* datasync */
+		}
+
+	case Tlock, Tgetlock:
+		l := &fc.Llock
+		fc.Fid, p = gint32(p)
+		l.Type, p = gint8(p)
+		if fc.Type == Tlock {
+			l.Flags, p = gint32(p)
+		}
+		l.Start, p = gint64(p)
+		l.Length, p = gint64(p)
+		l.ProcId, p = gint32(p)
+		l.ClientId, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tlink:
+		fc.Dfid, p = gint32(p)
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tmkdir:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if len(p) < 8 {
+			goto szerror
+		}
+		fc.Lmode, p = gint32(p)
+		fc.Lgid, p = gint32(p)
+
+	case Trenameat:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if len(p) < 6 {
+			goto szerror
+		}
+		fc.Dfid, p = gint32(p)
+		fc.Newname, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tunlinkat:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if len(p) < 4 {
+			goto szerror
+		}
+		fc.Lflags, p = gint32(p)
+	}
+
+	if len(p) > 0 {
+		goto szerror
+	}
+
+	return //NOSONAR
+
+szerror:
+	return nil, &Error{"invalid size", EINVAL}, 0
+}
@@ -0,0 +1,224 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+// Create a Rlerror message in the specified Fcall.
+func PackRlerror(fc *Fcall, ecode uint32) error {
+	p, err := packCommon(fc, 4, Rlerror) /* ecode[4] */
+	if err != nil {
+		return err
+	}
+
+	fc.Errornum = ecode
+	p = pint32(ecode, p)
+	return nil
+}
+
+// Create a Rstatfs message in the specified Fcall.
+func PackRstatfs(fc *Fcall, st *Lstatfs) error {
+	size := 4 + 4 + 8*6 + 4 /* type[4] bsize[4] blocks[8] bfree[8] bavail[8] files[8] ffree[8] fsid[8] namelen[4] */
+	p, err := packCommon(fc, size, Rstatfs)
+	if err != nil {
+		return err
+	}
+
+	fc.Lstatfs = *st
+	p = pint32(st.Type, p)
+	p = pint32(st.Bsize, p)
+	p = pint64(st.Blocks, p)
+	p = pint64(st.Bfree, p)
+	p = pint64(st.Bavail, p)
+	p = pint64(st.Files, p)
+	p = pint64(st.Ffree, p)
+	p = pint64(st.Fsid, p)
+	p = pint32(st.Namelen, p)
+	return nil
+}
+
+func packQidIounit(fc *Fcall, id uint8, qid *Qid, iounit uint32) error {
+	size := 13 + 4 /* qid[13] iounit[4] */
+	p, err := packCommon(fc, size, id)
+	if err != nil {
+		return err
+	}
+
+	fc.Qid = *qid
+	fc.Iounit = iounit
+	p = pqid(qid, p)
+	p = pint32(iounit, p)
+	return nil
+}
+
+func packQid(fc *Fcall, id uint8, qid *Qid) error {
+	p, err := packCommon(fc, 13, id) /* qid[13] */
+	if err != nil {
+		return err
+	}
+
+	fc.Qid = *qid
+	p = pqid(qid, p)
+	return nil
+}
+
+// Create a Rlopen message in the specified Fcall.
+func PackRlopen(fc *Fcall, qid *Qid, iounit uint32) error {
+	return packQidIounit(fc, Rlopen, qid, iounit)
+}
+
+// Create a Rlcreate message in the specified Fcall.
+func PackRlcreate(fc *Fcall, qid *Qid, iounit uint32) error {
+	return packQidIounit(fc, Rlcreate, qid, iounit)
+}
+
+// Create a Rsymlink message in the specified Fcall.
+func PackRsymlink(fc *Fcall, qid *Qid) error {
+	return packQid(fc, Rsymlink, qid)
+}
+
+// Create a Rmkdir message in the specified Fcall.
+func PackRmkdir(fc *Fcall, qid *Qid) error {
+	return packQid(fc, Rmkdir, qid)
+}
+
+// Create a Rrename message in the specified Fcall.
+func PackRrename(fc *Fcall) error {
+	_, err := packCommon(fc, 0, Rrename)
+	return err
+}
+
+// Create a Rreadlink message in the specified Fcall.
+func PackRreadlink(fc *Fcall, target string) error {
+	p, err := packCommon(fc, 2+len(target), Rreadlink) /* target[s] */
+	if err != nil {
+		return err
+	}
+
+	fc.Target = target
+	p = pstr(target, p)
+	return nil
+}
+
+// Create a Rgetattr message in the specified Fcall.
+func PackRgetattr(fc *Fcall, attr *Lattr) error {
+	size := 8 + 13 + 4*3 + 8*15 /* valid[8] qid[13] mode[4] uid[4] gid[4] nlink[8] rdev[8] size[8] blksize[8] blocks[8] times[64] gen[8] data_version[8] */
+	p, err := packCommon(fc, size, Rgetattr)
+	if err != nil {
+		return err
+	}
+
+	fc.Lattr = *attr
+	p = pint64(attr.Valid, p)
+	p = pqid(&attr.Qid, p)
+	p = pint32(attr.Mode, p)
+	p = pint32(attr.Uid, p)
+	p = pint32(attr.Gid, p)
+	p = pint64(attr.Nlink, p)
+	p = pint64(attr.Rdev, p)
+	p = pint64(attr.Size, p)
+	p = pint64(attr.Blksize, p)
+	p = pint64(attr.Blocks, p)
+	p = pint64(attr.AtimeSec, p)
+	p = pint64(attr.AtimeNsec, p)
+	p = pint64(attr.MtimeSec, p)
+	p = pint64(attr.MtimeNsec, p)
+	p = pint64(attr.CtimeSec, p)
+	p = pint64(attr.CtimeNsec, p)
+	p = pint64(attr.BtimeSec, p)
+	p = pint64(attr.BtimeNsec, p)
+	p = pint64(attr.Gen, p)
+	p = pint64(attr.DataVersion, p)
+	return nil
+}
+
+// Create a Rsetattr message in the specified Fcall.
+func PackRsetattr(fc *Fcall) error {
+	_, err := packCommon(fc, 0, Rsetattr)
+	return err
+}
+
+// Initializes the specified Fcall value to contain Rreaddir message.
+// The user should fill fc.Data with directory entries created by
+// PackLdirent and call SetRreadCount to update the data size to the
+// actual value.
+func InitRreaddir(fc *Fcall, count uint32) error {
+	size := int(4 + count) /* count[4] data[count] */
+	p, err := packCommon(fc, size, Rreaddir)
+	if err != nil {
+		return err
+	}
+
+	fc.Count = count
+	fc.Data = p[4 : fc.Count+4]
+	p = pint32(count, p)
+	return nil
+}
+
+// Size of the on-the-wire representation of a 9P2000.L directory entry.
+func LdirentSize(name string) int {
+	return 13 + 8 + 1 + 2 + len(name) /* qid[13] offset[8] type[1] name[s] */
+}
+
+// Converts a 9P2000.L directory entry to its on-the-wire representation
+// and writes it to buf, which must hold LdirentSize(name) bytes. Offset is
+// the position of the next entry in the directory. Returns the rest of buf.
+func PackLdirent(buf []byte, qid *Qid, offset uint64, typ uint8, name string) []byte {
+	buf = pqid(qid, buf)
+	buf = pint64(offset, buf)
+	buf = pint8(typ, buf)
+	return pstr(name, buf)
+}
+
+// Create a Rfsync message in the specified Fcall.
+func PackRfsync(fc *Fcall) error {
+	_, err := packCommon(fc, 0, Rfsync)
+	return err
+}
+
+// Create a Rlock message in the specified Fcall.
+func PackRlock(fc *Fcall, status uint8) error {
+	p, err := packCommon(fc, 1, Rlock) /* status[1] */
+	if err != nil {
+		return err
+	}
+
+	fc.Lstatus = status
+	p = pint8(status, p)
+	return nil
+}
+
+// Create a Rgetlock message in the specified Fcall.
+func PackRgetlock(fc *Fcall, l *Llock) error {
+	size := 1 + 8 + 8 + 4 + 2 + len(l.ClientId) /* type[1] start[8] length[8] proc_id[4] client_id[s] */
+	p, err := packCommon(fc, size, Rgetlock)
+	if err != nil {
+		return err
+	}
+
+	fc.Llock = *l
+	p = pint8(l.Type, p)
+	p = pint64(l.Start, p)
+	p = pint64(l.Length, p)
+	p = pint32(l.ProcId, p)
+	p = pstr(l.ClientId, p)
+	return nil
+}
+
+// Create a Rlink message in the specified Fcall.
+func PackRlink(fc *Fcall) error {
+	_, err := packCommon(fc, 0, Rlink)
+	return err
+}
+
+// Create a Rrenameat message in the specified Fcall.
+func PackRrenameat(fc *Fcall) error {
+	_, err := packCommon(fc, 0, Rrenameat)
+	return err
+}
+
+// Create a Runlinkat message in the specified Fcall.
+func PackRunlinkat(fc *Fcall) error {
+	_, err := packCommon(fc, 0, Runlinkat)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"time"
 )
 
 func (srv *Srv) NewConn(c net.Conn) {
@@ -22,6 +23,11 @@ func (srv *Srv) NewConn(c net.Conn) {
 	conn.reqout = make(chan *SrvReq, srv.Maxpend)
 	conn.done = make(chan bool)
 	conn.rchan = make(chan *Fcall, 64)
+	conn.work = make([]chan *SrvReq, srv.Workers)
+	for i := range conn.work {
+		conn.work[i] = make(chan *SrvReq, 64)
+		go conn.worker(conn.work[i])
+	}
 
 	srv.Lock()
 	if srv.conns == nil {
@@ -45,6 +51,9 @@ func (srv *Srv) NewConn(c net.Conn) {
 
 func (conn *Conn) close() {
 	conn.done <- true
+	for _, work := range conn.work {
+		close(work)
+	}
 	conn.Srv.Lock()
 	delete(conn.Srv.conns, conn)
 	conn.Srv.Unlock()
@@ -103,7 +112,13 @@ func (conn *Conn) recv() {
 
 				break
 			}
-			fc, err, fcsize := Unpack(buf, conn.Dotu)
+			var fc *Fcall
+			var fcsize int
+			if conn.Dotl {
+				fc, err, fcsize = UnpackL(buf)
+			} else {
+				fc, err, fcsize = Unpack(buf, conn.Dotu)
+			}
 			if err != nil {
 				log.Println(fmt.Sprintf("invalid packet : %v %v", err, buf))
 				conn.conn.Close()
@@ -122,6 +137,7 @@ func (conn *Conn) recv() {
 
 			req.Conn = conn
 			req.Tc = fc
+			req.start = time.Now()
 			//			req.Rc = rc
 			if conn.Debuglevel > 0 {
 				conn.logFcall(req.Tc)
@@ -150,15 +166,7 @@ func (conn *Conn) recv() {
 			}
 			conn.Unlock()
 			if process {
-				// Tversion may change some attributes of the
-				// connection, so we block on it. Otherwise,
-				// we may loop back to reading and that is a race.
-				// This fix brought to you by the race detector.
-				if req.Tc.Type == Tversion {
-					req.process()
-				} else {
-					go req.process()
-				}
+				conn.dispatch(req)
 			}
 
 			buf = buf[fcsize:]
@@ -168,6 +176,34 @@ func (conn *Conn) recv() {
 
 }
 
+// Hands a request over for processing. The requests for a fid are
+// processed by the same worker, in the order they were received, so
+// that the state of a fid needs no locking. Requests for different
+// fids are processed concurrently.
+func (conn *Conn) dispatch(req *SrvReq) {
+	switch {
+	case req.Tc.Type == Tversion:
+		// Tversion may change some attributes of the
+		// connection, so we block on it. Otherwise,
+		// we may loop back to reading and that is a race.
+		// This fix brought to you by the race detector.
+		req.process()
+
+	case req.Tc.Fid == NOFID:
+		// Tauth and Tflush must not wait behind the requests they are about
+		go req.process()
+
+	default:
+		conn.work[int(req.Tc.Fid%uint32(len(conn.work)))] <- req
+	}
+}
+
+func (conn *Conn) worker(work chan *SrvReq) {
+	for req := range work {
+		req.process()
+	}
+}
+
 func (conn *Conn) send() {
 	for {
 		select {
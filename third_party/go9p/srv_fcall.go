@@ -19,9 +19,13 @@ func (srv *Srv) version(req *SrvReq) {
 		conn.Msize = tc.Msize
 	}
 
-	conn.Dotu = tc.Version == "9P2000.u" && srv.Dotu
+	// 9P2000.L reads and writes the messages it shares with 9P2000.u as 9P2000.u does
+	conn.Dotl = tc.Version == "9P2000.L" && srv.Dotl
+	conn.Dotu = conn.Dotl || (tc.Version == "9P2000.u" && srv.Dotu)
 	ver := "9P2000"
-	if conn.Dotu {
+	if conn.Dotl {
+		ver = "9P2000.L"
+	} else if conn.Dotu {
 		ver = "9P2000.u"
 	}
 
@@ -0,0 +1,217 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+var Enotsupp error = &Error{"operation not supported", EOPNOTSUPP}
+
+// Returns the 9P2000.L operations, responding with an error
+// if the connection does not speak 9P2000.L.
+func (srv *Srv) lops(req *SrvReq) SrvReqLOps {
+	if !req.Conn.Dotl {
+		req.RespondError(&Error{"unknown message type", EINVAL})
+		return nil
+	}
+
+	return (srv.ops).(SrvReqLOps)
+}
+
+// Looks up the directory fid of the request. Returns false after
+// responding with an error if there is no such fid.
+func (srv *Srv) dfid(req *SrvReq) bool {
+	req.Dfid = req.Conn.FidGet(req.Tc.Dfid)
+	if req.Dfid == nil {
+		req.RespondError(Eunknownfid)
+		return false
+	}
+
+	if (req.Dfid.Type & QTDIR) == 0 {
+		req.RespondError(Enotdir)
+		return false
+	}
+
+	return true
+}
+
+func (srv *Srv) statfs(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		ops.Statfs(req)
+	}
+}
+
+func (srv *Srv) lopen(req *SrvReq) {
+	fid := req.Fid
+	ops := srv.lops(req)
+	if ops == nil {
+		return
+	}
+
+	if fid.opened {
+		req.RespondError(Eopen)
+		return
+	}
+
+	mode := uint8(req.Tc.Lflags & 3)
+	if (fid.Type&QTDIR) != 0 && mode != OREAD {
+		req.RespondError(Eperm)
+		return
+	}
+
+	fid.Omode = mode
+	ops.Lopen(req)
+}
+
+func (srv *Srv) lopenPost(req *SrvReq) {
+	if req.Fid != nil {
+		req.Fid.opened = req.Rc != nil && req.Rc.Type == Rlopen
+	}
+}
+
+func (srv *Srv) lcreate(req *SrvReq) {
+	fid := req.Fid
+	ops := srv.lops(req)
+	if ops == nil {
+		return
+	}
+
+	if fid.opened {
+		req.RespondError(Eopen)
+		return
+	}
+
+	if (fid.Type & QTDIR) == 0 {
+		req.RespondError(Enotdir)
+		return
+	}
+
+	fid.Omode = uint8(req.Tc.Lflags & 3)
+	ops.Lcreate(req)
+}
+
+func (srv *Srv) lcreatePost(req *SrvReq) {
+	if req.Rc != nil && req.Rc.Type == Rlcreate && req.Fid != nil {
+		req.Fid.Type = req.Rc.Qid.Type
+		req.Fid.opened = true
+	}
+}
+
+// Checks that the fid of a request that creates a file or changes
+// the entries of a directory is a directory.
+func (srv *Srv) ldir(req *SrvReq) SrvReqLOps {
+	ops := srv.lops(req)
+	if ops == nil {
+		return nil
+	}
+
+	if (req.Fid.Type & QTDIR) == 0 {
+		req.RespondError(Enotdir)
+		return nil
+	}
+
+	return ops
+}
+
+func (srv *Srv) symlink(req *SrvReq) {
+	if ops := srv.ldir(req); ops != nil {
+		ops.Symlink(req)
+	}
+}
+
+func (srv *Srv) rename(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil && srv.dfid(req) {
+		ops.Rename(req)
+	}
+}
+
+func (srv *Srv) readlink(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		ops.Readlink(req)
+	}
+}
+
+func (srv *Srv) getattr(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		ops.Getattr(req)
+	}
+}
+
+func (srv *Srv) setattr(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		ops.Setattr(req)
+	}
+}
+
+func (srv *Srv) readdir(req *SrvReq) {
+	tc := req.Tc
+	fid := req.Fid
+	ops := srv.lops(req)
+	if ops == nil {
+		return
+	}
+
+	if tc.Count+IOHDRSZ > req.Conn.Msize {
+		req.RespondError(Etoolarge)
+		return
+	}
+
+	if !fid.opened || (fid.Type&QTDIR) == 0 {
+		req.RespondError(Ebaduse)
+		return
+	}
+
+	ops.Readdir(req)
+}
+
+func (srv *Srv) fsync(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		ops.Fsync(req)
+	}
+}
+
+// Locks are advisory, and the clients of a mount enforce them among
+// themselves. The server grants every lock, and reports none.
+func (srv *Srv) lock(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		req.RespondRlock(LOCKSUCCESS)
+	}
+}
+
+func (srv *Srv) getlock(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		l := req.Tc.Llock
+		l.Type = LOCKTYPEUNLCK
+		req.RespondRgetlock(&l)
+	}
+}
+
+func (srv *Srv) link(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil && srv.dfid(req) {
+		ops.Link(req)
+	}
+}
+
+func (srv *Srv) mkdir(req *SrvReq) {
+	if ops := srv.ldir(req); ops != nil {
+		ops.Mkdir(req)
+	}
+}
+
+func (srv *Srv) renameat(req *SrvReq) {
+	if ops := srv.ldir(req); ops != nil && srv.dfid(req) {
+		ops.Renameat(req)
+	}
+}
+
+func (srv *Srv) unlinkat(req *SrvReq) {
+	if ops := srv.ldir(req); ops != nil {
+		ops.Unlinkat(req)
+	}
+}
+
+// Device files and extended attributes are not supported.
+func (srv *Srv) lunsupported(req *SrvReq) {
+	if ops := srv.lops(req); ops != nil {
+		req.RespondError(Enotsupp)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+// SrvRequest operations of the 9P2000.L extension. This interface should be
+// implemented by file servers that set Dotl. The operations correspond
+// directly to the 9P2000.L message types. Locks are left to the clients,
+// and extended attributes are not supported.
+type SrvReqLOps interface {
+	Statfs(*SrvReq)
+	Lopen(*SrvReq)
+	Lcreate(*SrvReq)
+	Symlink(*SrvReq)
+	Rename(*SrvReq)
+	Readlink(*SrvReq)
+	Getattr(*SrvReq)
+	Setattr(*SrvReq)
+	Readdir(*SrvReq)
+	Fsync(*SrvReq)
+	Link(*SrvReq)
+	Mkdir(*SrvReq)
+	Renameat(*SrvReq)
+	Unlinkat(*SrvReq)
+}
+
+func (req *SrvReq) respond(err error) {
+	if err != nil {
+		req.RespondError(err)
+	} else {
+		req.Respond()
+	}
+}
+
+// Respond to the request with Rstatfs message
+func (req *SrvReq) RespondRstatfs(st *Lstatfs) {
+	req.respond(PackRstatfs(req.Rc, st))
+}
+
+// Respond to the request with Rlopen message
+func (req *SrvReq) RespondRlopen(qid *Qid, iounit uint32) {
+	req.respond(PackRlopen(req.Rc, qid, iounit))
+}
+
+// Respond to the request with Rlcreate message
+func (req *SrvReq) RespondRlcreate(qid *Qid, iounit uint32) {
+	req.respond(PackRlcreate(req.Rc, qid, iounit))
+}
+
+// Respond to the request with Rsymlink message
+func (req *SrvReq) RespondRsymlink(qid *Qid) {
+	req.respond(PackRsymlink(req.Rc, qid))
+}
+
+// Respond to the request with Rrename message
+func (req *SrvReq) RespondRrename() {
+	req.respond(PackRrename(req.Rc))
+}
+
+// Respond to the request with Rreadlink message
+func (req *SrvReq) RespondRreadlink(target string) {
+	req.respond(PackRreadlink(req.Rc, target))
+}
+
+// Respond to the request with Rgetattr message
+func (req *SrvReq) RespondRgetattr(attr *Lattr) {
+	req.respond(PackRgetattr(req.Rc, attr))
+}
+
+// Respond to the request with Rsetattr message
+func (req *SrvReq) RespondRsetattr() {
+	req.respond(PackRsetattr(req.Rc))
+}
+
+// Respond to the request with Rfsync message
+func (req *SrvReq) RespondRfsync() {
+	req.respond(PackRfsync(req.Rc))
+}
+
+// Respond to the request with Rlock message
+func (req *SrvReq) RespondRlock(status uint8) {
+	req.respond(PackRlock(req.Rc, status))
+}
+
+// Respond to the request with Rgetlock message
+func (req *SrvReq) RespondRgetlock(l *Llock) {
+	req.respond(PackRgetlock(req.Rc, l))
+}
+
+// Respond to the request with Rlink message
+func (req *SrvReq) RespondRlink() {
+	req.respond(PackRlink(req.Rc))
+}
+
+// Respond to the request with Rmkdir message
+func (req *SrvReq) RespondRmkdir(qid *Qid) {
+	req.respond(PackRmkdir(req.Rc, qid))
+}
+
+// Respond to the request with Rrenameat message
+func (req *SrvReq) RespondRrenameat() {
+	req.respond(PackRrenameat(req.Rc))
+}
+
+// Respond to the request with Runlinkat message
+func (req *SrvReq) RespondRunlinkat() {
+	req.respond(PackRunlinkat(req.Rc))
+}
@@ -21,8 +21,25 @@ type SrvReqOps interface {
 	Wstat(*SrvReq)
 }
 
-// Respond to the request with Rerror message
+// Respond to the request with Rerror message, or Rlerror message if
+// the connection speaks 9P2000.L
 func (req *SrvReq) RespondError(err interface{}) {
+	if req.Conn.Dotl {
+		var ecode uint32
+		switch e := err.(type) {
+		case *Error:
+			ecode = e.Errornum
+		case error:
+			ecode = toError(e).Errornum
+		}
+		if ecode == 0 {
+			ecode = EIO
+		}
+		PackRlerror(req.Rc, ecode)
+		req.Respond()
+		return
+	}
+
 	switch e := err.(type) {
 	case *Error:
 		PackRerror(req.Rc, e.Error(), uint32(e.Errornum), req.Conn.Dotu)
@@ -10,8 +10,12 @@ import (
 	"net"
 	"runtime"
 	"sync"
+	"time"
 )
 
+// The default number of goroutines processing the requests of a connection
+const WORKERS = 16
+
 type reqStatus int
 
 const (
@@ -119,13 +123,16 @@ type Srv struct {
 	Id         string // Used for debugging and stats
 	Msize      uint32 // Maximum size of the 9P2000 messages supported by the server
 	Dotu       bool   // If true, the server supports the 9P2000.u extension
+	Dotl       bool   // If true, the server supports the 9P2000.L extension
 	Debuglevel int    // debug level
 	Upool      Users  // Interface for finding users and groups known to the file server
 	Maxpend    int    // Maximum pending outgoing requests
+	Workers    int    // Number of goroutines processing the requests of a connection
 	Log        *Logger
 
 	ops   interface{}     // operations
 	conns map[*Conn]*Conn // List of connections
+	stats opStats         // latency of the requests
 }
 
 // The Conn type represents a connection from a client to the file server
@@ -134,12 +141,14 @@ type Conn struct {
 	Srv        *Srv
 	Msize      uint32 // maximum size of 9P2000 messages for the connection
 	Dotu       bool   // if true, both the client and the server speak 9P2000.u
+	Dotl       bool   // if true, both the client and the server speak 9P2000.L
 	Id         string // used for debugging and stats
 	Debuglevel int
 
 	conn    net.Conn
 	fidpool map[uint32]*SrvFid
 	reqs    map[uint16]*SrvReq // all outstanding requests
+	work    []chan *SrvReq     // requests to process, by fid
 
 	reqout chan *SrvReq
 	rchan  chan *Fcall
@@ -186,8 +195,10 @@ type SrvReq struct {
 	Fid    *SrvFid // The SrvFid value for all messages that contain fid[4]
 	Afid   *SrvFid // The SrvFid value for the messages that contain afid[4] (Tauth and Tattach)
 	Newfid *SrvFid // The SrvFid value for the messages that contain newfid[4] (Twalk)
+	Dfid   *SrvFid // The SrvFid value for the messages that contain dfid[4] (Trename, Trenameat, Tlink)
 	Conn   *Conn   // Connection that the request belongs to
 
+	start      time.Time // when the request was received
 	status     reqStatus
 	flushreq   *SrvReq
 	prev, next *SrvReq
@@ -198,13 +209,18 @@ type SrvReq struct {
 // values to the fields that are not initialized and creates the goroutines
 // required for the server's operation. The method receives an empty
 // interface value, ops, that should implement the interfaces the file server is
-// interested in. Ops must implement the SrvReqOps interface.
+// interested in. Ops must implement the SrvReqOps interface, and the
+// SrvReqLOps interface for the server to speak 9P2000.L.
 func (srv *Srv) Start(ops interface{}) bool {
 	if _, ok := (ops).(SrvReqOps); !ok {
 		return false
 	}
 
 	srv.ops = ops
+	if _, ok := (ops).(SrvReqLOps); !ok {
+		srv.Dotl = false
+	}
+
 	if srv.Upool == nil {
 		srv.Upool = OsUsers
 	}
@@ -213,6 +229,10 @@ func (srv *Srv) Start(ops interface{}) bool {
 		srv.Msize = MSIZE
 	}
 
+	if srv.Workers <= 0 {
+		srv.Workers = WORKERS
+	}
+
 	if srv.Log == nil {
 		srv.Log = NewLogger(1024)
 	}
@@ -238,6 +258,7 @@ func (req *SrvReq) process() {
 
 	if flushed {
 		req.Respond()
+		return
 	}
 
 	if rop, ok := (req.Conn.Srv.ops).(SrvReqProcessOps); ok {
@@ -319,6 +340,57 @@ func (req *SrvReq) Process() {
 
 	case Twstat:
 		srv.wstat(req)
+
+	case Tstatfs:
+		srv.statfs(req)
+
+	case Tlopen:
+		srv.lopen(req)
+
+	case Tlcreate:
+		srv.lcreate(req)
+
+	case Tsymlink:
+		srv.symlink(req)
+
+	case Trename:
+		srv.rename(req)
+
+	case Treadlink:
+		srv.readlink(req)
+
+	case Tgetattr:
+		srv.getattr(req)
+
+	case Tsetattr:
+		srv.setattr(req)
+
+	case Treaddir:
+		srv.readdir(req)
+
+	case Tfsync:
+		srv.fsync(req)
+
+	case Tlock:
+		srv.lock(req)
+
+	case Tgetlock:
+		srv.getlock(req)
+
+	case Tlink:
+		srv.link(req)
+
+	case Tmkdir:
+		srv.mkdir(req)
+
+	case Trenameat:
+		srv.renameat(req)
+
+	case Tunlinkat:
+		srv.unlinkat(req)
+
+	case Tmknod, Txattrwalk, Txattrcreate:
+		srv.lunsupported(req)
 	}
 }
 
@@ -354,6 +426,12 @@ func (req *SrvReq) PostProcess() {
 
 	case Tremove:
 		srv.removePost(req)
+
+	case Tlopen:
+		srv.lopenPost(req)
+
+	case Tlcreate:
+		srv.lcreatePost(req)
 	}
 
 	if req.Fid != nil {
@@ -370,6 +448,11 @@ func (req *SrvReq) PostProcess() {
 		req.Newfid.DecRef()
 		req.Newfid = nil
 	}
+
+	if req.Dfid != nil {
+		req.Dfid.DecRef()
+		req.Dfid = nil
+	}
 }
 
 // The Respond method sends response back to the client. The req.Rc value
@@ -390,6 +473,10 @@ func (req *SrvReq) Respond() {
 		return
 	}
 
+	if (status & reqFlush) == 0 {
+		conn.Srv.stats.record(req.Tc.Type, time.Since(req.start))
+	}
+
 	/* remove the request and all requests flushing it */
 	conn.Lock()
 	nextreq := req.prev
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"sync"
 )
 
@@ -43,6 +44,20 @@ func (srv *Srv) ServeHTTP(c http.ResponseWriter, r *http.Request) {
 	io.WriteString(c, fmt.Sprintf("<html><body><h1>Server %s</h1>", srv.Id))
 	defer io.WriteString(c, "</body></html>")
 
+	// latency
+	io.WriteString(c, "<h2>Latency</h2><table><tr><th>Message<th>Count<th>Mean<th>Max</tr>")
+	ops := srv.OpStats()
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		op := ops[name]
+		io.WriteString(c, fmt.Sprintf("<tr><td>%s<td>%d<td>%v<td>%v</tr>", name, op.Count, op.Mean(), op.Max))
+	}
+	io.WriteString(c, "</table>")
+
 	// connections
 	io.WriteString(c, "<h2>Connections</h2><p>")
 	srv.Lock()
@@ -1,6 +1,66 @@
 package go9p
 
+import (
+	"sync"
+	"time"
+)
+
 type StatsOps interface {
 	statsRegister()
 	statsUnregister()
 }
+
+// OpStats holds the latency of the requests of one message type,
+// from receiving a request to responding to it.
+type OpStats struct {
+	Count int           // number of requests responded to
+	Total time.Duration // total latency of the requests
+	Max   time.Duration // highest latency of a request
+}
+
+// Mean returns the average latency of the requests.
+func (s OpStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return s.Total / time.Duration(s.Count)
+}
+
+type opStats struct {
+	sync.Mutex
+	ops map[uint8]*OpStats
+}
+
+func (s *opStats) record(typ uint8, d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	if s.ops == nil {
+		s.ops = make(map[uint8]*OpStats)
+	}
+
+	op := s.ops[typ]
+	if op == nil {
+		op = new(OpStats)
+		s.ops[typ] = op
+	}
+
+	op.Count++
+	op.Total += d
+	if d > op.Max {
+		op.Max = d
+	}
+}
+
+// OpStats returns the latency of the requests responded to by the
+// server, by the name of their message type (such as "Tread").
+func (srv *Srv) OpStats() map[string]OpStats {
+	srv.stats.Lock()
+	defer srv.stats.Unlock()
+	ret := make(map[string]OpStats, len(srv.stats.ops))
+	for typ, op := range srv.stats.ops {
+		ret[msgName(typ)] = *op
+	}
+
+	return ret
+}
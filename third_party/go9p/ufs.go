@@ -5,6 +5,7 @@
 package go9p
 
 import (
+	"errors"
 	"io"
 	"log"
 	"os"
@@ -12,10 +13,13 @@ import (
 	"path"
 	"sort"
 	"strconv"
+	"sync"
 	"syscall"
+	"time"
 )
 
 type ufsFid struct {
+	ufs        *Ufs
 	path       string
 	file       *os.File
 	dirs       []os.FileInfo
@@ -23,19 +27,33 @@ type ufsFid struct {
 	dirents    []byte
 	diroffset  uint64
 	st         os.FileInfo
+
+	// read-ahead buffer
+	ra      []byte
+	raoff   int64
+	raeof   bool
+	ragen   uint64
+	ramtime time.Time
+	rasize  int64
 }
 
 type Ufs struct {
 	Srv
-	Root string
+	Root         string
+	StatCacheTTL time.Duration // How long the attributes of files are cached, 0 to not cache them
+	ReadAhead    int           // How many bytes are read at once for reads from files, 0 to not read ahead
+
+	cacheOnce sync.Once
+	fcache    *ufsCache
 }
 
 func toError(err error) *Error {
 	var ecode uint32
+	var errno syscall.Errno
 
 	ename := err.Error()
-	if e, ok := err.(syscall.Errno); ok {
-		ecode = uint32(e)
+	if errors.As(err, &errno) {
+		ecode = linuxErrno(errno)
 	} else {
 		ecode = EIO
 	}
@@ -46,7 +64,7 @@ func toError(err error) *Error {
 func (fid *ufsFid) stat() *Error {
 	var err error
 
-	fid.st, err = os.Lstat(fid.path)
+	fid.st, err = fid.ufs.cache().lstat(fid.path)
 	if err != nil {
 		return toError(err)
 	}
@@ -169,6 +187,7 @@ func (ufs *Ufs) Attach(req *SrvReq) {
 
 	tc := req.Tc
 	fid := new(ufsFid)
+	fid.ufs = ufs
 	// You can think of the ufs.Root as a 'chroot' of a sort.
 	// clients attach are not allowed to go outside the
 	// directory represented by ufs.Root
@@ -198,7 +217,7 @@ func (*Ufs) Walk(req *SrvReq) {
 	}
 
 	if req.Newfid.Aux == nil {
-		req.Newfid.Aux = new(ufsFid)
+		req.Newfid.Aux = &ufsFid{ufs: fid.ufs}
 	}
 
 	nfid := req.Newfid.Aux.(*ufsFid)
@@ -207,7 +226,7 @@ func (*Ufs) Walk(req *SrvReq) {
 	i := 0
 	for ; i < len(tc.Wname); i++ {
 		p := path + "/" + tc.Wname[i]
-		st, err := os.Lstat(p)
+		st, err := fid.ufs.cache().lstat(p)
 		if err != nil {
 			if i == 0 {
 				req.RespondError(Enoent)
@@ -241,6 +260,10 @@ func (*Ufs) Open(req *SrvReq) {
 		return
 	}
 
+	if tc.Mode&OTRUNC != 0 {
+		fid.ufs.cache().invalidate(fid.path, false)
+	}
+
 	req.RespondRopen(dir2Qid(fid.st), 0)
 }
 
@@ -305,6 +328,7 @@ func (*Ufs) Create(req *SrvReq) {
 		return
 	}
 
+	fid.ufs.cache().invalidate(path, false)
 	fid.path = path
 	fid.file = file
 	err = fid.stat()
@@ -347,8 +371,11 @@ func (*Ufs) Read(req *SrvReq) {
 
 			fid.dirents = nil
 			fid.direntends = nil
+			c := fid.ufs.cache()
+			gen := c.generation()
 			for i := 0; i < len(fid.dirs); i++ {
 				path := fid.path + "/" + fid.dirs[i].Name()
+				c.add(path, fid.dirs[i], gen)
 				st, _ := dir2Dir(path, fid.dirs[i], req.Conn.Dotu, req.Conn.Srv.Upool)
 				if st == nil {
 					continue
@@ -389,7 +416,11 @@ func (*Ufs) Read(req *SrvReq) {
 		copy(rc.Data, fid.dirents[tc.Offset:int(tc.Offset)+count])
 
 	} else {
-		count, e = fid.file.ReadAt(rc.Data, int64(tc.Offset))
+		if fid.ufs.ReadAhead > 0 {
+			count, e = fid.readAhead(rc.Data, int64(tc.Offset))
+		} else {
+			count, e = fid.file.ReadAt(rc.Data, int64(tc.Offset))
+		}
 		if e != nil && e != io.EOF {
 			req.RespondError(toError(e))
 			return
@@ -411,6 +442,7 @@ func (*Ufs) Write(req *SrvReq) {
 	}
 
 	n, e := fid.file.WriteAt(tc.Data, int64(tc.Offset))
+	fid.ufs.cache().invalidate(fid.path, false)
 	if e != nil {
 		req.RespondError(toError(e))
 		return
@@ -430,6 +462,7 @@ func (*Ufs) Remove(req *SrvReq) {
 	}
 
 	e := os.Remove(fid.path)
+	fid.ufs.cache().invalidate(fid.path, true)
 	if e != nil {
 		req.RespondError(toError(e))
 		return
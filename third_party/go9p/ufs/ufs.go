@@ -7,13 +7,17 @@ package ufs
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"k8s.io/minikube/third_party/go9p"
 )
 
-func StartServer(addrVal string, debugVal int, rootVal string) {
+func StartServer(addrVal string, debugVal int, rootVal string, statTTL time.Duration, readAhead int) {
 	ufs := new(go9p.Ufs)
 	ufs.Dotu = true
+	ufs.Dotl = true
+	ufs.StatCacheTTL = statTTL
+	ufs.ReadAhead = readAhead
 	ufs.Id = "ufs"
 	ufs.Root = rootVal
 	ufs.Debuglevel = debugVal
@@ -0,0 +1,162 @@
+// Copyright 2009 The go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The most files whose attributes are cached
+const statCacheSize = 8192
+
+type statEntry struct {
+	st      os.FileInfo
+	expires time.Time
+}
+
+// The caches of a Ufs. File attributes are kept for Ufs.StatCacheTTL,
+// or until the file is changed through the server. Every change made
+// through the server also bumps the generation, which invalidates the
+// data read ahead.
+type ufsCache struct {
+	sync.Mutex
+	ttl   time.Duration
+	stats map[string]statEntry
+	gen   uint64
+}
+
+func (ufs *Ufs) cache() *ufsCache {
+	ufs.cacheOnce.Do(func() {
+		ufs.fcache = &ufsCache{ttl: ufs.StatCacheTTL, stats: make(map[string]statEntry)}
+	})
+
+	return ufs.fcache
+}
+
+// Returns the attributes of a file, without following symbolic links.
+func (c *ufsCache) lstat(path string) (os.FileInfo, error) {
+	if c.ttl <= 0 {
+		return os.Lstat(path)
+	}
+
+	c.Lock()
+	e, ok := c.stats[path]
+	gen := c.gen
+	c.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		return e.st, nil
+	}
+
+	st, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.add(path, st, gen)
+	return st, nil
+}
+
+// Caches the attributes of a file, unless a change was made through
+// the server since generation gen, as they may predate the change.
+func (c *ufsCache) add(path string, st os.FileInfo, gen uint64) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+	c.Lock()
+	defer c.Unlock()
+	if c.gen != gen {
+		return
+	}
+
+	if len(c.stats) >= statCacheSize {
+		for p, e := range c.stats {
+			if !now.Before(e.expires) {
+				delete(c.stats, p)
+			}
+		}
+
+		if len(c.stats) >= statCacheSize {
+			c.stats = make(map[string]statEntry)
+		}
+	}
+
+	c.stats[path] = statEntry{st, now.Add(c.ttl)}
+}
+
+// Returns the generation of the cache, which changes with every
+// change made through the server.
+func (c *ufsCache) generation() uint64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.gen
+}
+
+// Forgets the attributes of a file that is changed through the server,
+// and those of its directory, which changes with the entries in it.
+// If tree is true, also forgets the attributes of the files below it,
+// which are renamed or removed with it.
+func (c *ufsCache) invalidate(file string, tree bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.gen++
+	delete(c.stats, file)
+	delete(c.stats, path.Dir(file))
+	if !tree {
+		return
+	}
+
+	prefix := file + "/"
+	for p := range c.stats {
+		if strings.HasPrefix(p, prefix) {
+			delete(c.stats, p)
+		}
+	}
+}
+
+// Reads from the file of the fid through its read-ahead buffer, which
+// is refilled with Ufs.ReadAhead bytes from the offset whenever a read
+// is not within it. The buffer is dropped when anything is changed
+// through the server, or the attributes of the file change.
+func (fid *ufsFid) readAhead(buf []byte, offset int64) (int, error) {
+	c := fid.ufs.cache()
+	gen := c.generation()
+	end := fid.raoff + int64(len(fid.ra))
+	valid := fid.ra != nil && gen == fid.ragen &&
+		fid.st.ModTime().Equal(fid.ramtime) && fid.st.Size() == fid.rasize &&
+		offset >= fid.raoff && (offset+int64(len(buf)) <= end || (fid.raeof && offset <= end))
+
+	if !valid {
+		n := fid.ufs.ReadAhead
+		if len(buf) > n {
+			n = len(buf)
+		}
+
+		if cap(fid.ra) < n {
+			fid.ra = make([]byte, n)
+		}
+
+		m, err := fid.file.ReadAt(fid.ra[:n], offset)
+		if err != nil && err != io.EOF {
+			fid.ra = nil
+			return 0, err
+		}
+
+		fid.ra = fid.ra[:m]
+		fid.raoff = offset
+		fid.raeof = m < n
+		fid.ragen = gen
+		fid.ramtime = fid.st.ModTime()
+		fid.rasize = fid.st.Size()
+	}
+
+	return copy(buf, fid.ra[offset-fid.raoff:]), nil
+}
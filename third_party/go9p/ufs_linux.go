@@ -125,6 +125,9 @@ func (u *Ufs) Wstat(req *SrvReq) {
 		return
 	}
 
+	// whether or not the changes succeed, they may have been made in part
+	defer u.cache().invalidate(fid.path, true)
+
 	dir := &req.Tc.Dir
 	if dir.Mode != 0xFFFFFFFF {
 		mode := dir.Mode & 0777
@@ -194,6 +197,7 @@ func (u *Ufs) Wstat(req *SrvReq) {
 			req.RespondError(toError(err))
 			return
 		}
+		u.cache().invalidate(destpath, true)
 		fid.path = destpath
 	}
 
@@ -231,3 +235,41 @@ func (u *Ufs) Wstat(req *SrvReq) {
 
 	req.RespondRwstat()
 }
+
+func dir2Lattr(d os.FileInfo) *Lattr {
+	attr := newLattr(d)
+	stat, ok := d.Sys().(*syscall.Stat_t)
+	if !ok {
+		return attr
+	}
+
+	attr.Uid = stat.Uid
+	attr.Gid = stat.Gid
+	attr.Nlink = uint64(stat.Nlink)
+	attr.Blksize = uint64(stat.Blksize)
+	attr.Blocks = uint64(stat.Blocks)
+	attr.Rdev = uint64(stat.Rdev)
+	attr.AtimeSec, attr.AtimeNsec = uint64(stat.Atim.Sec), uint64(stat.Atim.Nsec)
+	attr.CtimeSec, attr.CtimeNsec = uint64(stat.Ctim.Sec), uint64(stat.Ctim.Nsec)
+
+	return attr
+}
+
+func statfs(path string) (*Lstatfs, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return nil, err
+	}
+
+	return &Lstatfs{
+		Type:    v9fsMagic,
+		Bsize:   uint32(st.Bsize),
+		Blocks:  uint64(st.Blocks),
+		Bfree:   uint64(st.Bfree),
+		Bavail:  uint64(st.Bavail),
+		Files:   uint64(st.Files),
+		Ffree:   uint64(st.Ffree),
+		Fsid:    uint64(uint32(st.Fsid.X__val[0])) | uint64(uint32(st.Fsid.X__val[1]))<<32,
+		Namelen: uint32(st.Namelen),
+	}, nil
+}
@@ -141,6 +141,9 @@ func (u *Ufs) Wstat(req *SrvReq) {
 		return
 	}
 
+	// whether or not the changes succeed, they may have been made in part
+	defer u.cache().invalidate(fid.path, true)
+
 	dir := &req.Tc.Dir
 	if dir.Mode != 0xFFFFFFFF {
 		mode := dir.Mode & 0777
@@ -210,6 +213,7 @@ func (u *Ufs) Wstat(req *SrvReq) {
 			req.RespondError(toError(err))
 			return
 		}
+		u.cache().invalidate(destpath, true)
 		fid.path = destpath
 	}
 
@@ -247,3 +251,26 @@ func (u *Ufs) Wstat(req *SrvReq) {
 
 	req.RespondRwstat()
 }
+
+func dir2Lattr(d os.FileInfo) *Lattr {
+	attr := newLattr(d)
+	data, ok := d.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return attr
+	}
+
+	atime := data.LastAccessTime.Nanoseconds()
+	attr.AtimeSec, attr.AtimeNsec = uint64(atime/1e9), uint64(atime%1e9)
+	btime := data.CreationTime.Nanoseconds()
+	attr.BtimeSec, attr.BtimeNsec = uint64(btime/1e9), uint64(btime%1e9)
+	return attr
+}
+
+// Windows has no statfs(2), so the space of the file system is not reported
+func statfs(path string) (*Lstatfs, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	return &Lstatfs{Type: v9fsMagic, Bsize: 4096, Namelen: 255}, nil
+}
@@ -0,0 +1,473 @@
+// Copyright 2009 The go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+// File types in the modes of 9P2000.L, which are those of Linux
+const (
+	lmodeFIFO   = 0010000
+	lmodeChar   = 0020000
+	lmodeDir    = 0040000
+	lmodeBlock  = 0060000
+	lmodeFile   = 0100000
+	lmodeLink   = 0120000
+	lmodeSocket = 0140000
+	lmodeSetuid = 04000
+	lmodeSetgid = 02000
+	lmodeSticky = 01000
+)
+
+// The type field of Rstatfs, which is the one of v9fs on Linux
+const v9fsMagic = 0x01021997
+
+func dir2Lmode(d os.FileInfo) uint32 {
+	mode := d.Mode()
+	ret := uint32(mode.Perm())
+	switch {
+	case mode&os.ModeDir != 0:
+		ret |= lmodeDir
+	case mode&os.ModeSymlink != 0:
+		ret |= lmodeLink
+	case mode&os.ModeNamedPipe != 0:
+		ret |= lmodeFIFO
+	case mode&os.ModeSocket != 0:
+		ret |= lmodeSocket
+	case mode&os.ModeCharDevice != 0:
+		ret |= lmodeChar
+	case mode&os.ModeDevice != 0:
+		ret |= lmodeBlock
+	default:
+		ret |= lmodeFile
+	}
+
+	if mode&os.ModeSetuid != 0 {
+		ret |= lmodeSetuid
+	}
+
+	if mode&os.ModeSetgid != 0 {
+		ret |= lmodeSetgid
+	}
+
+	if mode&os.ModeSticky != 0 {
+		ret |= lmodeSticky
+	}
+
+	return ret
+}
+
+func lmode2Mode(lmode uint32) os.FileMode {
+	mode := os.FileMode(lmode & 0777)
+	if lmode&lmodeSetuid != 0 {
+		mode |= os.ModeSetuid
+	}
+
+	if lmode&lmodeSetgid != 0 {
+		mode |= os.ModeSetgid
+	}
+
+	if lmode&lmodeSticky != 0 {
+		mode |= os.ModeSticky
+	}
+
+	return mode
+}
+
+func lflags2uflags(flags uint32) int {
+	ret := omode2uflags(uint8(flags & 3))
+	if flags&LOCREATE != 0 {
+		ret |= os.O_CREATE
+	}
+
+	if flags&LOEXCL != 0 {
+		ret |= os.O_EXCL
+	}
+
+	if flags&LOTRUNC != 0 {
+		ret |= os.O_TRUNC
+	}
+
+	if flags&LOAPPEND != 0 {
+		ret |= os.O_APPEND
+	}
+
+	return ret
+}
+
+// Returns the attributes of a file that need no system specific
+// information. dir2Lattr adds the rest.
+func newLattr(d os.FileInfo) *Lattr {
+	attr := new(Lattr)
+	attr.Valid = GETATTRBASIC
+	attr.Qid = *dir2Qid(d)
+	attr.Mode = dir2Lmode(d)
+	attr.Nlink = 1
+	attr.Size = uint64(d.Size())
+	attr.Blksize = 4096
+	attr.Blocks = (attr.Size + 511) / 512
+	mtime := d.ModTime()
+	attr.MtimeSec = uint64(mtime.Unix())
+	attr.MtimeNsec = uint64(mtime.Nanosecond())
+	attr.AtimeSec, attr.AtimeNsec = attr.MtimeSec, attr.MtimeNsec
+	attr.CtimeSec, attr.CtimeNsec = attr.MtimeSec, attr.MtimeNsec
+	return attr
+}
+
+// Creates a file in the directory of the fid. The file is created by
+// the create function, and the function returns its attributes.
+func (fid *ufsFid) lcreate(name string, create func(path string) error) (os.FileInfo, error) {
+	path := fid.path + "/" + name
+	err := create(path)
+	fid.ufs.cache().invalidate(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Lstat(path)
+}
+
+func (*Ufs) Statfs(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	st, err := statfs(fid.path)
+	if err != nil {
+		req.RespondError(toError(err))
+		return
+	}
+
+	req.RespondRstatfs(st)
+}
+
+func (*Ufs) Lopen(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	tc := req.Tc
+	err := fid.stat()
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	var e error
+	fid.file, e = os.OpenFile(fid.path, lflags2uflags(tc.Lflags&^(LOCREATE|LOEXCL)), 0)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	if tc.Lflags&LOTRUNC != 0 {
+		fid.ufs.cache().invalidate(fid.path, false)
+	}
+
+	req.RespondRlopen(dir2Qid(fid.st), 0)
+}
+
+func (*Ufs) Lcreate(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	tc := req.Tc
+	var file *os.File
+	st, e := fid.lcreate(tc.Name, func(path string) (err error) {
+		file, err = os.OpenFile(path, lflags2uflags(tc.Lflags)|os.O_CREATE, lmode2Mode(tc.Lmode))
+		return err
+	})
+	if e != nil {
+		if file != nil {
+			file.Close()
+		}
+		req.RespondError(toError(e))
+		return
+	}
+
+	fid.path = fid.path + "/" + tc.Name
+	fid.file = file
+	fid.st = st
+	req.RespondRlcreate(dir2Qid(st), 0)
+}
+
+func (*Ufs) Symlink(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	tc := req.Tc
+	st, e := fid.lcreate(tc.Name, func(path string) error {
+		return os.Symlink(tc.Target, path)
+	})
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRsymlink(dir2Qid(st))
+}
+
+func (*Ufs) Mkdir(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	tc := req.Tc
+	st, e := fid.lcreate(tc.Name, func(path string) error {
+		return os.Mkdir(path, lmode2Mode(tc.Lmode))
+	})
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRmkdir(dir2Qid(st))
+}
+
+func (*Ufs) Link(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	dfid := req.Dfid.Aux.(*ufsFid)
+	_, e := dfid.lcreate(req.Tc.Name, func(path string) error {
+		return os.Link(fid.path, path)
+	})
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	fid.ufs.cache().invalidate(fid.path, false)
+	req.RespondRlink()
+}
+
+// Renames a file, and forgets what is cached about it and the files below it.
+func (ufs *Ufs) rename(from, to string) error {
+	err := os.Rename(from, to)
+	c := ufs.cache()
+	c.invalidate(from, true)
+	c.invalidate(to, true)
+	return err
+}
+
+func (u *Ufs) Rename(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	dfid := req.Dfid.Aux.(*ufsFid)
+	to := dfid.path + "/" + req.Tc.Name
+	if e := u.rename(fid.path, to); e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	fid.path = to
+	req.RespondRrename()
+}
+
+func (u *Ufs) Renameat(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	dfid := req.Dfid.Aux.(*ufsFid)
+	tc := req.Tc
+	if e := u.rename(fid.path+"/"+tc.Name, dfid.path+"/"+tc.Newname); e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRrenameat()
+}
+
+func (*Ufs) Unlinkat(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	tc := req.Tc
+	path := fid.path + "/" + tc.Name
+	st, e := os.Lstat(path)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	// unlink(2) does not remove directories, and rmdir(2) removes nothing else
+	switch {
+	case st.IsDir() && tc.Lflags&ATREMOVEDIR == 0:
+		req.RespondError(&Error{"is a directory", EISDIR})
+		return
+	case !st.IsDir() && tc.Lflags&ATREMOVEDIR != 0:
+		req.RespondError(Enotdir)
+		return
+	}
+
+	e = os.Remove(path)
+	fid.ufs.cache().invalidate(path, true)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRunlinkat()
+}
+
+func (*Ufs) Readlink(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	target, e := os.Readlink(fid.path)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRreadlink(target)
+}
+
+func (*Ufs) Getattr(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	err := fid.stat()
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	req.RespondRgetattr(dir2Lattr(fid.st))
+}
+
+func (*Ufs) Setattr(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	err := fid.stat()
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	// whether or not the changes succeed, they may have been made in part
+	defer fid.ufs.cache().invalidate(fid.path, false)
+
+	a := &req.Tc.Lsetattr
+	if a.Valid&ATTRMODE != 0 {
+		if e := os.Chmod(fid.path, lmode2Mode(a.Mode)); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	if a.Valid&(ATTRUID|ATTRGID) != 0 {
+		uid, gid := -1, -1
+		if a.Valid&ATTRUID != 0 {
+			uid = int(a.Uid)
+		}
+		if a.Valid&ATTRGID != 0 {
+			gid = int(a.Gid)
+		}
+		if e := os.Lchown(fid.path, uid, gid); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	if a.Valid&ATTRSIZE != 0 {
+		if e := os.Truncate(fid.path, int64(a.Size)); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	// If either mtime or atime need to be changed, then
+	// we must change both.
+	if a.Valid&(ATTRATIME|ATTRMTIME) != 0 {
+		now := time.Now()
+		old := dir2Lattr(fid.st)
+		at := time.Unix(int64(old.AtimeSec), int64(old.AtimeNsec))
+		mt := fid.st.ModTime()
+		switch {
+		case a.Valid&ATTRATIMESET != 0:
+			at = time.Unix(int64(a.AtimeSec), int64(a.AtimeNsec))
+		case a.Valid&ATTRATIME != 0:
+			at = now
+		}
+		switch {
+		case a.Valid&ATTRMTIMESET != 0:
+			mt = time.Unix(int64(a.MtimeSec), int64(a.MtimeNsec))
+		case a.Valid&ATTRMTIME != 0:
+			mt = now
+		}
+		if e := os.Chtimes(fid.path, at, mt); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	req.RespondRsetattr()
+}
+
+func (*Ufs) Readdir(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	tc := req.Tc
+	rc := req.Rc
+	c := fid.ufs.cache()
+	if tc.Offset == 0 || fid.dirs == nil {
+		// Reopen the directory to see its current entries
+		gen := c.generation()
+		dir, e := os.Open(fid.path)
+		if e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+
+		dirs, e := dir.Readdir(-1)
+		dir.Close()
+		if e != nil && e != io.EOF {
+			req.RespondError(toError(e))
+			return
+		}
+
+		dot, e := os.Lstat(fid.path)
+		if e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+
+		dotdot, e := os.Lstat(path.Dir(fid.path))
+		if e != nil {
+			dotdot = dot
+		}
+
+		fid.dirs = append([]os.FileInfo{dot, dotdot}, dirs...)
+		for _, d := range dirs {
+			c.add(fid.path+"/"+d.Name(), d, gen)
+		}
+	}
+
+	InitRreaddir(rc, tc.Count)
+	buf := rc.Data
+	count := 0
+	start := len(fid.dirs)
+	if tc.Offset < uint64(start) {
+		start = int(tc.Offset)
+	}
+
+	for i := start; i < len(fid.dirs); i++ {
+		d := fid.dirs[i]
+		name := d.Name()
+		switch i {
+		case 0:
+			name = "."
+		case 1:
+			name = ".."
+		}
+
+		sz := LdirentSize(name)
+		if count+sz > len(buf) {
+			break
+		}
+
+		PackLdirent(buf[count:], dir2Qid(d), uint64(i+1), uint8(dir2Lmode(d)>>12), name)
+		count += sz
+	}
+
+	if count == 0 && start < len(fid.dirs) {
+		req.RespondError(&Error{"too small read size for dir entry", EINVAL})
+		return
+	}
+
+	SetRreadCount(rc, uint32(count))
+	req.Respond()
+}
+
+func (*Ufs) Fsync(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	if fid.file != nil {
+		if e := fid.file.Sync(); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	req.RespondRfsync()
+}
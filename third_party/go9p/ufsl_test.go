@@ -0,0 +1,265 @@
+package go9p
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ufsClient speaks 9P2000.L to a Ufs over a pipe
+type ufsClient struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+func newUfsClient(t *testing.T, root string) *ufsClient {
+	ufs := new(Ufs)
+	ufs.Id = "test"
+	ufs.Root = root
+	ufs.Dotu = true
+	ufs.Dotl = true
+	ufs.Start(ufs)
+
+	c, s := net.Pipe()
+	ufs.NewConn(s)
+	cl := &ufsClient{t, c}
+
+	fc := NewFcall(MSIZE)
+	PackTversion(fc, MSIZE, "9P2000.L")
+	if rc := cl.rpc(fc, Rversion); rc.Version != "9P2000.L" {
+		t.Fatalf("version = %q, want 9P2000.L", rc.Version)
+	}
+
+	PackTattach(fc, 1, NOFID, "", "", uint32(os.Getuid()), true)
+	cl.rpc(fc, Rattach)
+	return cl
+}
+
+// Sends a message, and returns the response, which must be of type want
+func (cl *ufsClient) rpc(fc *Fcall, want uint8) *Fcall {
+	cl.t.Helper()
+	if fc.Type != Tversion {
+		SetTag(fc, 1)
+	}
+	if _, err := cl.conn.Write(fc.Pkt); err != nil {
+		cl.t.Fatalf("write: %v", err)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(cl.conn, buf); err != nil {
+		cl.t.Fatalf("read: %v", err)
+	}
+
+	sz, _ := gint32(buf)
+	buf = append(buf, make([]byte, sz-4)...)
+	if _, err := io.ReadFull(cl.conn, buf[4:]); err != nil {
+		cl.t.Fatalf("read: %v", err)
+	}
+
+	typ := buf[4]
+	if typ != want {
+		ecode, _ := gint32(buf[7:])
+		cl.t.Fatalf("response type = %d (ecode %d), want %d", typ, ecode, want)
+	}
+
+	rc := &Fcall{Type: typ, Pkt: buf}
+	p := buf[7:]
+	switch typ {
+	case Rversion:
+		rc.Msize, p = gint32(p)
+		rc.Version, _ = gstr(p)
+	case Rlerror:
+		rc.Errornum, _ = gint32(p)
+	case Rgetattr:
+		rc.Lattr.Valid, p = gint64(p)
+		p = gqid(p, &rc.Lattr.Qid)
+		rc.Lattr.Mode, p = gint32(p)
+		p = p[8:] /* uid[4] gid[4] */
+		rc.Lattr.Nlink, p = gint64(p)
+		p = p[8:] /* rdev[8] */
+		rc.Lattr.Size, _ = gint64(p)
+	case Rreaddir, Rread:
+		rc.Count, p = gint32(p)
+		rc.Data = p
+	}
+
+	return rc
+}
+
+// Packs a 9P2000.L message with fields of type uint8, uint32, uint64 and string
+func packL(typ uint8, fields ...interface{}) *Fcall {
+	size := 0
+	for _, f := range fields {
+		switch v := f.(type) {
+		case uint8:
+			size++
+		case uint32:
+			size += 4
+		case uint64:
+			size += 8
+		case string:
+			size += 2 + len(v)
+		}
+	}
+
+	fc := NewFcall(MSIZE)
+	p, _ := packCommon(fc, size, typ)
+	for _, f := range fields {
+		switch v := f.(type) {
+		case uint8:
+			p = pint8(v, p)
+		case uint32:
+			p = pint32(v, p)
+		case uint64:
+			p = pint64(v, p)
+		case string:
+			p = pstr(v, p)
+		}
+	}
+
+	return fc
+}
+
+func TestUfsDotl(t *testing.T) {
+	root, err := ioutil.TempDir("", "ufs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	cl := newUfsClient(t, root)
+	defer cl.conn.Close()
+
+	// mkdir dir; create dir/file and write to it
+	cl.rpc(packL(Tmkdir, uint32(1), "dir", uint32(0755), uint32(0)), Rmkdir)
+	fc := NewFcall(MSIZE)
+	PackTwalk(fc, 1, 2, []string{"dir"})
+	cl.rpc(fc, Rwalk)
+	cl.rpc(packL(Tlcreate, uint32(2), "file", uint32(LORDWR), uint32(0644), uint32(0)), Rlcreate)
+	PackTwrite(fc, 2, 0, 5, []byte("hello"))
+	cl.rpc(fc, Rwrite)
+
+	rc := cl.rpc(packL(Tgetattr, uint32(2), uint64(GETATTRBASIC)), Rgetattr)
+	if rc.Lattr.Size != 5 || rc.Lattr.Mode != lmodeFile|0644 {
+		t.Errorf("getattr = size %d mode %o, want size 5 mode %o", rc.Lattr.Size, rc.Lattr.Mode, lmodeFile|0644)
+	}
+
+	// truncate through setattr
+	cl.rpc(packL(Tsetattr, uint32(2), uint32(ATTRSIZE), uint32(0), uint32(0), uint32(0),
+		uint64(2), uint64(0), uint64(0), uint64(0), uint64(0)), Rsetattr)
+	if data, _ := ioutil.ReadFile(filepath.Join(root, "dir", "file")); string(data) != "he" {
+		t.Errorf("file = %q after setattr, want \"he\"", data)
+	}
+
+	// list dir
+	PackTwalk(fc, 1, 3, []string{"dir"})
+	cl.rpc(fc, Rwalk)
+	cl.rpc(packL(Tlopen, uint32(3), uint32(LOREAD)), Rlopen)
+	rc = cl.rpc(packL(Treaddir, uint32(3), uint64(0), uint32(4096)), Rreaddir)
+	var names []string
+	for p := rc.Data; len(p) > 0; {
+		var qid Qid
+		var name string
+		p = gqid(p, &qid)
+		_, p = gint64(p)
+		_, p = gint8(p)
+		name, p = gstr(p)
+		names = append(names, name)
+	}
+	if len(names) != 3 || names[0] != "." || names[1] != ".." || names[2] != "file" {
+		t.Errorf("readdir = %v, want [. .. file]", names)
+	}
+
+	// rename dir/file to moved, then remove it
+	cl.rpc(packL(Trenameat, uint32(3), "file", uint32(1), "moved"), Rrenameat)
+	if _, err := os.Stat(filepath.Join(root, "moved")); err != nil {
+		t.Errorf("renameat: %v", err)
+	}
+	rc = cl.rpc(packL(Tunlinkat, uint32(1), "moved", uint32(ATREMOVEDIR)), Rlerror)
+	if rc.Errornum != ENOTDIR {
+		t.Errorf("unlinkat of a file as a directory = %d, want %d", rc.Errornum, ENOTDIR)
+	}
+	cl.rpc(packL(Tunlinkat, uint32(1), "moved", uint32(0)), Runlinkat)
+	rc = cl.rpc(packL(Tunlinkat, uint32(1), "moved", uint32(0)), Rlerror)
+	if rc.Errornum != ENOENT {
+		t.Errorf("unlinkat of a missing file = %d, want %d", rc.Errornum, ENOENT)
+	}
+
+	// extended attributes are not supported
+	rc = cl.rpc(packL(Txattrwalk, uint32(1), uint32(4), "user.x"), Rlerror)
+	if rc.Errornum != EOPNOTSUPP {
+		t.Errorf("xattrwalk = %d, want %d", rc.Errornum, EOPNOTSUPP)
+	}
+}
+
+func TestUfsCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ufs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ufs := &Ufs{StatCacheTTL: time.Hour, ReadAhead: 4}
+	c := ufs.cache()
+	st, err := c.lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// changes made behind the server are seen once the attributes expire, ...
+	if err := ioutil.WriteFile(path, []byte("abcdefghijklmnopqrst"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ = c.lstat(path); st.Size() != 10 {
+		t.Errorf("cached size = %d, want 10", st.Size())
+	}
+
+	// ... and those made through it immediately
+	c.invalidate(dir, true)
+	if st, _ = c.lstat(path); st.Size() != 20 {
+		t.Errorf("size after invalidation = %d, want 20", st.Size())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	fid := &ufsFid{ufs: ufs, path: path, file: file, st: st}
+	buf := make([]byte, 2)
+	for _, tc := range []struct {
+		offset int64
+		want   string
+		raoff  int64
+	}{
+		{0, "ab", 0},
+		{2, "cd", 0},   // within the buffer
+		{3, "de", 3},   // past its end, refilled
+		{18, "st", 18}, // at the end of the file
+		{19, "t", 18},
+	} {
+		n, err := fid.readAhead(buf, tc.offset)
+		if err != nil {
+			t.Fatalf("readAhead(%d): %v", tc.offset, err)
+		}
+		if got := string(buf[:n]); got != tc.want || fid.raoff != tc.raoff {
+			t.Errorf("readAhead(%d) = %q from buffer at %d, want %q from buffer at %d", tc.offset, got, fid.raoff, tc.want, tc.raoff)
+		}
+	}
+
+	// changes through the server drop the buffer
+	c.invalidate(path, false)
+	if _, err := fid.readAhead(buf, 19); err != nil || fid.raoff != 19 {
+		t.Errorf("readAhead after invalidation read from %d (%v), want 19", fid.raoff, err)
+	}
+}